@@ -5,12 +5,21 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"runtime"
 	"strings"
+	"time"
 
 	"github.com/cloudyali/terratag/internal/common"
+	"github.com/cloudyali/terratag/internal/selector"
+)
+
+const (
+	CommandRevert   = "revert"   // Restore .bak originals and delete generated .terratag.tf files
+	CommandFinalize = "finalize" // Fold .terratag.tf files into the original filenames and delete backups
 )
 
 type Args struct {
+	Command             string // Optional lifecycle command (revert, finalize), empty for tagging/validation
 	TagsFile            string // Path to tag standard file
 	Dir                 string
 	Filter              string
@@ -33,6 +42,27 @@ type Args struct {
 	APIServerMode       bool   // Hidden flag for API server mode
 	NoProviderCache     bool   // Disable centralized provider cache
 	AutoInit            bool   // Automatically run terraform init if needed
+	DryRun              bool   // Compute tagging changes and print diffs without writing files
+	DiffFormat          string // Output format for dry-run diffs (unified, json)
+	FailOnDiff          bool   // Exit with an error when a dry run would change any file
+	Strategy            string // Where tags are written: resource (every resource) or provider (default_tags/default_labels)
+	GenerateVariables   bool   // Reference generated Terraform variables for required tags without a value instead of placeholders
+	Parallelism         int    // Maximum number of files processed concurrently, 0 for the number of CPUs
+	TaggingReport       string // Output path of the JSON tagging report, "-" for stdout
+	InitTimeout         time.Duration // Timeout of terraform init, 0 to disable
+	ValidateTimeout     time.Duration // Timeout of the terraform validate run by -auto-init, 0 to disable
+	SchemaTimeout       time.Duration // Timeout of terraform providers schema -json, 0 to disable
+	Migrate             string // Path to a tag key mapping file, renames and removes existing tag keys instead of tagging
+	TraceTags           bool   // Add per resource traceability tags computed from the file, the resource address and git
+}
+
+// CommandTimeouts returns the timeouts of the terraform, tofu and terragrunt subprocesses
+func (args Args) CommandTimeouts() common.CommandTimeouts {
+	return common.CommandTimeouts{
+		Init:     args.InitTimeout,
+		Validate: args.ValidateTimeout,
+		Schema:   args.SchemaTimeout,
+	}
 }
 
 func validate(args Args) error {
@@ -41,8 +71,29 @@ func validate(args Args) error {
 		return nil
 	}
 	
-	// In validation-only mode, tags file is not required
-	if !args.ValidateOnly && args.TagsFile == "" {
+	if args.Command != "" && args.Command != CommandRevert && args.Command != CommandFinalize {
+		return fmt.Errorf("invalid command %s, must be either 'revert' or 'finalize'", args.Command)
+	}
+
+	// Lifecycle commands only operate on files left behind by a previous run
+	if args.Command != "" {
+		if args.ValidateOnly || args.DryRun {
+			return fmt.Errorf("the %s command cannot be combined with -validate-only or -dry-run", args.Command)
+		}
+
+		if args.Migrate != "" {
+			return fmt.Errorf("the %s command cannot be combined with -migrate", args.Command)
+		}
+
+		return nil
+	}
+
+	if args.Migrate != "" && args.ValidateOnly {
+		return errors.New("-migrate cannot be combined with -validate-only")
+	}
+
+	// In validation-only and migration mode, tags file is not required
+	if !args.ValidateOnly && args.Migrate == "" && args.TagsFile == "" {
 		return errors.New("missing tags file - please provide a tag standardization file using -tags")
 	}
 
@@ -55,14 +106,55 @@ func validate(args Args) error {
 		return fmt.Errorf("invalid type %s, must be either 'terraform', 'terragrunt', or 'terragrunt-run-all'", args.Type)
 	}
 
+	if _, err := selector.Parse(args.Filter, args.Skip); err != nil {
+		return err
+	}
+
 	// Validate report format
 	if args.ReportFormat != "" {
-		validFormats := map[string]bool{"json": true, "yaml": true, "table": true, "markdown": true}
+		validFormats := map[string]bool{"json": true, "yaml": true, "table": true, "markdown": true, "sarif": true, "junit": true, "html": true}
 		if !validFormats[args.ReportFormat] {
-			return fmt.Errorf("invalid report format %s, must be one of: json, yaml, table, markdown", args.ReportFormat)
+			return fmt.Errorf("invalid report format %s, must be one of: json, yaml, table, markdown, sarif, junit, html", args.ReportFormat)
 		}
 	}
 
+	// Validate diff format
+	if args.DiffFormat != "" {
+		validDiffFormats := map[string]bool{"unified": true, "json": true}
+		if !validDiffFormats[args.DiffFormat] {
+			return fmt.Errorf("invalid diff format %s, must be one of: unified, json", args.DiffFormat)
+		}
+	}
+
+	if args.Strategy != "" && args.Strategy != string(common.StrategyResource) && args.Strategy != string(common.StrategyProvider) {
+		return fmt.Errorf("invalid strategy %s, must be either 'resource' or 'provider'", args.Strategy)
+	}
+
+	if args.Parallelism < 0 {
+		return fmt.Errorf("invalid parallelism %d, must not be negative", args.Parallelism)
+	}
+
+	if args.InitTimeout < 0 || args.ValidateTimeout < 0 || args.SchemaTimeout < 0 {
+		return errors.New("subprocess timeouts must not be negative")
+	}
+
+	if args.AutoFix && !args.ValidateOnly {
+		return errors.New("-auto-fix can only be used together with -validate-only")
+	}
+
+	if args.TraceTags && args.Strategy == string(common.StrategyProvider) {
+		return errors.New("-trace-tags can't be used together with -strategy=provider, trace tags are specific to every resource")
+	}
+
+	if args.FailOnDiff && !args.DryRun {
+		return errors.New("-fail-on-diff can only be used together with -dry-run")
+	}
+
+	// Both would be written to stdout and couldn't be parsed
+	if args.DryRun && args.TaggingReport == "-" {
+		return errors.New("-tagging-report can't be written to stdout together with -dry-run, which prints the diffs to stdout, write it to a file instead")
+	}
+
 	return nil
 }
 
@@ -71,13 +163,19 @@ func InitArgs() (Args, error) {
 	programName := os.Args[0]
 	programArgs := os.Args[1:]
 
+	// Lifecycle commands are given as the first argument, e.g. terratag revert -dir=.
+	if len(programArgs) > 0 && !strings.HasPrefix(programArgs[0], "-") {
+		args.Command = programArgs[0]
+		programArgs = programArgs[1:]
+	}
+
 	fs := flag.NewFlagSet(programName, flag.ExitOnError)
 
 	fs.StringVar(&args.TagsFile, "tags", "", "Path to tag standardization YAML file containing tags to apply to resources. File should define tags with their values (e.g., tags: {\"Environment\":\"prod\",\"Team\":\"platform\"}). Not required when using -validate-only mode.")
 	fs.StringVar(&args.Dir, "dir", ".", "Directory to recursively search for .tf files. Supports both regular tagging and validation modes.")
 	fs.BoolVar(&args.IsSkipTerratagFiles, "skipTerratagFiles", true, "Skips any previously tagged files ending with .terratag.tf to avoid double-processing")
-	fs.StringVar(&args.Filter, "filter", ".*", "Only apply tags to the selected resource types (regex pattern, e.g., 'aws_instance|aws_s3_bucket')")
-	fs.StringVar(&args.Skip, "skip", "", "Exclude the selected resource types from tagging (regex pattern, e.g., 'aws_iam_.*')")
+	fs.StringVar(&args.Filter, "filter", ".*", "Only apply tags to the resources matching every whitespace separated selector: a resource type regex (e.g., 'aws_instance|aws_s3_bucket'), or 'name:<regex>', 'address:<regex>', 'module:<module address>' or 'file:<glob>'")
+	fs.StringVar(&args.Skip, "skip", "", "Exclude the resources matching any whitespace separated selector from tagging: a resource type regex (e.g., 'aws_iam_.*'), or 'name:<regex>', 'address:<regex>', 'module:<module address>' or 'file:<glob>'")
 	fs.BoolVar(&args.Verbose, "verbose", false, "Enable verbose logging for detailed operation information including tag extraction and validation details")
 	fs.BoolVar(&args.Rename, "rename", true, "Keep the original filename or replace it with <basename>.terratag.tf (applies to tagging mode only)")
 	fs.StringVar(&args.Type, "type", string(common.Terraform), "The IAC type. Valid values: terraform (standard .tf files), terragrunt (with .hcl files), or terragrunt-run-all")
@@ -88,13 +186,25 @@ func InitArgs() (Args, error) {
 	// Tag standardization and validation flags
 	fs.BoolVar(&args.ValidateOnly, "validate-only", false, "Only validate tags against a standard without applying changes. Analyzes existing tags for compliance, missing required tags, format violations, and AWS resource tagging support.")
 	fs.StringVar(&args.StandardFile, "standard", "", "Path to tag standardization YAML file defining required/optional tags, validation rules, data types, patterns, and allowed values. Required when using -validate-only.")
-	fs.StringVar(&args.ReportFormat, "report-format", "table", "Report format for validation results. Options: 'json' (machine readable), 'yaml' (structured), 'table' (human readable), 'markdown' (documentation). Includes compliance rates, AWS tagging support analysis, and violation summaries.")
+	fs.StringVar(&args.ReportFormat, "report-format", "table", "Report format for validation results. Options: 'json' (machine readable), 'yaml' (structured), 'table' (human readable), 'markdown' (documentation), 'sarif' (code scanning), 'junit' (CI test results), 'html' (self-contained page). Includes compliance rates, AWS tagging support analysis, and violation summaries.")
 	fs.StringVar(&args.ReportOutput, "report-output", "", "Output file path for validation report. If empty or '-', outputs to stdout. Useful for CI/CD pipelines and automated compliance checking.")
 	fs.BoolVar(&args.StrictMode, "strict-mode", false, "Fail validation with non-zero exit code on any violation (strict compliance mode). Default behavior shows warnings but exits successfully.")
-	fs.BoolVar(&args.AutoFix, "auto-fix", false, "Attempt to automatically fix violations when possible (future feature). Currently validates and suggests fixes without modifying files.")
+	fs.BoolVar(&args.AutoFix, "auto-fix", false, "Together with -validate-only, apply the suggested fixes to the literal tag keys and values of the source files and re-validate them. Values set by variables, locals or other expressions are reported as fixes to apply manually.")
 	fs.StringVar(&args.PlanFile, "plan", "", "Path to terraform plan JSON file (from 'terraform show -json plan.tfplan') for accurate variable resolution. When provided, uses resolved values from terraform plan instead of custom variable parsing.")
 	fs.BoolVar(&args.NoProviderCache, "no-provider-cache", false, "Disable centralized provider caching. Use this flag to force fresh provider downloads for each directory (may increase storage usage).")
 	fs.BoolVar(&args.AutoInit, "auto-init", false, "Automatically run terraform init if needed. When enabled, terratag will detect initialization errors and automatically run the appropriate init commands.")
+	fs.BoolVar(&args.DryRun, "dry-run", false, "Compute the tagged HCL and print a diff per file instead of writing .terratag.tf and .bak files (applies to tagging mode only).")
+	fs.StringVar(&args.DiffFormat, "diff-format", "unified", "Output format for dry-run diffs. Options: 'unified' (patch style), 'json' (machine readable list of per-file diffs).")
+	fs.StringVar(&args.Strategy, "strategy", string(common.StrategyResource), "Tagging strategy. 'resource' merges tags into every taggable resource, 'provider' writes them once into provider \"aws\" default_tags and provider \"google\" default_labels (creating the provider block if missing). Resources of other providers are still tagged individually.")
+	fs.BoolVar(&args.FailOnDiff, "fail-on-diff", false, "Exit with a non-zero code when a dry run would change any file. Useful for CI checks that code is already tagged.")
+	fs.IntVar(&args.Parallelism, "parallelism", runtime.NumCPU(), "Maximum number of files processed concurrently when tagging or validating. Lower it to limit memory and open file handles on large repositories. 0 uses the number of CPUs.")
+	fs.StringVar(&args.TaggingReport, "tagging-report", "", "Write a JSON report of the outcome of every file and resource (tagged, skipped, not_taggable, filtered or error, with the reason, tag attribute and line) to this path, or to stdout for '-' (not together with -dry-run, which prints the diffs to stdout).")
+	fs.DurationVar(&args.InitTimeout, "init-timeout", 10*time.Minute, "Kill terraform/tofu/terragrunt init when it runs longer than this duration (e.g. 90s, 15m). 0 disables the timeout.")
+	fs.DurationVar(&args.ValidateTimeout, "validate-timeout", 5*time.Minute, "Kill the terraform/tofu/terragrunt validate run by -auto-init when it runs longer than this duration. 0 disables the timeout.")
+	fs.DurationVar(&args.SchemaTimeout, "schema-timeout", 5*time.Minute, "Kill terraform/tofu/terragrunt providers schema -json when it runs longer than this duration. 0 disables the timeout.")
+	fs.StringVar(&args.Migrate, "migrate", "", "Path to a YAML tag key mapping file ('rename' map of old to new keys, 'remove' list of keys). Rewrites the literal keys of existing tags/labels maps, provider default tags, terratag_found_* locals and aws_autoscaling_group tag blocks in place instead of tagging, and reports computed tags that can't be rewritten. Combine with -dry-run to preview the changes.")
+	fs.BoolVar(&args.GenerateVariables, "generate-variables", false, "For required tags without a default_value, examples or allowed_values, generate a terratag_variables.tf with a validated variable \"terratag_<key>\" per tag and reference it instead of writing a CONFIGURE_<KEY>_VALUE placeholder. An example terratag.example.tfvars is generated in -dir.")
+	fs.BoolVar(&args.TraceTags, "trace-tags", false, "Add traceability tags to every tagged resource: terratag_file, terratag_resource_address and, from the local git repository, git_repo, git_commit, git_last_modified_by and git_last_modified_at of the last commit that modified the resource block.")
 	
 	// Hidden flag for API server mode - not shown in help
 	fs.BoolVar(&args.APIServerMode, "api-server", false, "")
@@ -106,10 +216,6 @@ func InitArgs() (Args, error) {
 		if f.Name == "version" {
 			return
 		}
-		// Skip auto-fix as it's marked as future feature
-		if f.Name == "auto-fix" {
-			return
-		}
 
 		name := "TERRATAG_" + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
 		if value, ok := os.LookupEnv(name); ok {
@@ -81,7 +81,17 @@ func TestValidate(t *testing.T) {
 				ReportFormat: "invalid",
 			},
 			wantErr: true,
-			errMsg:  "invalid report format invalid, must be one of: json, yaml, table, markdown",
+			errMsg:  "invalid report format invalid, must be one of: json, yaml, table, markdown, sarif, junit, html",
+		},
+		{
+			name: "invalid skip selector",
+			args: Args{
+				TagsFile: "test-tags.yaml",
+				Type:     "terraform",
+				Skip:     "aws_iam_.* module:network",
+			},
+			wantErr: true,
+			errMsg:  `invalid -skip: selector "module:network" must be a module address, e.g. module:module.network`,
 		},
 		{
 			name: "valid report format json",
@@ -119,6 +129,33 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid report format sarif",
+			args: Args{
+				TagsFile:     "test-tags.yaml",
+				Type:         "terraform",
+				ReportFormat: "sarif",
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid report format junit",
+			args: Args{
+				TagsFile:     "test-tags.yaml",
+				Type:         "terraform",
+				ReportFormat: "junit",
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid report format html",
+			args: Args{
+				TagsFile:     "test-tags.yaml",
+				Type:         "terraform",
+				ReportFormat: "html",
+			},
+			wantErr: false,
+		},
 		{
 			name: "empty report format is valid",
 			args: Args{
@@ -128,6 +165,135 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid dry run with json diff format",
+			args: Args{
+				TagsFile:   "test-tags.yaml",
+				Type:       "terraform",
+				DryRun:     true,
+				DiffFormat: "json",
+				FailOnDiff: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid diff format",
+			args: Args{
+				TagsFile:   "test-tags.yaml",
+				Type:       "terraform",
+				DryRun:     true,
+				DiffFormat: "patch",
+			},
+			wantErr: true,
+			errMsg:  "invalid diff format patch, must be one of: unified, json",
+		},
+		{
+			name: "valid provider strategy",
+			args: Args{
+				TagsFile: "test-tags.yaml",
+				Type:     "terraform",
+				Strategy: "provider",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid strategy",
+			args: Args{
+				TagsFile: "test-tags.yaml",
+				Type:     "terraform",
+				Strategy: "module",
+			},
+			wantErr: true,
+			errMsg:  "invalid strategy module, must be either 'resource' or 'provider'",
+		},
+		{
+			name: "fail on diff without dry run",
+			args: Args{
+				TagsFile:   "test-tags.yaml",
+				Type:       "terraform",
+				FailOnDiff: true,
+			},
+			wantErr: true,
+			errMsg:  "-fail-on-diff can only be used together with -dry-run",
+		},
+		{
+			name: "tagging report to stdout with dry run",
+			args: Args{
+				TagsFile:      "test-tags.yaml",
+				Type:          "terraform",
+				DryRun:        true,
+				TaggingReport: "-",
+			},
+			wantErr: true,
+			errMsg:  "-tagging-report can't be written to stdout together with -dry-run, which prints the diffs to stdout, write it to a file instead",
+		},
+		{
+			name: "trace tags with provider strategy",
+			args: Args{
+				TagsFile:  "test-tags.yaml",
+				Type:      "terraform",
+				Strategy:  "provider",
+				TraceTags: true,
+			},
+			wantErr: true,
+			errMsg:  "-trace-tags can't be used together with -strategy=provider, trace tags are specific to every resource",
+		},
+		{
+			name: "revert command without tags file",
+			args: Args{
+				Command: CommandRevert,
+				Type:    "terraform",
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown command",
+			args: Args{
+				Command: "undo",
+				Type:    "terraform",
+			},
+			wantErr: true,
+			errMsg:  "invalid command undo, must be either 'revert' or 'finalize'",
+		},
+		{
+			name: "finalize command with dry run",
+			args: Args{
+				Command: CommandFinalize,
+				Type:    "terraform",
+				DryRun:  true,
+			},
+			wantErr: true,
+			errMsg:  "the finalize command cannot be combined with -validate-only or -dry-run",
+		},
+		{
+			name: "migrate without tags file",
+			args: Args{
+				Migrate: "migrations.yaml",
+				Type:    "terraform",
+			},
+			wantErr: false,
+		},
+		{
+			name: "migrate with validate only",
+			args: Args{
+				Migrate:      "migrations.yaml",
+				ValidateOnly: true,
+				StandardFile: "standard.yaml",
+				Type:         "terraform",
+			},
+			wantErr: true,
+			errMsg:  "-migrate cannot be combined with -validate-only",
+		},
+		{
+			name: "auto fix without validate only",
+			args: Args{
+				TagsFile: "tags.yaml",
+				Type:     "terraform",
+				AutoFix:  true,
+			},
+			wantErr: true,
+			errMsg:  "-auto-fix can only be used together with -validate-only",
+		},
 	}
 
 	for _, tt := range tests {
@@ -278,6 +444,18 @@ func TestInitArgs(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "finalize command",
+			args: []string{"terratag", "finalize", "-dir", "infra"},
+			validate: func(t *testing.T, args Args) {
+				if args.Command != CommandFinalize {
+					t.Errorf("expected command finalize, got %s", args.Command)
+				}
+				if args.Dir != "infra" {
+					t.Errorf("expected dir infra, got %s", args.Dir)
+				}
+			},
+		},
 		{
 			name:    "missing required tags",
 			args:    []string{"terratag"},
@@ -30,6 +30,7 @@ func main() {
 	if err != nil {
 		fmt.Println(err)
 		fmt.Println("Usage: terratag -tags='{ \"some_tag\": \"value\" }' [-dir=\".\"]")
+		fmt.Println("       terratag revert|finalize [-dir=\".\"]")
 
 		return
 	}
@@ -121,22 +122,38 @@ func runWithContext(ctx context.Context, args cli.Args) error {
 	shutdownWg.Add(1)
 	defer shutdownWg.Done()
 	
+	type result struct {
+		report *terratag.TaggingReport
+		err    error
+	}
+
 	// Create a channel to receive the result
-	resultChan := make(chan error, 1)
+	resultChan := make(chan result, 1)
 	
 	// Run terratag in a goroutine
 	go func() {
-		resultChan <- terratag.Terratag(args)
+		report, err := terratag.Terratag(ctx, args)
+		resultChan <- result{report: report, err: err}
 	}()
 	
-	// Wait for either completion or cancellation
+	// Wait for completion. On cancellation terratag kills its subprocesses and stops between files,
+	// waiting for it ensures no file is left half-written.
+	var res result
 	select {
-	case err := <-resultChan:
-		return err
+	case res = <-resultChan:
 	case <-ctx.Done():
-		log.Println("[INFO] Operation cancelled by shutdown signal")
-		return ctx.Err()
+		log.Println("[INFO] Operation cancelled by shutdown signal, waiting for the current files to complete")
+		res = <-resultChan
+	}
+
+	// The report is written even when the run failed, e.g. with -fail-on-diff or on cancellation
+	if args.TaggingReport != "" && res.report != nil {
+		if err := res.report.WriteJSON(args.TaggingReport); err != nil {
+			log.Printf("[ERROR] %v", err)
+		}
 	}
+
+	return res.err
 }
 
 func initLogFiltering(verbose bool) {
@@ -1,6 +1,12 @@
 package common
 
-import "github.com/hashicorp/hcl/v2/hclwrite"
+import (
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/cloudyali/terratag/internal/selector"
+)
 
 type IACType string
 
@@ -10,25 +16,99 @@ const (
 	TerragruntRunAll IACType = "terragrunt-run-all"
 )
 
+type TaggingStrategy string
+
+const (
+	StrategyResource TaggingStrategy = "resource"
+	StrategyProvider TaggingStrategy = "provider"
+)
+
 type Version struct {
 	Major int
 	Minor int
 }
 
+// CommandTimeouts limits how long the terraform, tofu and terragrunt subprocesses may run, 0 disables a timeout
+type CommandTimeouts struct {
+	Init     time.Duration // init
+	Validate time.Duration // validate, used to detect a missing or outdated initialization
+	Schema   time.Duration // providers schema -json
+}
+
 type TaggingArgs struct {
 	Filter              string
 	Skip                string
+	Selectors           *selector.Selectors // Filter and Skip parsed once per run
 	Dir                 string
-	Tags                string // JSON string of tags loaded from TagsFile
+	Tags                string          // JSON string of tags loaded from TagsFile
+	TagExpressions      map[string]bool // Keys of Tags whose values are raw HCL expressions
 	Matches             []string
 	IsSkipTerratagFiles bool
 	Rename              bool
 	DefaultToTerraform  bool
 	IACType             IACType
 	KeepExistingTags    bool
+	DryRun              bool // Compute diffs instead of writing tagged files
+	Strategy            TaggingStrategy
+	TagSets             TagSetResolver      // Per resource type tags, nil to apply Tags to every resource
+	Parallelism         int                 // Maximum number of files processed concurrently, defaults to the number of CPUs
+	Tracer              Tracer              // Computes the traceability tags of resources, nil to disable them
+	Overlays            []TagOverlay        // Tag values of the files of matching directories, applied in order
+	ModuleAddresses     map[string][]string // Addresses of the modules instantiating each module directory, keyed by absolute directory
+	CloudProvider       string              // Cloud provider of the tag standard, for resource types without a known provider prefix
+	LabelSanitizer      LabelSanitizer      // Transforms the values of Google labels into valid label values, nil to keep them as is
+}
+
+// TagOverlay adds or overrides tag values for the files of the directories matched by a glob
+type TagOverlay struct {
+	Pattern string            // Glob matched against the directory and the path of a file, relative to Dir
+	Tags    map[string]string // Literal tag values
+}
+
+// Tracer computes the traceability tags of the resource blocks of a file
+type Tracer interface {
+	// FileTags returns the traceability tags of the resource blocks of a file, keyed by resource address
+	FileTags(path string) map[string]map[string]string
+}
+
+// LabelSanitizer transforms tag values into valid Google label values
+type LabelSanitizer interface {
+	// SanitizeLabelValue returns the label value of the value of a tag, and the other value of the key sanitized to
+	// the same label value, the first time such a collision is detected
+	SanitizeLabelValue(key string, value string) (string, string)
+}
+
+// TagSet is the set of tags applied to a resource type
+type TagSet struct {
+	Name           string            // Empty for the standard tags, the resource type for resource rule specific tags, the provider for provider specific tags
+	Tags           string            // JSON string of tags
+	TagExpressions map[string]bool   // Keys of Tags whose values are raw HCL expressions
+	ExcludedTags   []string          // Tags that must not be applied to the resource type
+	KeyAliases     map[string]string // Keys of the standard tags renamed for the provider of the resource type, by standard key
+}
+
+// TagSetResolver resolves the tags to apply to a resource type
+type TagSetResolver interface {
+	// ResolveTagSet returns the tag set of a resource type, or nil if the resource type is excluded from tagging
+	ResolveTagSet(resourceType string) (*TagSet, error)
+}
+
+// ProviderTagSetResolver is implemented by the tag set resolvers whose tags depend on the cloud provider, it resolves
+// the default tags of the provider blocks
+type ProviderTagSetResolver interface {
+	// ResolveProviderTagSet returns the tag set of the provider blocks of a cloud provider, or nil to use the standard tags
+	ResolveProviderTagSet(provider string) (*TagSet, error)
+}
+
+// DirTagSetResolver is implemented by the tag set resolvers whose tags depend on the directory of the tagged files
+type DirTagSetResolver interface {
+	// ResolveDirTagSets returns the standard tags and the tag set resolver of the files of a directory
+	ResolveDirTagSets(dir string) (*TagSet, TagSetResolver, error)
 }
 
 type TerratagLocal struct {
-	Found map[string]hclwrite.Tokens
-	Added string
+	Found        map[string]hclwrite.Tokens
+	Added        string
+	AddedTagSets map[string]string // Resource rule specific terratag_added_* locals by tag set name
+	Trace        map[string]string // terratag_trace_* locals of the traced resources by key
 }
@@ -3,6 +3,7 @@ package convert
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"strings"
 
@@ -21,6 +22,61 @@ func GetExistingTagsExpression(tokens hclwrite.Tokens) string {
 	return stringifyExpression(tokens)
 }
 
+// ReferencesTerratagAddedLocal returns true if the expression already references a terratag_added_* local
+// of the file (standard or tag set specific), i.e. it is the output of a previous terratag run
+func ReferencesTerratagAddedLocal(tokens hclwrite.Tokens, filename string) bool {
+	return terratagAddedLocalIndex(tokens, filename) != -1
+}
+
+// RetargetTerratagAddedLocal returns a copy of the tokens where the reference to a terratag_added_* local
+// of the file is replaced by a reference to the local named key
+func RetargetTerratagAddedLocal(tokens hclwrite.Tokens, filename string, key string) hclwrite.Tokens {
+	index := terratagAddedLocalIndex(tokens, filename)
+	if index == -1 {
+		return tokens
+	}
+
+	retargeted := make(hclwrite.Tokens, len(tokens))
+	copy(retargeted, tokens)
+	retargeted[index] = &hclwrite.Token{
+		Type:         hclsyntax.TokenIdent,
+		Bytes:        []byte(key),
+		SpacesBefore: tokens[index].SpacesBefore,
+	}
+
+	return retargeted
+}
+
+// ReferencesLocal returns true if the expression references the local named key
+func ReferencesLocal(tokens hclwrite.Tokens, key string) bool {
+	for i := 0; i+2 < len(tokens); i++ {
+		if tokens[i].Type == hclsyntax.TokenIdent && string(tokens[i].Bytes) == "local" &&
+			tokens[i+1].Type == hclsyntax.TokenDot &&
+			tokens[i+2].Type == hclsyntax.TokenIdent && string(tokens[i+2].Bytes) == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// terratagAddedLocalIndex returns the index of the name token of the first terratag_added_* local
+// of the file referenced by the tokens, or -1
+func terratagAddedLocalIndex(tokens hclwrite.Tokens, filename string) int {
+	key := tag_keys.GetTerratagAddedKey(filename)
+
+	for i := 0; i+2 < len(tokens); i++ {
+		if tokens[i].Type == hclsyntax.TokenIdent && string(tokens[i].Bytes) == "local" &&
+			tokens[i+1].Type == hclsyntax.TokenDot &&
+			tokens[i+2].Type == hclsyntax.TokenIdent &&
+			(string(tokens[i+2].Bytes) == key || strings.HasPrefix(string(tokens[i+2].Bytes), key+"__")) {
+			return i + 2
+		}
+	}
+
+	return -1
+}
+
 func isHclMap(tokens hclwrite.Tokens) bool {
 	maybeHclMap := strings.TrimSpace(string(tokens.Bytes()))
 
@@ -37,11 +93,34 @@ func stringifyExpression(tokens hclwrite.Tokens) string {
 }
 
 func AppendLocalsBlock(file *hclwrite.File, filename string, terratag common.TerratagLocal) {
-	key := tag_keys.GetTerratagAddedKey(filename)
+	locals := map[string]string{tag_keys.GetTerratagAddedKey(filename): terratag.Added}
+	for tagSet, added := range terratag.AddedTagSets {
+		locals[tag_keys.GetTerratagAddedTagSetKey(filename, tagSet)] = added
+	}
+	for key, trace := range terratag.Trace {
+		locals[key] = trace
+	}
+
+	var newLocals *hclwrite.Block
+
+	for _, key := range utils.SortObjectKeys(locals) {
+		if replaceLocal(file, key, locals[key]) {
+			continue
+		}
 
-	// If there's an existings terratag locals replace it with the merged locals.
-	blocks := file.Body().Blocks()
-	for _, block := range blocks {
+		if newLocals == nil {
+			file.Body().AppendNewline()
+			newLocals = file.Body().AppendNewBlock("locals", nil)
+			file.Body().AppendNewline()
+		}
+
+		newLocals.Body().SetAttributeValue(key, cty.StringVal(locals[key]))
+	}
+}
+
+// replaceLocal replaces the value of an existing local, it returns false if the local doesn't exist
+func replaceLocal(file *hclwrite.File, key string, value string) bool {
+	for _, block := range file.Body().Blocks() {
 		if block.Type() != "locals" {
 			continue
 		}
@@ -51,19 +130,16 @@ func AppendLocalsBlock(file *hclwrite.File, filename string, terratag common.Ter
 		}
 
 		block.Body().RemoveAttribute(key)
-		block.Body().SetAttributeValue(key, cty.StringVal(terratag.Added))
+		block.Body().SetAttributeValue(key, cty.StringVal(value))
 
-		return
+		return true
 	}
 
-	file.Body().AppendNewline()
-	locals := file.Body().AppendNewBlock("locals", nil)
-	file.Body().AppendNewline()
-
-	locals.Body().SetAttributeValue(key, cty.StringVal(terratag.Added))
+	return false
 }
 
-func AppendTagBlocks(resource *hclwrite.Block, tags string) error {
+// AppendTagBlocks adds a "tag" block per tag, values of the keys marked in expressions are raw HCL expressions
+func AppendTagBlocks(resource *hclwrite.Block, tags string, expressions map[string]bool) error {
 	var tagsMap map[string]string
 	if err := json.Unmarshal([]byte(tags), &tagsMap); err != nil {
 		return err
@@ -71,11 +147,33 @@ func AppendTagBlocks(resource *hclwrite.Block, tags string) error {
 
 	keys := utils.SortObjectKeys(tagsMap)
 
+	// Tag blocks added by a previous run (or by hand) are updated in place instead of duplicated
+	existingTagBlocks := map[string]*hclwrite.Block{}
+	for _, block := range resource.Body().Blocks() {
+		if block.Type() != "tag" || block.Body().GetAttribute("key") == nil {
+			continue
+		}
+
+		existingKey := stringifyExpression(block.Body().GetAttribute("key").Expr().BuildTokens(hclwrite.Tokens{}))
+		existingTagBlocks[strings.Trim(existingKey, "\"")] = block
+	}
+
 	for _, key := range keys {
+		value, err := tagValueTokens(tagsMap[key], expressions[key])
+		if err != nil {
+			return fmt.Errorf("invalid expression for tag '%s': %w", key, err)
+		}
+
+		if tagBlock, ok := existingTagBlocks[key]; ok {
+			tagBlock.Body().SetAttributeRaw("value", value)
+
+			continue
+		}
+
 		resource.Body().AppendNewline()
 		tagBlock := resource.Body().AppendNewBlock("tag", nil)
 		tagBlock.Body().SetAttributeValue("key", cty.StringVal(key))
-		tagBlock.Body().SetAttributeValue("value", cty.StringVal(tagsMap[key]))
+		tagBlock.Body().SetAttributeRaw("value", value)
 		tagBlock.Body().SetAttributeValue("propagate_at_launch", cty.BoolVal(true))
 	}
 
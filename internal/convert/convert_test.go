@@ -1,9 +1,11 @@
 package convert
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/cloudyali/terratag/internal/common"
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -227,7 +229,7 @@ func TestAppendTagBlocks(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			resource := hclwrite.NewBlock("resource", []string{"aws_instance", "test"})
-			err := AppendTagBlocks(resource, tt.tags)
+			err := AppendTagBlocks(resource, tt.tags, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("AppendTagBlocks() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -239,6 +241,44 @@ func TestAppendTagBlocks(t *testing.T) {
 	}
 }
 
+func TestAppendTagBlocksTwice(t *testing.T) {
+	f := hclwrite.NewEmptyFile()
+	resource := f.Body().AppendNewBlock("resource", []string{"aws_autoscaling_group", "test"})
+
+	if err := AppendTagBlocks(resource, `{"Environment":"dev","Team":"platform"}`, nil); err != nil {
+		t.Fatalf("AppendTagBlocks() error = %v", err)
+	}
+	if err := AppendTagBlocks(resource, `{"Environment":"prod","Team":"platform"}`, nil); err != nil {
+		t.Fatalf("AppendTagBlocks() error = %v", err)
+	}
+
+	blocks := resource.Body().Blocks()
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 tag blocks after re-tagging, got %d", len(blocks))
+	}
+
+	value := strings.TrimSpace(string(blocks[0].Body().GetAttribute("value").Expr().BuildTokens(nil).Bytes()))
+	if value != `"prod"` {
+		t.Errorf("expected Environment tag to be updated to prod, got %s", value)
+	}
+}
+
+func TestReferencesTerratagAddedLocal(t *testing.T) {
+	f, diags := hclwrite.ParseConfig([]byte(`tags = merge({ "Name" = "a" }, local.terratag_added_main)`), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("failed to parse: %v", diags)
+	}
+
+	tokens := f.Body().GetAttribute("tags").Expr().BuildTokens(nil)
+
+	if !ReferencesTerratagAddedLocal(tokens, "main") {
+		t.Error("expected expression to reference terratag_added_main")
+	}
+	if ReferencesTerratagAddedLocal(tokens, "other") {
+		t.Error("expected expression not to reference terratag_added_other")
+	}
+}
+
 func TestUnquoteTagsAttribute(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -433,4 +473,376 @@ func TestHclValueToMap(t *testing.T) {
 	if err == nil {
 		t.Error("expected error for invalid HCL tokens")
 	}
-}
\ No newline at end of file
+}
+func TestAppendTagBlocksExpressions(t *testing.T) {
+	f := hclwrite.NewEmptyFile()
+	resource := f.Body().AppendNewBlock("resource", []string{"aws_autoscaling_group", "test"})
+
+	if err := AppendTagBlocks(resource, `{"Environment":"var.environment","Team":"platform"}`, map[string]bool{"Environment": true}); err != nil {
+		t.Fatalf("AppendTagBlocks() error = %v", err)
+	}
+
+	blocks := resource.Body().Blocks()
+	environment := strings.TrimSpace(string(blocks[0].Body().GetAttribute("value").Expr().BuildTokens(nil).Bytes()))
+	if environment != "var.environment" {
+		t.Errorf("expected unquoted expression value, got %s", environment)
+	}
+
+	team := strings.TrimSpace(string(blocks[1].Body().GetAttribute("value").Expr().BuildTokens(nil).Bytes()))
+	if team != `"platform"` {
+		t.Errorf("expected quoted literal value, got %s", team)
+	}
+}
+
+func TestMergeTerratagLocalsExpressions(t *testing.T) {
+	f, diags := hclwrite.ParseConfig([]byte(`locals {
+  terratag_added_main = {"Environment" = var.environment, "Team" = "platform"}
+}
+`), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("failed to parse config: %v", diags)
+	}
+
+	attribute := f.Body().Blocks()[0].Body().GetAttribute("terratag_added_main")
+
+	merged, err := MergeTerratagLocals(attribute, `{"Owner"="${local.team}@corp.com","Team"=upper("platform")}`)
+	if err != nil {
+		t.Fatalf("MergeTerratagLocals() error = %v", err)
+	}
+
+	expected := `{"Environment" = var.environment, "Owner" = "${local.team}@corp.com", "Team" = upper("platform")}`
+	if merged != expected {
+		t.Errorf("MergeTerratagLocals() = %s, want %s", merged, expected)
+	}
+}
+
+func TestExpressionToJSONTemplate(t *testing.T) {
+	tests := map[string]string{
+		`var.environment`:            `${var.environment}`,
+		`lower(local.team)`:          `${lower(local.team)}`,
+		`"${local.team}@corp.com"`:   `${local.team}@corp.com`,
+		`"literal \"quoted\" value"`: `literal "quoted" value`,
+	}
+
+	for input, expected := range tests {
+		if output := ExpressionToJSONTemplate(input); output != expected {
+			t.Errorf("ExpressionToJSONTemplate(%s) = %s, want %s", input, output, expected)
+		}
+	}
+}
+
+func TestRetargetTerratagAddedLocal(t *testing.T) {
+	f, diags := hclwrite.ParseConfig([]byte(`tags = merge({ "Name" = "a" }, local.terratag_added_main)`), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("failed to parse: %v", diags)
+	}
+
+	tokens := f.Body().GetAttribute("tags").Expr().BuildTokens(nil)
+	retargeted := RetargetTerratagAddedLocal(tokens, "main", "terratag_added_main__aws_db_instance")
+
+	expected := `merge({ "Name" = "a" }, local.terratag_added_main__aws_db_instance)`
+	if got := strings.TrimSpace(string(retargeted.Bytes())); got != expected {
+		t.Errorf("RetargetTerratagAddedLocal() = %s, want %s", got, expected)
+	}
+	if !ReferencesTerratagAddedLocal(retargeted, "main") {
+		t.Error("expected tag set local to be recognized as a terratag_added local of the file")
+	}
+	if got := strings.TrimSpace(string(tokens.Bytes())); got != `merge({ "Name" = "a" }, local.terratag_added_main)` {
+		t.Errorf("expected original tokens to be left untouched, got %s", got)
+	}
+}
+
+func TestMigrateTagKeys(t *testing.T) {
+	migrations := TagKeyMigrations{
+		Renames:  map[string]string{"CostCentre": "CostCenter", "team": "Team Name"},
+		Removals: map[string]bool{"LegacyOwner": true},
+	}
+
+	tests := []struct {
+		name       string
+		input      string
+		expected   string
+		unresolved []string
+	}{
+		{
+			name: "rename and remove in a map",
+			input: `{
+    CostCentre  = "cc-1"
+    LegacyOwner = "bob" # former owner
+    Name        = "web"
+  }`,
+			expected: `{
+    CostCenter  = "cc-1"
+    Name        = "web"
+  }`,
+		},
+		{
+			name:       "merge with computed tags",
+			input:      `merge(var.tags, { "CostCentre" = "cc-1", LegacyOwner = "bob" }, local.terratag_added_main)`,
+			expected:   `merge(var.tags, { "CostCenter" = "cc-1" }, local.terratag_added_main)`,
+			unresolved: []string{"var.tags"},
+		},
+		{
+			name:     "new key that isn't an identifier is quoted",
+			input:    `{ team = "platform" }`,
+			expected: `{ "Team Name" = "platform" }`,
+		},
+		{
+			name:       "computed key",
+			input:      `{ (var.key) = "x", CostCentre = "cc-1" }`,
+			expected:   `{ (var.key) = "x", CostCenter = "cc-1" }`,
+			unresolved: []string{"key (var.key)"},
+		},
+		{
+			name:       "new key already set",
+			input:      `{ CostCentre = "cc-1", CostCenter = "cc-2" }`,
+			expected:   `{ CostCentre = "cc-1", CostCenter = "cc-2" }`,
+			unresolved: []string{"key CostCentre (CostCenter is already set)"},
+		},
+		{
+			name:     "terratag locals only",
+			input:    `merge(local.terratag_found_main__aws_instance__web, local.terratag_added_main)`,
+			expected: `merge(local.terratag_found_main__aws_instance__web, local.terratag_added_main)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, diags := hclwrite.ParseConfig([]byte("tags = "+tt.input+"\n"), "", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatalf("failed to parse: %v", diags)
+			}
+
+			migrated, changes, err := MigrateTagKeys(f.Body().GetAttribute("tags").Expr().BuildTokens(nil), migrations)
+			if err != nil {
+				t.Fatalf("MigrateTagKeys() error = %v", err)
+			}
+
+			if got := strings.TrimSpace(string(migrated.Bytes())); got != tt.expected {
+				t.Errorf("MigrateTagKeys() = %s, want %s", got, tt.expected)
+			}
+
+			if strings.Join(changes.Unresolved, ",") != strings.Join(tt.unresolved, ",") {
+				t.Errorf("MigrateTagKeys() unresolved = %v, want %v", changes.Unresolved, tt.unresolved)
+			}
+		})
+	}
+}
+
+func TestMigrateTagBlocks(t *testing.T) {
+	f, diags := hclwrite.ParseConfig([]byte(`resource "aws_autoscaling_group" "test" {
+  tag {
+    key                 = "CostCentre"
+    value               = "cc-1"
+    propagate_at_launch = true
+  }
+  tag {
+    key                 = "LegacyOwner"
+    value               = "bob"
+    propagate_at_launch = true
+  }
+  tag {
+    key                 = var.tag_key
+    value               = "x"
+    propagate_at_launch = true
+  }
+}
+`), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("failed to parse: %v", diags)
+	}
+
+	resource := f.Body().Blocks()[0]
+
+	changes, err := MigrateTagBlocks(resource, TagKeyMigrations{
+		Renames:  map[string]string{"CostCentre": "CostCenter"},
+		Removals: map[string]bool{"LegacyOwner": true},
+	})
+	if err != nil {
+		t.Fatalf("MigrateTagBlocks() error = %v", err)
+	}
+
+	blocks := resource.Body().Blocks()
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 tag blocks, got %d", len(blocks))
+	}
+
+	key := strings.TrimSpace(string(blocks[0].Body().GetAttribute("key").Expr().BuildTokens(nil).Bytes()))
+	if key != `"CostCenter"` {
+		t.Errorf("expected renamed tag block key, got %s", key)
+	}
+
+	if len(changes.Renamed) != 1 || len(changes.Removed) != 1 || len(changes.Unresolved) != 1 {
+		t.Errorf("unexpected changes %+v", changes)
+	}
+}
+
+func TestMigrateJSONTagKeys(t *testing.T) {
+	tags := map[string]interface{}{"CostCentre": "cc-1", "LegacyOwner": "bob", "Name": "web"}
+
+	changes := MigrateJSONTagKeys(tags, TagKeyMigrations{
+		Renames:  map[string]string{"CostCentre": "CostCenter"},
+		Removals: map[string]bool{"LegacyOwner": true},
+	})
+
+	if len(tags) != 2 || tags["CostCenter"] != "cc-1" || tags["Name"] != "web" {
+		t.Errorf("unexpected migrated tags %v", tags)
+	}
+	if !changes.Changed() || len(changes.Unresolved) != 0 {
+		t.Errorf("unexpected changes %+v", changes)
+	}
+
+	changes = MigrateJSONTagKeys("${var.tags}", TagKeyMigrations{})
+	if len(changes.Unresolved) != 1 {
+		t.Errorf("expected tag expression to be unresolved, got %+v", changes)
+	}
+}
+
+func TestEditTags(t *testing.T) {
+	edits := []TagEdit{
+		{Action: TagEditSet, Key: "Environment", Value: "production"},
+		{Action: TagEditSet, Key: "Owner", Value: "team@corp.com"},
+		{Action: TagEditRemove, Key: "Legacy"},
+		{Action: TagEditRename, Key: "cost_center", NewKey: "CostCenter"},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		skipped  map[string]string
+	}{
+		{
+			name:     "literal map",
+			input:    `{ Environment = "prod", Legacy = "yes", cost_center = "cc-1" }`,
+			expected: `{ Environment = "production", CostCenter = "cc-1", Owner = "team@corp.com" }`,
+			skipped:  map[string]string{},
+		},
+		{
+			name:     "computed value",
+			input:    `{ Environment = var.environment, Owner = "me" }`,
+			expected: `{ Environment = var.environment, Owner = "team@corp.com" }`,
+			skipped: map[string]string{
+				"Environment": "value var.environment is computed",
+				"Legacy":      "tag is not set",
+				"cost_center": "tag is not set",
+			},
+		},
+		{
+			name:     "merge with computed tags",
+			input:    `merge(var.tags, { Environment = "prod" })`,
+			expected: `merge(var.tags, { Environment = "production", Owner = "team@corp.com" })`,
+			skipped: map[string]string{
+				"Legacy":      "tag may be set by var.tags, which is computed",
+				"cost_center": "tag may be set by var.tags, which is computed",
+			},
+		},
+		{
+			name:     "computed tags",
+			input:    `var.tags`,
+			expected: `var.tags`,
+			skipped: map[string]string{
+				"Environment": "tags are computed by var.tags",
+				"Owner":       "tags are computed by var.tags",
+				"Legacy":      "tag may be set by var.tags, which is computed",
+				"cost_center": "tag may be set by var.tags, which is computed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, diags := hclwrite.ParseConfig([]byte("tags = "+tt.input+"\n"), "", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatalf("failed to parse: %v", diags)
+			}
+
+			edited, skipped, err := EditTags(f.Body().GetAttribute("tags").Expr().BuildTokens(nil), edits)
+			if err != nil {
+				t.Fatalf("EditTags() error = %v", err)
+			}
+
+			if got := strings.TrimSpace(string(edited.Bytes())); got != tt.expected {
+				t.Errorf("EditTags() = %s, want %s", got, tt.expected)
+			}
+
+			if len(skipped) != len(tt.skipped) {
+				t.Fatalf("EditTags() skipped = %v, want %v", skipped, tt.skipped)
+			}
+
+			for key, reason := range tt.skipped {
+				if skipped[key] != reason {
+					t.Errorf("EditTags() skipped %s = %q, want %q", key, skipped[key], reason)
+				}
+			}
+		})
+	}
+}
+
+func TestEditTagsWithoutTags(t *testing.T) {
+	edited, skipped, err := EditTags(nil, []TagEdit{{Action: TagEditSet, Key: "Environment", Value: "prod"}})
+	if err != nil {
+		t.Fatalf("EditTags() error = %v", err)
+	}
+
+	if got := strings.TrimSpace(string(edited.Bytes())); got != "{\n  Environment = \"prod\"\n}" {
+		t.Errorf("EditTags() = %s", got)
+	}
+	if len(skipped) != 0 {
+		t.Errorf("expected no skipped edits, got %v", skipped)
+	}
+}
+
+func TestLiteralTagKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		known    bool
+	}{
+		{
+			name:     "map",
+			input:    `{ Name = "web", "Cost Center" = "cc-1" }`,
+			expected: []string{"Name", "Cost Center"},
+			known:    true,
+		},
+		{
+			name:     "merge with terratag added tags",
+			input:    `merge({ Name = "web" }, { Name = "api", Team = "ops" }, local.terratag_added_main)`,
+			expected: []string{"Name", "Team"},
+			known:    true,
+		},
+		{
+			name:     "merge with computed tags",
+			input:    `merge(var.tags, { Name = "web" })`,
+			expected: []string{"Name"},
+		},
+		{
+			name:     "computed key",
+			input:    `{ (var.key) = "web", Name = "web" }`,
+			expected: []string{"Name"},
+		},
+		{
+			name:  "tags found by a previous run",
+			input: `merge(local.terratag_found_main__aws_instance__web, local.terratag_added_main)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, diags := hclwrite.ParseConfig([]byte("tags = "+tt.input+"\n"), "", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatalf("failed to parse: %v", diags)
+			}
+
+			keys, known, err := LiteralTagKeys(f.Body().GetAttribute("tags").Expr().BuildTokens(nil))
+			if err != nil {
+				t.Fatalf("LiteralTagKeys() error = %v", err)
+			}
+
+			if strings.Join(keys, ",") != strings.Join(tt.expected, ",") || known != tt.known {
+				t.Errorf("LiteralTagKeys() = %v, %v, want %v, %v", keys, known, tt.expected, tt.known)
+			}
+		})
+	}
+}
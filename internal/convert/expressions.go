@@ -0,0 +1,47 @@
+package convert
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// tagValueTokens returns the tokens of a tag value, quoted unless it is a raw HCL expression
+func tagValueTokens(value string, isExpression bool) (hclwrite.Tokens, error) {
+	if !isExpression {
+		return hclwrite.TokensForValue(cty.StringVal(value)), nil
+	}
+
+	file, diags := hclwrite.ParseConfig([]byte("value = "+value), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return file.Body().GetAttribute("value").Expr().BuildTokens(nil), nil
+}
+
+// ExpressionToJSONTemplate converts a raw HCL expression into the equivalent JSON syntax string value:
+// quoted templates keep their content ("${local.team}@corp.com"), any other expression is wrapped with ${ }
+func ExpressionToJSONTemplate(expression string) string {
+	expression = strings.TrimSpace(expression)
+
+	if parsed, diags := hclsyntax.ParseExpression([]byte(expression), "", hcl.InitialPos); !diags.HasErrors() {
+		if _, isTemplate := parsed.(*hclsyntax.TemplateExpr); isTemplate {
+			if content, err := strconv.Unquote(expression); err == nil {
+				return content
+			}
+		}
+	}
+
+	return "${" + expression + "}"
+}
+
+// LiteralToJSONTemplate escapes the template sequences of a literal value for the JSON syntax, where every string
+// is a template: ${ and %{ become $${ and %%{
+func LiteralToJSONTemplate(value string) string {
+	return strings.NewReplacer("${", "$${", "%{", "%%{").Replace(value)
+}
@@ -0,0 +1,205 @@
+package convert
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TagEditAction is the change a TagEdit makes to a tag
+type TagEditAction string
+
+const (
+	TagEditSet    TagEditAction = "set"    // Add the tag or update its value
+	TagEditRemove TagEditAction = "remove" // Remove the tag
+	TagEditRename TagEditAction = "rename" // Rename the key of the tag, keeping its value
+)
+
+// TagEdit is a change to a single tag of a tags expression
+type TagEdit struct {
+	Action TagEditAction
+	Key    string
+	Value  string // Literal value of TagEditSet
+	NewKey string // New key of TagEditRename
+}
+
+// EditTags applies edits to the literal tag maps of an expression, which is either a map or a merge() of maps.
+// Only literal keys and values are edited, the edits that can't be applied (e.g. a value set by a variable or a tag
+// that may be set by var.tags) are returned with the reason, keyed by tag key. Missing tags are added to the last
+// literal map, empty tokens (no tags attribute) return a new map.
+func EditTags(tokens hclwrite.Tokens, edits []TagEdit) (hclwrite.Tokens, map[string]string, error) {
+	skipped := map[string]string{}
+
+	// Added tags are written in the order of their keys
+	edits = append([]TagEdit{}, edits...)
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].Key < edits[j].Key
+	})
+
+	src := tokens.Bytes()
+	if len(bytes.TrimSpace(src)) == 0 {
+		return newTagsMap(edits, skipped)
+	}
+
+	expr, diags := hclsyntax.ParseExpression(src, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, nil, fmt.Errorf("failed to parse tags: %w", diags)
+	}
+
+	var objects []*hclsyntax.ObjectConsExpr
+	var unresolved []string
+
+	collectTagObjects(expr, src, &objects, &unresolved)
+
+	notSetReason := "tag is not set"
+	if len(unresolved) > 0 {
+		notSetReason = "tag may be set by " + strings.Join(unresolved, ", ") + ", which is computed"
+	}
+
+	var keyEdits []keyEdit
+	var added []string
+
+	for _, edit := range edits {
+		items := findTagItems(objects, edit.Key)
+
+		if len(items) == 0 {
+			if edit.Action != TagEditSet {
+				skipped[edit.Key] = notSetReason
+			} else if len(objects) == 0 {
+				skipped[edit.Key] = "tags are computed by " + strings.Join(unresolved, ", ")
+			} else {
+				added = append(added, formatKey(edit.Key, false)+" = "+formatValue(edit.Value))
+			}
+
+			continue
+		}
+
+		switch edit.Action {
+		case TagEditSet:
+			// The last map of a merge() wins
+			item := items[len(items)-1]
+			if !isLiteralValue(item.ValueExpr) {
+				skipped[edit.Key] = fmt.Sprintf("value %s is computed", item.ValueExpr.Range().SliceBytes(src))
+
+				continue
+			}
+
+			valueRange := item.ValueExpr.Range()
+			keyEdits = append(keyEdits, keyEdit{start: valueRange.Start.Byte, end: valueRange.End.Byte, replacement: formatValue(edit.Value)})
+		case TagEditRemove:
+			for _, item := range items {
+				start, end := itemRange(src, item.KeyExpr.Range().Start.Byte, item.ValueExpr.Range().End.Byte)
+				keyEdits = append(keyEdits, keyEdit{start: start, end: end})
+			}
+		case TagEditRename:
+			if len(findTagItems(objects, edit.NewKey)) > 0 {
+				skipped[edit.Key] = edit.NewKey + " is already set"
+
+				continue
+			}
+
+			for _, item := range items {
+				keyRange := item.KeyExpr.Range()
+				keyEdits = append(keyEdits, keyEdit{start: keyRange.Start.Byte, end: keyRange.End.Byte, replacement: formatKey(edit.NewKey, isQuotedKey(src, keyRange))})
+			}
+		}
+	}
+
+	if len(added) > 0 {
+		keyEdits = append(keyEdits, appendItemsEdit(src, objects[len(objects)-1], added))
+	}
+
+	if len(keyEdits) == 0 {
+		return tokens, skipped, nil
+	}
+
+	edited, err := applyKeyEdits(src, keyEdits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return edited, skipped, nil
+}
+
+// newTagsMap returns a map of the tags set by the edits
+func newTagsMap(edits []TagEdit, skipped map[string]string) (hclwrite.Tokens, map[string]string, error) {
+	var items []string
+
+	for _, edit := range edits {
+		if edit.Action != TagEditSet {
+			skipped[edit.Key] = "tag is not set"
+
+			continue
+		}
+
+		items = append(items, "  "+formatKey(edit.Key, false)+" = "+formatValue(edit.Value)+"\n")
+	}
+
+	if len(items) == 0 {
+		return nil, skipped, nil
+	}
+
+	tokens, err := applyKeyEdits([]byte("{\n"+strings.Join(items, "")+"}"), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tokens, skipped, nil
+}
+
+// findTagItems returns the items of the maps that set a literal key
+func findTagItems(objects []*hclsyntax.ObjectConsExpr, key string) []hclsyntax.ObjectConsItem {
+	var items []hclsyntax.ObjectConsItem
+
+	for _, object := range objects {
+		for _, item := range object.Items {
+			if itemKey, ok := literalKey(item.KeyExpr); ok && itemKey == key {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items
+}
+
+// isLiteralValue returns true if a value is a literal string, number or bool, without references or function calls
+func isLiteralValue(expr hclsyntax.Expression) bool {
+	value, diags := expr.Value(nil)
+
+	return !diags.HasErrors() && value.IsKnown() && !value.IsNull() && value.Type().IsPrimitiveType()
+}
+
+func formatValue(value string) string {
+	return string(hclwrite.TokensForValue(cty.StringVal(value)).Bytes())
+}
+
+// appendItemsEdit returns the edit that appends items to a map. Multi-line maps get one item per line before the
+// closing brace, the indentation is fixed by formatting the file.
+func appendItemsEdit(src []byte, object *hclsyntax.ObjectConsExpr, items []string) keyEdit {
+	closeBrace := object.SrcRange.End.Byte - 1
+
+	lineStart := closeBrace
+	for lineStart > 0 && (src[lineStart-1] == ' ' || src[lineStart-1] == '\t') {
+		lineStart--
+	}
+
+	if lineStart > 0 && src[lineStart-1] == '\n' {
+		return keyEdit{start: lineStart, end: lineStart, replacement: strings.Join(items, "\n") + "\n"}
+	}
+
+	// Single line map, e.g. { Name = "web" }
+	previous := lineStart - 1
+
+	separator := ", "
+	if src[previous] == '{' || src[previous] == ',' {
+		separator = " "
+	}
+
+	return keyEdit{start: previous + 1, end: closeBrace, replacement: separator + strings.Join(items, ", ") + " "}
+}
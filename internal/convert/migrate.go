@@ -0,0 +1,367 @@
+package convert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TagKeyMigrations are the tag keys to rename or remove from existing configurations
+type TagKeyMigrations struct {
+	Renames  map[string]string // Old key to new key
+	Removals map[string]bool
+}
+
+// TagKeyChanges are the changes made to the tag keys of a tags expression or of tag blocks
+type TagKeyChanges struct {
+	Renamed    []string // "Old -> New"
+	Removed    []string
+	Unresolved []string // Computed parts whose keys can't be rewritten, e.g. var.tags
+}
+
+// Changed returns true if any key was renamed or removed
+func (c *TagKeyChanges) Changed() bool {
+	return len(c.Renamed) > 0 || len(c.Removed) > 0
+}
+
+type keyEdit struct {
+	start       int
+	end         int
+	replacement string
+}
+
+// MigrateTagKeys renames and removes the literal keys of the tag maps of an expression, which is either a map or
+// a merge() of maps. Other expressions (e.g. var.tags or a conditional) and computed keys can't be rewritten and
+// are reported as unresolved. The layout of the expression is kept, values of renamed keys are realigned by
+// formatting the file.
+func MigrateTagKeys(tokens hclwrite.Tokens, migrations TagKeyMigrations) (hclwrite.Tokens, *TagKeyChanges, error) {
+	src := tokens.Bytes()
+
+	expr, diags := hclsyntax.ParseExpression(src, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, nil, fmt.Errorf("failed to parse tags: %w", diags)
+	}
+
+	changes := &TagKeyChanges{}
+
+	var objects []*hclsyntax.ObjectConsExpr
+
+	collectTagObjects(expr, src, &objects, &changes.Unresolved)
+
+	var edits []keyEdit
+
+	for _, object := range objects {
+		collectObjectKeyEdits(object, src, migrations, changes, &edits)
+	}
+
+	if len(edits) == 0 {
+		return tokens, changes, nil
+	}
+
+	migrated, err := applyKeyEdits(src, edits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return migrated, changes, nil
+}
+
+// applyKeyEdits applies byte range edits to the source of a tags expression and returns its tokens
+func applyKeyEdits(src []byte, edits []keyEdit) (hclwrite.Tokens, error) {
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+
+	edited := append([]byte{}, src...)
+	for _, edit := range edits {
+		edited = append(edited[:edit.start], append([]byte(edit.replacement), edited[edit.end:]...)...)
+	}
+
+	f, diags := hclwrite.ParseConfig([]byte("tags = "+strings.TrimSpace(string(edited))), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to rewrite tags: %w", diags)
+	}
+
+	return f.Body().GetAttribute("tags").Expr().BuildTokens(nil), nil
+}
+
+// collectTagObjects collects the literal maps of a tags expression, which is either a map or a merge() of maps.
+// Other parts of the expression can't be edited and are collected as unresolved, except references to the
+// terratag_* locals, which are generated by terratag.
+func collectTagObjects(expr hclsyntax.Expression, src []byte, objects *[]*hclsyntax.ObjectConsExpr, unresolved *[]string) {
+	switch e := expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		*objects = append(*objects, e)
+	case *hclsyntax.FunctionCallExpr:
+		if e.Name != "merge" {
+			*unresolved = append(*unresolved, string(e.Range().SliceBytes(src)))
+
+			return
+		}
+
+		for _, arg := range e.Args {
+			collectTagObjects(arg, src, objects, unresolved)
+		}
+	case *hclsyntax.TemplateWrapExpr:
+		// "${merge(...)}" in configurations written for Terraform 0.11
+		collectTagObjects(e.Wrapped, src, objects, unresolved)
+	case *hclsyntax.ParenthesesExpr:
+		collectTagObjects(e.Expression, src, objects, unresolved)
+	case *hclsyntax.ScopeTraversalExpr:
+		if isTerratagLocal(e.Traversal) {
+			return
+		}
+
+		*unresolved = append(*unresolved, string(e.Range().SliceBytes(src)))
+	default:
+		*unresolved = append(*unresolved, string(e.Range().SliceBytes(src)))
+	}
+}
+
+func collectObjectKeyEdits(object *hclsyntax.ObjectConsExpr, src []byte, migrations TagKeyMigrations, changes *TagKeyChanges, edits *[]keyEdit) {
+	literalKeys := map[string]bool{}
+
+	for _, item := range object.Items {
+		if key, ok := literalKey(item.KeyExpr); ok {
+			literalKeys[key] = true
+		}
+	}
+
+	for _, item := range object.Items {
+		key, ok := literalKey(item.KeyExpr)
+		if !ok {
+			changes.Unresolved = append(changes.Unresolved, "key "+string(item.KeyExpr.Range().SliceBytes(src)))
+
+			continue
+		}
+
+		if migrations.Removals[key] {
+			start, end := itemRange(src, item.KeyExpr.Range().Start.Byte, item.ValueExpr.Range().End.Byte)
+			*edits = append(*edits, keyEdit{start: start, end: end})
+			changes.Removed = append(changes.Removed, key)
+
+			continue
+		}
+
+		newKey, ok := migrations.Renames[key]
+		if !ok {
+			continue
+		}
+
+		if literalKeys[newKey] {
+			changes.Unresolved = append(changes.Unresolved, fmt.Sprintf("key %s (%s is already set)", key, newKey))
+
+			continue
+		}
+
+		keyRange := item.KeyExpr.Range()
+		*edits = append(*edits, keyEdit{
+			start:       keyRange.Start.Byte,
+			end:         keyRange.End.Byte,
+			replacement: formatKey(newKey, isQuotedKey(src, keyRange)),
+		})
+		changes.Renamed = append(changes.Renamed, key+" -> "+newKey)
+	}
+}
+
+// literalKey returns the key of an object item written as an identifier or a string without interpolations
+func literalKey(keyExpr hclsyntax.Expression) (string, bool) {
+	key, diags := keyExpr.Value(nil)
+	if diags.HasErrors() || key.IsNull() || !key.IsKnown() || key.Type() != cty.String {
+		return "", false
+	}
+
+	return key.AsString(), true
+}
+
+func isTerratagLocal(traversal hcl.Traversal) bool {
+	if traversal.RootName() != "local" || len(traversal) < 2 {
+		return false
+	}
+
+	attr, ok := traversal[1].(hcl.TraverseAttr)
+
+	return ok && strings.HasPrefix(attr.Name, "terratag_")
+}
+
+func isQuotedKey(src []byte, keyRange hcl.Range) bool {
+	return strings.HasPrefix(string(keyRange.SliceBytes(src)), "\"")
+}
+
+// formatKey writes a key the way the original was written, keys that aren't valid identifiers are always quoted
+func formatKey(key string, quoted bool) string {
+	if !quoted && hclsyntax.ValidIdentifier(key) {
+		return key
+	}
+
+	return string(hclwrite.TokensForValue(cty.StringVal(key)).Bytes())
+}
+
+// itemRange extends the range of an object item to its separating comma, and to the whole line
+// if the item is the only content of its line
+func itemRange(src []byte, start int, end int) (int, int) {
+	itemEnd := end
+
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+
+	hasComma := end < len(src) && src[end] == ','
+	if hasComma {
+		end++
+	}
+
+	lineStart := start
+	for lineStart > 0 && (src[lineStart-1] == ' ' || src[lineStart-1] == '\t') {
+		lineStart--
+	}
+
+	lineEnd := end
+	for lineEnd < len(src) && (src[lineEnd] == ' ' || src[lineEnd] == '\t' || src[lineEnd] == '\r') {
+		lineEnd++
+	}
+
+	// A trailing comment describes the removed item
+	if rest := string(src[lineEnd:]); strings.HasPrefix(rest, "#") || strings.HasPrefix(rest, "//") {
+		for lineEnd < len(src) && src[lineEnd] != '\n' {
+			lineEnd++
+		}
+	}
+
+	if (lineStart == 0 || src[lineStart-1] == '\n') && lineEnd < len(src) && src[lineEnd] == '\n' {
+		return lineStart, lineEnd + 1
+	}
+
+	// Inline item, drop the space that separated it from the next one,
+	// or the comma that separated the last item from the previous one
+	if hasComma {
+		if end < len(src) && src[end] == ' ' {
+			end++
+		}
+	} else if lineStart > 0 && src[lineStart-1] == ',' {
+		start, end = lineStart-1, itemEnd
+	}
+
+	return start, end
+}
+
+// MigrateTagBlocks renames the key of, or removes, the "tag" blocks of a resource (e.g. aws_autoscaling_group).
+// Keys that aren't literal strings and dynamic "tag" blocks are reported as unresolved.
+func MigrateTagBlocks(resource *hclwrite.Block, migrations TagKeyMigrations) (*TagKeyChanges, error) {
+	changes := &TagKeyChanges{}
+
+	type tagBlock struct {
+		block *hclwrite.Block
+		key   string
+	}
+
+	var tagBlocks []tagBlock
+
+	literalKeys := map[string]bool{}
+
+	for _, block := range resource.Body().Blocks() {
+		if block.Type() == "dynamic" && len(block.Labels()) > 0 && block.Labels()[0] == "tag" {
+			changes.Unresolved = append(changes.Unresolved, "dynamic \"tag\" blocks")
+
+			continue
+		}
+
+		if block.Type() != "tag" || block.Body().GetAttribute("key") == nil {
+			continue
+		}
+
+		keyTokens := block.Body().GetAttribute("key").Expr().BuildTokens(nil)
+
+		keyExpr, diags := hclsyntax.ParseExpression(keyTokens.Bytes(), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("failed to parse tag block key: %w", diags)
+		}
+
+		key, ok := literalKey(keyExpr)
+		if !ok {
+			changes.Unresolved = append(changes.Unresolved, "tag block key "+strings.TrimSpace(string(keyTokens.Bytes())))
+
+			continue
+		}
+
+		literalKeys[key] = true
+		tagBlocks = append(tagBlocks, tagBlock{block: block, key: key})
+	}
+
+	for _, tag := range tagBlocks {
+		if migrations.Removals[tag.key] {
+			resource.Body().RemoveBlock(tag.block)
+			changes.Removed = append(changes.Removed, tag.key)
+
+			continue
+		}
+
+		newKey, ok := migrations.Renames[tag.key]
+		if !ok {
+			continue
+		}
+
+		if literalKeys[newKey] {
+			changes.Unresolved = append(changes.Unresolved, fmt.Sprintf("tag block %s (%s is already set)", tag.key, newKey))
+
+			continue
+		}
+
+		tag.block.Body().SetAttributeValue("key", cty.StringVal(newKey))
+		changes.Renamed = append(changes.Renamed, tag.key+" -> "+newKey)
+	}
+
+	return changes, nil
+}
+
+// MigrateJSONTagKeys renames and removes the keys of a tag object of a JSON syntax (.tf.json) configuration in place.
+// Tag expressions ("${var.tags}") can't be rewritten and are reported as unresolved.
+func MigrateJSONTagKeys(tags interface{}, migrations TagKeyMigrations) *TagKeyChanges {
+	changes := &TagKeyChanges{}
+
+	switch t := tags.(type) {
+	case nil:
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for key := range t {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if migrations.Removals[key] {
+				delete(t, key)
+				changes.Removed = append(changes.Removed, key)
+
+				continue
+			}
+
+			newKey, ok := migrations.Renames[key]
+			if !ok {
+				continue
+			}
+
+			if _, exists := t[newKey]; exists {
+				changes.Unresolved = append(changes.Unresolved, fmt.Sprintf("key %s (%s is already set)", key, newKey))
+
+				continue
+			}
+
+			t[newKey] = t[key]
+			delete(t, key)
+			changes.Renamed = append(changes.Renamed, key+" -> "+newKey)
+		}
+	case string:
+		changes.Unresolved = append(changes.Unresolved, t)
+	default:
+		changes.Unresolved = append(changes.Unresolved, fmt.Sprintf("%v", t))
+	}
+
+	return changes
+}
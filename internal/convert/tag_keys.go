@@ -0,0 +1,59 @@
+package convert
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// LiteralTagKeys returns the literal keys of a tags expression, which is either a map or a merge() of maps.
+// References to the terratag_* locals are ignored, except terratag_found_* locals, which hold the tags found by a
+// previous run. known is false when those, other parts of the expression or some keys are computed
+// (e.g. merge(var.tags, {...})), and the keys of the tags can't all be known.
+func LiteralTagKeys(tokens hclwrite.Tokens) (keys []string, known bool, err error) {
+	src := tokens.Bytes()
+
+	expr, diags := hclsyntax.ParseExpression(src, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, false, fmt.Errorf("failed to parse tags: %w", diags)
+	}
+
+	var objects []*hclsyntax.ObjectConsExpr
+	var unresolved []string
+
+	collectTagObjects(expr, src, &objects, &unresolved)
+
+	known = len(unresolved) == 0
+	for _, traversal := range expr.Variables() {
+		if !isTerratagLocal(traversal) {
+			continue
+		}
+
+		if attr := traversal[1].(hcl.TraverseAttr); strings.HasPrefix(attr.Name, "terratag_found_") {
+			known = false
+		}
+	}
+
+	seen := map[string]bool{}
+
+	for _, object := range objects {
+		for _, item := range object.Items {
+			key, ok := literalKey(item.KeyExpr)
+			if !ok {
+				known = false
+
+				continue
+			}
+
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	return keys, known, nil
+}
@@ -3,15 +3,16 @@ package convert
 import (
 	"errors"
 	"fmt"
-	"regexp"
 	"sort"
 	"strings"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
 )
 
-var localsRegex = regexp.MustCompile(`"([^"]*)"[ \t]*=[ \t]*"([^"]*)"`)
-
+// Locals maps tag keys to the source of their HCL value expression (e.g. "\"prod\"" or "var.environment")
 type Locals map[string]string
 
 func decodeTerratagLocals(locals Locals, s string) error {
@@ -19,13 +20,25 @@ func decodeTerratagLocals(locals Locals, s string) error {
 		delete(locals, k)
 	}
 
-	matches := localsRegex.FindAllStringSubmatch(s, -1)
-	if matches == nil {
-		return errors.New("no matches found when decoding locals")
+	src := []byte(s)
+
+	expr, diags := hclsyntax.ParseExpression(src, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return fmt.Errorf("failed to parse locals: %w", diags)
 	}
 
-	for _, match := range matches {
-		locals[match[1]] = match[2]
+	object, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return errors.New("locals are not an object")
+	}
+
+	for _, item := range object.Items {
+		key, diags := item.KeyExpr.Value(nil)
+		if diags.HasErrors() || key.Type() != cty.String || key.IsNull() {
+			return fmt.Errorf("unsupported key %q when decoding locals", item.KeyExpr.Range().SliceBytes(src))
+		}
+
+		locals[key.AsString()] = string(item.ValueExpr.Range().SliceBytes(src))
 	}
 
 	return nil
@@ -44,7 +57,7 @@ func encodeTerratagLocals(locals Locals) string {
 	sort.Strings(keys)
 
 	for _, key := range keys {
-		ret += fmt.Sprintf("\"%s\" = \"%s\", ", key, locals[key])
+		ret += fmt.Sprintf("\"%s\" = %s, ", key, locals[key])
 	}
 
 	ret = strings.TrimSuffix(ret, ", ")
@@ -55,8 +68,7 @@ func encodeTerratagLocals(locals Locals) string {
 
 func MergeTerratagLocals(attribute *hclwrite.Attribute, added string) (string, error) {
 	localsAttribute := Locals{}
-	tokens := hclwrite.Tokens{}
-	existingLocalsExpression := stringifyExpression(attribute.BuildTokens(tokens))
+	existingLocalsExpression := stringifyExpression(attribute.Expr().BuildTokens(hclwrite.Tokens{}))
 
 	if err := decodeTerratagLocals(localsAttribute, existingLocalsExpression); err != nil {
 		return "", err
@@ -0,0 +1,222 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	annotationIgnore         = "terratag:ignore"
+	annotationSkipValidation = "terratag:skip-validation"
+)
+
+// jsonCommentKey is the property Terraform ignores in the objects of JSON syntax configurations
+const jsonCommentKey = "//"
+
+// Annotation is the terratag comment annotations of a resource block, written on the lines right above it, e.g.
+//
+//	# terratag:ignore=Owner,CostCenter reason="owned by the platform team"
+//	resource "aws_s3_bucket" "logs" {
+//
+// JSON syntax resources are annotated with a "//" comment property in their body.
+type Annotation struct {
+	Ignore         bool     // terratag:ignore, the resource is neither tagged nor validated
+	IgnoredTags    []string // terratag:ignore=<keys>, tags neither added to nor validated on the resource
+	SkipValidation bool     // terratag:skip-validation, the resource is tagged but not validated
+	Reason         string   // reason="..." of the annotations, joined with "; "
+	Directives     []string // Annotations as written without their reason, e.g. terratag:ignore=Owner,CostCenter
+}
+
+// SkipsValidation returns true if the resource isn't validated at all
+func (a Annotation) SkipsValidation() bool {
+	return a.Ignore || a.SkipValidation
+}
+
+// ResourceAnnotations returns the terratag annotations of the resource blocks of a file, keyed by resource address
+// (e.g. aws_instance.web). Resources without annotations are omitted. Malformed terratag annotations are errors,
+// so that a typo doesn't silently tag or validate a resource that was meant to be exempted.
+func ResourceAnnotations(path string) (map[string]Annotation, error) {
+	if IsJSONFile(path) {
+		return jsonResourceAnnotations(path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	annotations := map[string]Annotation{}
+
+	for key, lineRange := range BlockLineRanges(path) {
+		address, ok := strings.CutPrefix(key, "resource.")
+		if !ok {
+			continue
+		}
+
+		// The comment lines right above the block, up to the first line that isn't a comment
+		first := lineRange.Start
+		for first > 1 && first-1 <= len(lines) {
+			if _, ok := commentText(lines[first-2]); !ok {
+				break
+			}
+
+			first--
+		}
+
+		var annotation Annotation
+		found := false
+
+		for number := first; number < lineRange.Start; number++ {
+			text, _ := commentText(lines[number-1])
+			if !strings.HasPrefix(text, "terratag:") {
+				continue
+			}
+
+			if err := parseAnnotation(text, &annotation); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, number, err)
+			}
+
+			found = true
+		}
+
+		if found {
+			annotations[address] = annotation
+		}
+	}
+
+	return annotations, nil
+}
+
+// jsonResourceAnnotations returns the annotations of the "//" comment property of JSON syntax resources.
+// Every line of the comment may hold an annotation.
+func jsonResourceAnnotations(path string) (map[string]Annotation, error) {
+	jsonFile, err := ReadJSONFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := map[string]Annotation{}
+
+	for _, block := range jsonFile.Blocks("resource") {
+		comment, ok := block.Body[jsonCommentKey].(string)
+		if !ok {
+			continue
+		}
+
+		var annotation Annotation
+		found := false
+
+		for _, line := range strings.Split(comment, "\n") {
+			text := strings.TrimSpace(line)
+			if !strings.HasPrefix(text, "terratag:") {
+				continue
+			}
+
+			if err := parseAnnotation(text, &annotation); err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", path, strings.Join(block.Labels, "."), err)
+			}
+
+			found = true
+		}
+
+		if found {
+			annotations[strings.Join(block.Labels, ".")] = annotation
+		}
+	}
+
+	return annotations, nil
+}
+
+// commentText returns the text of a line holding a single comment, without its markers
+func commentText(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+
+	switch {
+	case strings.HasPrefix(line, "#"):
+		return strings.TrimSpace(line[1:]), true
+	case strings.HasPrefix(line, "//"):
+		return strings.TrimSpace(line[2:]), true
+	case strings.HasPrefix(line, "/*") && strings.HasSuffix(line, "*/") && len(line) >= 4:
+		return strings.TrimSpace(line[2 : len(line)-2]), true
+	}
+
+	return "", false
+}
+
+// parseAnnotation adds an annotation, e.g. terratag:ignore=Owner reason="...", to the annotations of a resource
+func parseAnnotation(text string, annotation *Annotation) error {
+	directive, rest, _ := strings.Cut(text, " ")
+
+	// Tag key lists may have spaces after their commas, e.g. terratag:ignore=Owner, CostCenter
+	for strings.HasSuffix(directive, ",") && strings.TrimSpace(rest) != "" {
+		var key string
+		key, rest, _ = strings.Cut(strings.TrimSpace(rest), " ")
+		directive += key
+	}
+
+	name, value, hasValue := strings.Cut(directive, "=")
+
+	switch name {
+	case annotationIgnore:
+		if !hasValue {
+			annotation.Ignore = true
+
+			break
+		}
+
+		var keys []string
+		for _, key := range strings.Split(value, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				keys = append(keys, key)
+			}
+		}
+
+		if len(keys) == 0 {
+			return fmt.Errorf("invalid annotation %q: %s= needs a comma separated list of tag keys", text, annotationIgnore)
+		}
+
+		annotation.IgnoredTags = append(annotation.IgnoredTags, keys...)
+	case annotationSkipValidation:
+		if hasValue {
+			return fmt.Errorf("invalid annotation %q: %s doesn't take a value", text, annotationSkipValidation)
+		}
+
+		annotation.SkipValidation = true
+	default:
+		return fmt.Errorf("unknown annotation %q, expected %s, %s=<keys> or %s", name, annotationIgnore, annotationIgnore, annotationSkipValidation)
+	}
+
+	annotation.Directives = append(annotation.Directives, directive)
+
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return nil
+	}
+
+	reason, ok := strings.CutPrefix(rest, "reason=")
+	if !ok {
+		return fmt.Errorf("invalid annotation %q: only a reason=\"...\" can follow %s", text, directive)
+	}
+
+	if strings.HasPrefix(reason, `"`) {
+		unquoted, err := strconv.Unquote(reason)
+		if err != nil {
+			return fmt.Errorf("invalid annotation %q: the reason must be a double quoted string", text)
+		}
+
+		reason = unquoted
+	} else if strings.ContainsAny(reason, " \t") {
+		return fmt.Errorf("invalid annotation %q: reasons with spaces must be double quoted", text)
+	}
+
+	if annotation.Reason != "" {
+		annotation.Reason += "; "
+	}
+
+	annotation.Reason += reason
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// FileDiff describes the change a tagging run would make to a single file
+type FileDiff struct {
+	Path       string `json:"path"`
+	TargetPath string `json:"target_path"`
+	Diff       string `json:"diff"`
+}
+
+// GetTargetPath returns the path ReplaceWithTerratagFile would write the tagged content to
+func GetTargetPath(path string, rename bool) string {
+	if rename && !IsTerratagFile(path) {
+		if IsJSONFile(path) {
+			return strings.TrimSuffix(path, jsonSuffix) + terratagSuffix + ".json"
+		}
+
+		return strings.TrimSuffix(path, filepath.Ext(path)) + terratagSuffix
+	}
+
+	return path
+}
+
+// OriginalPath returns the path of the original of a generated file, e.g. main.tf for main.terratag.tf
+func OriginalPath(path string) string {
+	if strings.HasSuffix(path, terratagSuffix+".json") {
+		return strings.TrimSuffix(path, terratagSuffix+".json") + jsonSuffix
+	}
+
+	return strings.TrimSuffix(path, terratagSuffix) + ".tf"
+}
+
+// UnifiedDiff computes a unified diff between the file at path and textContent
+// without touching the file system. An empty Diff means the content is unchanged.
+func UnifiedDiff(path string, textContent string, rename bool) (*FileDiff, error) {
+	fromFile := "a/" + diffPath(path)
+
+	// A missing file is diffed as a newly created one
+	src, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		fromFile = "/dev/null"
+	} else if err != nil {
+		return nil, err
+	}
+
+	targetPath := GetTargetPath(path, rename)
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(src),
+		B:        difflib.SplitLines(textContent),
+		FromFile: fromFile,
+		ToFile:   "b/" + diffPath(targetPath),
+		Context:  3,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileDiff{
+		Path:       path,
+		TargetPath: targetPath,
+		Diff:       diff,
+	}, nil
+}
+
+// diffPath formats a path for a diff header, a/ and b/ prefixes are added by the caller
+func diffPath(path string) string {
+	return strings.TrimPrefix(filepath.ToSlash(path), "/")
+}
+
+func splitLines(src []byte) []string {
+	if len(src) == 0 {
+		return nil
+	}
+
+	return difflib.SplitLines(string(src))
+}
@@ -14,9 +14,20 @@ import (
 func ReplaceWithTerratagFile(path string, textContent string, rename bool) error {
 	backupFilename := path + ".bak"
 
+	// Re-tagging a generated file, its original is already backed up
+	if IsTerratagFile(path) {
+		return CreateFile(path, textContent)
+	}
+
+	// Re-tagging in place, keep the backup of the untagged original
+	if _, err := os.Stat(backupFilename); err == nil && !rename {
+		log.Print("[INFO] Keeping existing backup ", backupFilename)
+
+		return CreateFile(path, textContent)
+	}
+
 	if rename {
-		taggedFilename := strings.TrimSuffix(path, filepath.Ext(path)) + ".terratag.tf"
-		if err := CreateFile(taggedFilename, textContent); err != nil {
+		if err := CreateFile(GetTargetPath(path, rename), textContent); err != nil {
 			return err
 		}
 	}
@@ -36,15 +47,46 @@ func ReplaceWithTerratagFile(path string, textContent string, rename bool) error
 	return nil
 }
 
+// CreateFile writes a file atomically: the content is written to a temporary file in the same directory
+// that is renamed over path, so an interrupted run never leaves a half-written file behind.
 func CreateFile(path string, textContent string) error {
 	log.Print("[INFO] Creating file ", path)
 
-	return os.WriteFile(path, []byte(textContent), 0644)
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(textContent); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// IsTerratagFile returns true if path is a file generated by terratag
+func IsTerratagFile(path string) bool {
+	return strings.HasSuffix(path, terratagSuffix) || strings.HasSuffix(path, terratagSuffix+".json")
 }
 
 func GetFilename(path string) string {
 	_, filename := filepath.Split(path)
-	filename = strings.TrimSuffix(filename, filepath.Ext(path))
+	filename = strings.TrimSuffix(filename, ".json")
+	filename = strings.TrimSuffix(filename, filepath.Ext(filename))
+	// A generated file keeps the locals keys of its original
+	filename = strings.TrimSuffix(filename, ".terratag")
 	filename = strings.ReplaceAll(filename, ".", "-")
 
 	return filename
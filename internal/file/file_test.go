@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/cloudyali/terratag/internal/standards"
 )
 
 func TestReadHCLFile(t *testing.T) {
@@ -260,6 +262,11 @@ func TestGetFilename(t *testing.T) {
 			path:     "./modules/vpc.network/main.tf",
 			expected: "main",
 		},
+		{
+			name:     "generated terratag file",
+			path:     "/path/to/main.terratag.tf",
+			expected: "main",
+		},
 	}
 
 	for _, tt := range tests {
@@ -270,4 +277,530 @@ func TestGetFilename(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestUnifiedDiff(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "terratag-diff-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	original := "resource \"aws_s3_bucket\" \"b\" {\n  bucket = \"b\"\n}\n"
+	tagged := "resource \"aws_s3_bucket\" \"b\" {\n  bucket = \"b\"\n  tags   = local.terratag_added_main\n}\n"
+
+	path := filepath.Join(tmpDir, "main.tf")
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	t.Run("rename targets terratag file", func(t *testing.T) {
+		diff, err := UnifiedDiff(path, tagged, true)
+		if err != nil {
+			t.Fatalf("UnifiedDiff() error = %v", err)
+		}
+
+		if diff.TargetPath != filepath.Join(tmpDir, "main.terratag.tf") {
+			t.Errorf("TargetPath = %s, want main.terratag.tf", diff.TargetPath)
+		}
+		if !strings.Contains(diff.Diff, "+  tags   = local.terratag_added_main") {
+			t.Errorf("Diff does not contain added tags line:\n%s", diff.Diff)
+		}
+		if !strings.Contains(diff.Diff, "+++ b/"+diffPath(diff.TargetPath)) {
+			t.Errorf("Diff header does not reference target path:\n%s", diff.Diff)
+		}
+	})
+
+	t.Run("unchanged content yields empty diff", func(t *testing.T) {
+		diff, err := UnifiedDiff(path, original, false)
+		if err != nil {
+			t.Fatalf("UnifiedDiff() error = %v", err)
+		}
+
+		if diff.Diff != "" {
+			t.Errorf("expected empty diff, got:\n%s", diff.Diff)
+		}
+	})
+
+	t.Run("file system is left untouched", func(t *testing.T) {
+		if _, err := UnifiedDiff(path, tagged, true); err != nil {
+			t.Fatalf("UnifiedDiff() error = %v", err)
+		}
+
+		entries, _ := os.ReadDir(tmpDir)
+		if len(entries) != 1 {
+			t.Errorf("expected only the original file, found %d entries", len(entries))
+		}
+	})
+}
+
+func writeLifecycleFixture(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func assertFileContent(t *testing.T, path string, want string) {
+	t.Helper()
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("Expected %s to exist: %v", path, err)
+		return
+	}
+	if string(got) != want {
+		t.Errorf("Unexpected content of %s: got %q, want %q", path, string(got), want)
+	}
+}
+
+func assertFileMissing(t *testing.T, path string) {
+	t.Helper()
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be removed", path)
+	}
+}
+
+func TestRevert(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeLifecycleFixture(t, tmpDir, map[string]string{
+		"main.tf.bak":                    "original main",
+		"main.terratag.tf":               "tagged main",
+		"inplace.tf.bak":                 "original inplace",
+		"inplace.tf":                     "tagged inplace",
+		"terratag_providers.terratag.tf": "provider",
+		"untouched.tf":                   "untouched",
+		"modules/vpc/vpc.tf.bak":         "original vpc",
+		"modules/vpc/vpc.terratag.tf":    "tagged vpc",
+	})
+
+	result, err := Revert(tmpDir)
+	if err != nil {
+		t.Fatalf("Revert failed: %v", err)
+	}
+
+	if len(result.Restored) != 3 || len(result.Removed) != 3 {
+		t.Errorf("Expected 3 restored and 3 removed files, got %+v", result)
+	}
+
+	assertFileContent(t, filepath.Join(tmpDir, "main.tf"), "original main")
+	assertFileContent(t, filepath.Join(tmpDir, "inplace.tf"), "original inplace")
+	assertFileContent(t, filepath.Join(tmpDir, "modules/vpc/vpc.tf"), "original vpc")
+	assertFileContent(t, filepath.Join(tmpDir, "untouched.tf"), "untouched")
+	assertFileMissing(t, filepath.Join(tmpDir, "main.tf.bak"))
+	assertFileMissing(t, filepath.Join(tmpDir, "main.terratag.tf"))
+	assertFileMissing(t, filepath.Join(tmpDir, "terratag_providers.terratag.tf"))
+	assertFileMissing(t, filepath.Join(tmpDir, "modules/vpc/vpc.terratag.tf"))
+}
+
+func TestRevertTagVariables(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeLifecycleFixture(t, tmpDir, map[string]string{
+		"main.tf.bak":                              "original main",
+		"main.terratag.tf":                         "tagged main",
+		standards.VariablesFileName:                "variables",
+		standards.VariablesExampleFileName:         "example",
+		"envs/prod/main.tf.bak":                    "original prod",
+		"envs/prod/main.terratag.tf":               "tagged prod",
+		"envs/prod/" + standards.VariablesFileName: "variables",
+	})
+
+	result, err := Revert(tmpDir)
+	if err != nil {
+		t.Fatalf("Revert failed: %v", err)
+	}
+
+	if len(result.Restored) != 2 || len(result.Removed) != 5 {
+		t.Errorf("Expected 2 restored and 5 removed files, got %+v", result)
+	}
+
+	assertFileContent(t, filepath.Join(tmpDir, "main.tf"), "original main")
+	assertFileMissing(t, filepath.Join(tmpDir, standards.VariablesFileName))
+	assertFileMissing(t, filepath.Join(tmpDir, standards.VariablesExampleFileName))
+	assertFileMissing(t, filepath.Join(tmpDir, "envs/prod", standards.VariablesFileName))
+}
+
+func TestFinalize(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeLifecycleFixture(t, tmpDir, map[string]string{
+		"main.tf.bak":                    "original main",
+		"main.terratag.tf":               "tagged main",
+		"inplace.tf.bak":                 "original inplace",
+		"inplace.tf":                     "tagged inplace",
+		"terratag_providers.terratag.tf": "provider",
+		standards.VariablesFileName:      "variables",
+	})
+
+	result, err := Finalize(tmpDir)
+	if err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+
+	if len(result.Renamed) != 2 || len(result.Removed) != 2 {
+		t.Errorf("Expected 2 renamed and 2 removed files, got %+v", result)
+	}
+
+	assertFileContent(t, filepath.Join(tmpDir, "main.tf"), "tagged main")
+	assertFileContent(t, filepath.Join(tmpDir, "inplace.tf"), "tagged inplace")
+	assertFileContent(t, filepath.Join(tmpDir, "terratag_providers.tf"), "provider")
+	assertFileMissing(t, filepath.Join(tmpDir, "main.tf.bak"))
+	assertFileMissing(t, filepath.Join(tmpDir, "inplace.tf.bak"))
+	assertFileMissing(t, filepath.Join(tmpDir, "main.terratag.tf"))
+	assertFileContent(t, filepath.Join(tmpDir, standards.VariablesFileName), "variables")
+}
+
+func TestFinalizeRefusesToOverwrite(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeLifecycleFixture(t, tmpDir, map[string]string{
+		"main.tf":          "hand written",
+		"main.terratag.tf": "tagged main",
+	})
+
+	if _, err := Finalize(tmpDir); err == nil {
+		t.Fatal("Expected Finalize to fail when the final path already exists")
+	}
+
+	assertFileContent(t, filepath.Join(tmpDir, "main.tf"), "hand written")
+}
+
+func TestReadJSONFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "main.tf.json")
+
+	content := `{
+  "provider": {"aws": [{"region": "us-east-1"}, {"alias": "west", "region": "us-west-2"}]},
+  "resource": {
+    "aws_s3_bucket": {"logs": {"bucket": "logs", "tags": {"Name": "logs"}}},
+    "aws_instance": {"web": {"ami": "ami-12345", "count": 2, "provider": "aws.west"}}
+  }
+}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	jsonFile, err := ReadJSONFile(path)
+	if err != nil {
+		t.Fatalf("ReadJSONFile failed: %v", err)
+	}
+
+	resources := jsonFile.Blocks("resource")
+	if len(resources) != 2 {
+		t.Fatalf("Expected 2 resources, got %d", len(resources))
+	}
+	if strings.Join(resources[0].Labels, ".") != "aws_instance.web" || strings.Join(resources[1].Labels, ".") != "aws_s3_bucket.logs" {
+		t.Errorf("Unexpected resource labels: %v, %v", resources[0].Labels, resources[1].Labels)
+	}
+
+	if providers := jsonFile.Blocks("provider"); len(providers) != 2 {
+		t.Errorf("Expected 2 provider blocks, got %d", len(providers))
+	}
+
+	hclBlock := resources[0].HCLBlock("resource")
+	if got := strings.TrimSpace(string(hclBlock.Body().GetAttribute("provider").Expr().BuildTokens(nil).Bytes())); got != "aws.west" {
+		t.Errorf("Expected provider aws.west on HCL block, got %s", got)
+	}
+
+	resources[1].Body["tags"].(map[string]interface{})["Owner"] = "DevOps"
+	jsonFile.SetLocal("terratag_added_main", map[string]interface{}{"Owner": "DevOps"})
+
+	out, err := jsonFile.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes failed: %v", err)
+	}
+
+	for _, expected := range []string{`"Owner": "DevOps"`, `"terratag_added_main"`, `"count": 2`} {
+		if !strings.Contains(string(out), expected) {
+			t.Errorf("Expected output to contain %s, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestJSONFileBytesKeepsLayout(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "main.tf.json")
+
+	content := `{
+  "terraform": {"required_version": ">= 1.0"},
+  "resource": {
+    "aws_s3_bucket": {
+      "logs": {"bucket": "logs", "tags": {"Name": "logs"}}
+    },
+    "aws_instance": {"web": {"ami": "ami-12345", "count": 2}}
+  }
+}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	jsonFile, err := ReadJSONFile(path)
+	if err != nil {
+		t.Fatalf("ReadJSONFile failed: %v", err)
+	}
+
+	out, err := jsonFile.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes failed: %v", err)
+	}
+	if string(out) != content {
+		t.Errorf("Expected an unchanged file to be written as it is, got:\n%s", out)
+	}
+
+	for _, resource := range jsonFile.Blocks("resource") {
+		if resource.Labels[0] == "aws_s3_bucket" {
+			resource.Body["tags"].(map[string]interface{})["Owner"] = "DevOps"
+		}
+	}
+
+	out, err = jsonFile.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes failed: %v", err)
+	}
+
+	expected := `{
+  "terraform": {"required_version": ">= 1.0"},
+  "resource": {
+    "aws_s3_bucket": {
+      "logs": {
+        "bucket": "logs",
+        "tags": {
+          "Name": "logs",
+          "Owner": "DevOps"
+        }
+      }
+    },
+    "aws_instance": {"web": {"ami": "ami-12345", "count": 2}}
+  }
+}
+`
+	if string(out) != expected {
+		t.Errorf("Expected only the tagged resource to be rewritten, got:\n%s", out)
+	}
+}
+
+func TestJSONFilePaths(t *testing.T) {
+	if got := GetTargetPath("/infra/main.tf.json", true); got != "/infra/main.terratag.tf.json" {
+		t.Errorf("Unexpected target path %s", got)
+	}
+	if got := GetFilename("/infra/main.tf.json"); got != "main" {
+		t.Errorf("Unexpected filename %s", got)
+	}
+	if got := GetFilename("/infra/main.terratag.tf.json"); got != "main" {
+		t.Errorf("Unexpected filename %s", got)
+	}
+	if !IsTerratagFile("/infra/main.terratag.tf.json") || IsTerratagFile("/infra/main.tf.json") {
+		t.Error("Unexpected IsTerratagFile result for JSON files")
+	}
+}
+
+func TestBlockLines(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	hclPath := filepath.Join(tmpDir, "main.tf")
+	hclContent := `provider "aws" {
+  region = "us-east-1"
+}
+
+provider "aws" {
+  alias  = "west"
+  region = "us-west-2"
+}
+
+resource "aws_instance" "web" {
+  ami = "ami-12345"
+}
+`
+	if err := os.WriteFile(hclPath, []byte(hclContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	jsonPath := filepath.Join(tmpDir, "main.tf.json")
+	jsonContent := `{
+  "resource": {
+    "aws_s3_bucket": {
+      "logs": {"bucket": "logs"}
+    }
+  }
+}`
+	if err := os.WriteFile(jsonPath, []byte(jsonContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	lines := BlockLines(hclPath)
+	if lines["provider.aws"] != 1 {
+		t.Errorf("Expected provider.aws at line 1, got %d", lines["provider.aws"])
+	}
+	if lines["resource.aws_instance.web"] != 10 {
+		t.Errorf("Expected resource.aws_instance.web at line 10, got %d", lines["resource.aws_instance.web"])
+	}
+
+	if got := BlockLines(jsonPath)["resource.aws_s3_bucket.logs"]; got != 4 {
+		t.Errorf("Expected resource.aws_s3_bucket.logs at line 4, got %d", got)
+	}
+
+	if got := BlockLines(filepath.Join(tmpDir, "missing.tf")); len(got) != 0 {
+		t.Errorf("Expected no lines for a missing file, got %v", got)
+	}
+
+	if got := BlockLineRanges(hclPath)["resource.aws_instance.web"]; got != (LineRange{Start: 10, End: 12}) {
+		t.Errorf("Expected resource.aws_instance.web at lines 10-12, got %v", got)
+	}
+
+	if got := BlockLineRanges(jsonPath)["resource.aws_s3_bucket.logs"]; got != (LineRange{Start: 4, End: 7}) {
+		t.Errorf("Expected resource.aws_s3_bucket.logs to end at the last line, got %v", got)
+	}
+}
+
+func TestResourceAnnotations(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	hclPath := filepath.Join(tmpDir, "main.tf")
+	hclContent := `# terratag:ignore reason="managed by the platform team"
+resource "aws_instance" "ignored" {
+  ami = "ami-12345"
+}
+
+// Shared bucket
+// terratag:ignore=Owner, CostCenter
+/* terratag:skip-validation reason=legacy */
+resource "aws_s3_bucket" "shared" {
+  bucket = "shared"
+}
+
+# terratag:ignore
+
+resource "aws_s3_bucket" "detached" {
+  bucket = "detached"
+}
+
+# Not an annotation
+resource "aws_s3_bucket" "plain" {
+  bucket = "plain"
+}
+`
+	if err := os.WriteFile(hclPath, []byte(hclContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	annotations, err := ResourceAnnotations(hclPath)
+	if err != nil {
+		t.Fatalf("ResourceAnnotations failed: %v", err)
+	}
+
+	if len(annotations) != 2 {
+		t.Fatalf("Expected 2 annotated resources, got %v", annotations)
+	}
+
+	ignored := annotations["aws_instance.ignored"]
+	if !ignored.Ignore || !ignored.SkipsValidation() || ignored.Reason != "managed by the platform team" {
+		t.Errorf("Unexpected annotation of aws_instance.ignored: %+v", ignored)
+	}
+
+	shared := annotations["aws_s3_bucket.shared"]
+	if shared.Ignore || !shared.SkipValidation || strings.Join(shared.IgnoredTags, ",") != "Owner,CostCenter" || shared.Reason != "legacy" {
+		t.Errorf("Unexpected annotation of aws_s3_bucket.shared: %+v", shared)
+	}
+	if strings.Join(shared.Directives, " ") != "terratag:ignore=Owner,CostCenter terratag:skip-validation" {
+		t.Errorf("Unexpected directives of aws_s3_bucket.shared: %v", shared.Directives)
+	}
+
+	jsonPath := filepath.Join(tmpDir, "main.tf.json")
+	jsonContent := `{
+  "resource": {
+    "aws_s3_bucket": {
+      "logs": {"//": "terratag:ignore=Owner,CostCenter reason=\"shared logs\"", "bucket": "logs"},
+      "data": {"//": "Data bucket", "bucket": "data"}
+    }
+  }
+}`
+	if err := os.WriteFile(jsonPath, []byte(jsonContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	annotations, err = ResourceAnnotations(jsonPath)
+	if err != nil {
+		t.Fatalf("ResourceAnnotations failed: %v", err)
+	}
+
+	logs, ok := annotations["aws_s3_bucket.logs"]
+	if !ok || len(annotations) != 1 {
+		t.Fatalf("Expected only aws_s3_bucket.logs to be annotated, got %v", annotations)
+	}
+	if strings.Join(logs.IgnoredTags, ",") != "Owner,CostCenter" || logs.Reason != "shared logs" || logs.SkipsValidation() {
+		t.Errorf("Unexpected annotation of aws_s3_bucket.logs: %+v", logs)
+	}
+}
+
+func TestResourceAnnotationsInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+	}{
+		{name: "unknown annotation", annotation: "# terratag:ignored"},
+		{name: "empty key list", annotation: "# terratag:ignore="},
+		{name: "skip-validation with a value", annotation: "# terratag:skip-validation=Owner"},
+		{name: "unexpected text", annotation: "# terratag:ignore because"},
+		{name: "unquoted reason with spaces", annotation: "# terratag:ignore reason=not quoted"},
+		{name: "unterminated reason", annotation: `# terratag:ignore reason="unterminated`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "main.tf")
+			content := tt.annotation + "\nresource \"aws_instance\" \"web\" {\n  ami = \"ami-12345\"\n}\n"
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+
+			if _, err := ResourceAnnotations(path); err == nil || !strings.Contains(err.Error(), "main.tf:1") {
+				t.Errorf("Expected an error at main.tf:1, got %v", err)
+			}
+		})
+	}
+}
+
+func TestKeepLayout(t *testing.T) {
+	original := `resource "aws_instance" "web" {
+    ami="ami-12345"   # not formatted
+  tags = { Owner = "platform" }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket="logs"
+}
+`
+	edited := `resource "aws_instance" "web" {
+    ami="ami-12345"   # not formatted
+  tags = { Owner = "platform", Team  =  "infra" }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket="logs"
+  tags = {
+  Owner="platform"
+  }
+}
+`
+	expected := `resource "aws_instance" "web" {
+    ami="ami-12345"   # not formatted
+  tags = { Owner = "platform", Team = "infra" }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket="logs"
+  tags = {
+    Owner = "platform"
+  }
+}
+`
+	if kept := string(KeepLayout([]byte(original), []byte(edited))); kept != expected {
+		t.Errorf("KeepLayout() =\n%s\nwant:\n%s", kept, expected)
+	}
+}
@@ -0,0 +1,403 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+const jsonSuffix = ".tf.json"
+
+// JSONFile is a Terraform JSON syntax configuration (.tf.json) decoded for tagging.
+// Blocks returned by the file share their bodies with it, changes are written back by Bytes.
+type JSONFile struct {
+	root map[string]interface{}
+	src  []byte
+	node *jsonNode // Layout of the values of src
+}
+
+// jsonNode is where a value is in the source of a JSON file, with the members of objects in their source order
+type jsonNode struct {
+	start   int
+	end     int
+	entry   int // Start of the key of an object member, or of the value of an array item
+	keys    []string
+	members map[string]*jsonNode
+	items   []*jsonNode
+}
+
+// JSONBlock is a labeled block of a JSON syntax configuration, e.g. a resource or a provider
+type JSONBlock struct {
+	Labels []string
+	Body   map[string]interface{}
+}
+
+// blockLabelsCount is the number of labels of the JSON block types terratag reads
+var blockLabelsCount = map[string]int{
+	"resource": 2,
+	"provider": 1,
+	"module":   1,
+}
+
+// IsJSONFile returns true if path is a Terraform JSON syntax configuration
+func IsJSONFile(path string) bool {
+	return strings.HasSuffix(path, jsonSuffix)
+}
+
+func ReadJSONFile(path string) (*JSONFile, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(src))
+	// Keep numbers as written instead of converting them to float64
+	decoder.UseNumber()
+
+	root := map[string]interface{}{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	node, err := parseJSONNode(json.NewDecoder(bytes.NewReader(src)), src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return &JSONFile{root: root, src: src, node: node}, nil
+}
+
+// parseJSONNode reads the layout of the next value of a decoder
+func parseJSONNode(decoder *json.Decoder, src []byte) (*jsonNode, error) {
+	start := nextJSONToken(decoder, src)
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &jsonNode{start: start, entry: start}
+
+	switch token {
+	case json.Delim('{'):
+		node.members = map[string]*jsonNode{}
+
+		for decoder.More() {
+			entry := nextJSONToken(decoder, src)
+
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			member, err := parseJSONNode(decoder, src)
+			if err != nil {
+				return nil, err
+			}
+
+			member.entry = entry
+
+			if _, ok := node.members[key.(string)]; !ok {
+				node.keys = append(node.keys, key.(string))
+			}
+
+			node.members[key.(string)] = member
+		}
+
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	case json.Delim('['):
+		for decoder.More() {
+			item, err := parseJSONNode(decoder, src)
+			if err != nil {
+				return nil, err
+			}
+
+			node.items = append(node.items, item)
+		}
+
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	node.end = int(decoder.InputOffset())
+
+	return node, nil
+}
+
+// nextJSONToken returns the offset of the next token of a decoder. The offset of the decoder is after the previous
+// token, the separators before the next one are skipped.
+func nextJSONToken(decoder *json.Decoder, src []byte) int {
+	offset := int(decoder.InputOffset())
+	for offset < len(src) && strings.ContainsRune(" \t\r\n:,", rune(src[offset])) {
+		offset++
+	}
+
+	return offset
+}
+
+// Blocks returns the blocks of the given type (resource or provider).
+// Both the object and the array forms of the JSON syntax are supported.
+func (f *JSONFile) Blocks(blockType string) []*JSONBlock {
+	var blocks []*JSONBlock
+
+	collectJSONBlocks(f.root[blockType], blockLabelsCount[blockType], nil, &blocks)
+
+	return blocks
+}
+
+// SetLocal sets a local value, adding a locals object if the file has none
+func (f *JSONFile) SetLocal(key string, value interface{}) {
+	switch locals := f.root["locals"].(type) {
+	case map[string]interface{}:
+		locals[key] = value
+
+		return
+	case []interface{}:
+		var first map[string]interface{}
+
+		for _, item := range locals {
+			if obj, ok := item.(map[string]interface{}); ok {
+				if _, exists := obj[key]; exists {
+					obj[key] = value
+
+					return
+				}
+
+				if first == nil {
+					first = obj
+				}
+			}
+		}
+
+		if first != nil {
+			first[key] = value
+
+			return
+		}
+	}
+
+	f.root["locals"] = map[string]interface{}{key: value}
+}
+
+// Bytes encodes the configuration back to JSON. Values that weren't changed are written as they are in the source,
+// the members of changed objects keep their source order, new members are added in sorted order.
+func (f *JSONFile) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.Write(f.src[:f.node.start])
+
+	if err := f.writeValue(&buf, f.root, f.node, ""); err != nil {
+		return nil, err
+	}
+
+	buf.Write(f.src[f.node.end:])
+
+	return buf.Bytes(), nil
+}
+
+// writeValue writes a value, indent is the indentation of the line the value starts on
+func (f *JSONFile) writeValue(buf *bytes.Buffer, value interface{}, node *jsonNode, indent string) error {
+	if node != nil && f.unchanged(value, node) {
+		buf.Write(f.src[node.start:node.end])
+
+		return nil
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			buf.WriteString("{}")
+
+			return nil
+		}
+
+		var keys, added []string
+
+		if node != nil {
+			for _, key := range node.keys {
+				if _, ok := v[key]; ok {
+					keys = append(keys, key)
+				}
+			}
+		}
+
+		for key := range v {
+			if node == nil || node.members[key] == nil {
+				added = append(added, key)
+			}
+		}
+
+		sort.Strings(added)
+
+		buf.WriteString("{")
+
+		for i, key := range append(keys, added...) {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+
+			var member *jsonNode
+			if node != nil {
+				member = node.members[key]
+			}
+
+			memberIndent := f.entryIndent(member, indent)
+
+			buf.WriteString("\n" + memberIndent)
+
+			if err := writeScalar(buf, key); err != nil {
+				return err
+			}
+
+			buf.WriteString(": ")
+
+			if err := f.writeValue(buf, v[key], member, memberIndent); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteString("\n" + indent + "}")
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+
+			return nil
+		}
+
+		buf.WriteString("[")
+
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+
+			var itemNode *jsonNode
+			if node != nil && i < len(node.items) {
+				itemNode = node.items[i]
+			}
+
+			itemIndent := f.entryIndent(itemNode, indent)
+
+			buf.WriteString("\n" + itemIndent)
+
+			if err := f.writeValue(buf, item, itemNode, itemIndent); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteString("\n" + indent + "]")
+	case nil, string, bool, json.Number, float64, int:
+		return writeScalar(buf, v)
+	default:
+		// Other types set by the callers, e.g. map[string]string, are written as their JSON values
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		var decoded interface{}
+		if err := decoder.Decode(&decoded); err != nil {
+			return err
+		}
+
+		return f.writeValue(buf, decoded, nil, indent)
+	}
+
+	return nil
+}
+
+// unchanged returns true if a value is the value of the source
+func (f *JSONFile) unchanged(value interface{}, node *jsonNode) bool {
+	decoder := json.NewDecoder(bytes.NewReader(f.src[node.start:node.end]))
+	decoder.UseNumber()
+
+	var original interface{}
+	if err := decoder.Decode(&original); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(value, original)
+}
+
+// entryIndent returns the indentation of a member or an item of a value indented by indent. Entries that start their
+// source line keep its indentation.
+func (f *JSONFile) entryIndent(node *jsonNode, indent string) string {
+	if node != nil {
+		line := f.src[bytes.LastIndexByte(f.src[:node.entry], '\n')+1 : node.entry]
+		if len(bytes.TrimLeft(line, " \t")) == 0 {
+			return string(line)
+		}
+	}
+
+	return indent + "  "
+}
+
+func writeScalar(buf *bytes.Buffer, value interface{}) error {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+
+	// Encode terminates the value with a newline
+	buf.Truncate(buf.Len() - 1)
+
+	return nil
+}
+
+// HCLBlock returns a header-only hclwrite block equivalent to the JSON block.
+// It's used to share schema lookups and keys between the native and the JSON syntax.
+func (b *JSONBlock) HCLBlock(blockType string) *hclwrite.Block {
+	block := hclwrite.NewBlock(blockType, b.Labels)
+
+	if provider, ok := b.Body["provider"].(string); ok {
+		block.Body().SetAttributeRaw("provider", hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(provider)},
+		})
+	}
+
+	return block
+}
+
+func collectJSONBlocks(value interface{}, labelsLeft int, labels []string, blocks *[]*JSONBlock) {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			collectJSONBlocks(item, labelsLeft, labels, blocks)
+		}
+	case map[string]interface{}:
+		if labelsLeft == 0 {
+			*blocks = append(*blocks, &JSONBlock{
+				Labels: append([]string{}, labels...),
+				Body:   v,
+			})
+
+			return
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			collectJSONBlocks(v[key], labelsLeft-1, append(labels, key), blocks)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package file
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// KeepLayout returns the edited content of a native syntax file formatted, with the lines that weren't edited kept as
+// they are in the original. hclwrite re-spaces every token of the files it writes, so that formatting the whole
+// edited file would also rewrite the lines that weren't edited.
+func KeepLayout(original []byte, edited []byte) []byte {
+	originalLines := strings.SplitAfter(string(original), "\n")
+	formattedOriginal := strings.SplitAfter(string(hclwrite.Format(original)), "\n")
+	formattedEdited := strings.SplitAfter(string(hclwrite.Format(edited)), "\n")
+
+	// Formatting only changes the spaces of the lines, the lines of the original and of its formatted content match
+	if len(originalLines) != len(formattedOriginal) {
+		return hclwrite.Format(edited)
+	}
+
+	var kept strings.Builder
+
+	matcher := difflib.NewMatcherWithJunk(formattedOriginal, formattedEdited, false, nil)
+	for _, opCode := range matcher.GetOpCodes() {
+		lines := formattedEdited[opCode.J1:opCode.J2]
+		if opCode.Tag == 'e' {
+			lines = originalLines[opCode.I1:opCode.I2]
+		}
+
+		for _, line := range lines {
+			kept.WriteString(line)
+		}
+	}
+
+	return []byte(kept.String())
+}
@@ -0,0 +1,138 @@
+package file
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudyali/terratag/internal/standards"
+)
+
+const (
+	backupSuffix   = ".bak"
+	terratagSuffix = ".terratag.tf"
+)
+
+// LifecycleResult summarises the files touched by Revert or Finalize
+type LifecycleResult struct {
+	Restored []string // Originals restored from their .bak backup
+	Removed  []string // Generated .terratag.tf files, tag variables files or backups deleted
+	Renamed  []string // Generated .terratag.tf files folded into their final name
+}
+
+// Revert undoes a tagging run under dir: every <name>.tf.bak (or .tf.json.bak) backup is moved back
+// to its original and every generated .terratag.tf (or .terratag.tf.json) file and tag variables file is deleted
+func Revert(dir string) (*LifecycleResult, error) {
+	backups, generated, variables, err := findTerratagFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LifecycleResult{}
+
+	for _, backup := range backups {
+		original := strings.TrimSuffix(backup, backupSuffix)
+
+		log.Print("[INFO] Restoring ", original, " from ", backup)
+
+		if err := os.Rename(backup, original); err != nil {
+			return result, fmt.Errorf("failed to restore %s: %w", original, err)
+		}
+
+		result.Restored = append(result.Restored, original)
+	}
+
+	for _, path := range append(generated, variables...) {
+		log.Print("[INFO] Removing generated file ", path)
+
+		if err := os.Remove(path); err != nil {
+			return result, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+
+		result.Removed = append(result.Removed, path)
+	}
+
+	return result, nil
+}
+
+// Finalize accepts a reviewed tagging run under dir: every generated <name>.terratag.tf file
+// replaces its original <name>.tf and all .bak backups are deleted. Generated files without
+// an original (e.g. the providers file) are renamed to drop the .terratag part. Tag variables
+// files already have their final name and are kept.
+func Finalize(dir string) (*LifecycleResult, error) {
+	backups, generated, _, err := findTerratagFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LifecycleResult{}
+
+	for _, path := range generated {
+		final := OriginalPath(path)
+
+		// The original was moved to a backup, a file at the final path is not ours to overwrite
+		if _, err := os.Stat(final); err == nil {
+			return result, fmt.Errorf("failed to finalize %s: %s already exists", path, final)
+		}
+
+		log.Print("[INFO] Renaming ", path, " to ", final)
+
+		if err := os.Rename(path, final); err != nil {
+			return result, fmt.Errorf("failed to finalize %s: %w", path, err)
+		}
+
+		result.Renamed = append(result.Renamed, final)
+	}
+
+	for _, backup := range backups {
+		log.Print("[INFO] Removing backup ", backup)
+
+		if err := os.Remove(backup); err != nil {
+			return result, fmt.Errorf("failed to remove %s: %w", backup, err)
+		}
+
+		result.Removed = append(result.Removed, backup)
+	}
+
+	return result, nil
+}
+
+// findTerratagFiles returns the backups, generated files and generated tag variables files a tagging run
+// left under dir
+func findTerratagFiles(dir string) ([]string, []string, []string, error) {
+	var backups, generated, variables []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			// Provider binaries are never tagged, modules under .terraform/modules are
+			if d.Name() == "providers" && filepath.Base(filepath.Dir(path)) == ".terraform" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		switch {
+		case strings.HasSuffix(path, ".tf"+backupSuffix), strings.HasSuffix(path, jsonSuffix+backupSuffix):
+			backups = append(backups, path)
+		case IsTerratagFile(path):
+			generated = append(generated, path)
+		case d.Name() == standards.VariablesFileName, d.Name() == standards.VariablesExampleFileName:
+			variables = append(variables, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return backups, generated, variables, nil
+}
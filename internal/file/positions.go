@@ -0,0 +1,85 @@
+package file
+
+import (
+	"bytes"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// LineRange is the first and last line (inclusive) of a block
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// BlockLines returns the line each resource and provider block of a file starts at,
+// keyed by the block type and labels, e.g. "resource.aws_instance.web" or "provider.aws".
+// Files that can't be parsed return an empty map, line numbers are informational only.
+func BlockLines(path string) map[string]int {
+	lines := map[string]int{}
+
+	for key, lineRange := range BlockLineRanges(path) {
+		lines[key] = lineRange.Start
+	}
+
+	return lines
+}
+
+// BlockLineRanges returns the lines each resource and provider block of a file spans, keyed like BlockLines.
+// The parser doesn't track where the blocks of a JSON syntax file end, they end at the last line of the file.
+func BlockLineRanges(path string) map[string]LineRange {
+	ranges := map[string]LineRange{}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ranges
+	}
+
+	parser := hclparse.NewParser()
+
+	// The backups of JSON syntax files are JSON too
+	var parsed *hcl.File
+	if IsJSONFile(strings.TrimSuffix(path, backupSuffix)) {
+		parsed, _ = parser.ParseJSON(content, path)
+	} else {
+		parsed, _ = parser.ParseHCL(content, path)
+	}
+
+	if parsed == nil || parsed.Body == nil {
+		return ranges
+	}
+
+	body, _, _ := parsed.Body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "resource", LabelNames: []string{"type", "name"}},
+			{Type: "provider", LabelNames: []string{"name"}},
+		},
+	})
+
+	lastLine := bytes.Count(content, []byte("\n")) + 1
+
+	for _, block := range body.Blocks {
+		key := block.Type
+		for _, label := range block.Labels {
+			key += "." + label
+		}
+
+		// Keep the first provider block when aliases declare several
+		if _, ok := ranges[key]; ok {
+			continue
+		}
+
+		lineRange := LineRange{Start: block.DefRange.Start.Line, End: lastLine}
+		if syntaxBody, ok := block.Body.(*hclsyntax.Body); ok {
+			lineRange.End = syntaxBody.SrcRange.End.Line
+		}
+
+		ranges[key] = lineRange
+	}
+
+	return ranges
+}
@@ -1,6 +1,7 @@
 package integration
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -59,7 +60,7 @@ resource "aws_instance" "test" {
 
 	// Test 1: Initialize schemas for first directory with cache enabled
 	t.Log("Testing first directory with cache enabled")
-	err = tfschema.InitProviderSchemasWithCache(testDir1, common.Terraform, false, true)
+	err = tfschema.InitProviderSchemasWithCache(context.Background(), testDir1, common.Terraform, false, true, common.CommandTimeouts{})
 	
 	// This might fail if terraform isn't initialized, which is expected in CI
 	if err != nil {
@@ -70,14 +71,14 @@ resource "aws_instance" "test" {
 	// Test 2: Initialize schemas for second directory with cache enabled
 	// This should use the cached schema if available
 	t.Log("Testing second directory with cache enabled (should use cache)")
-	err = tfschema.InitProviderSchemasWithCache(testDir2, common.Terraform, false, true)
+	err = tfschema.InitProviderSchemasWithCache(context.Background(), testDir2, common.Terraform, false, true, common.CommandTimeouts{})
 	if err != nil {
 		t.Logf("Schema initialization failed for second directory: %v", err)
 	}
 
 	// Test 3: Test with cache disabled
 	t.Log("Testing with cache disabled")
-	err = tfschema.InitProviderSchemasWithCache(testDir1, common.Terraform, false, false)
+	err = tfschema.InitProviderSchemasWithCache(context.Background(), testDir1, common.Terraform, false, false, common.CommandTimeouts{})
 	if err != nil {
 		t.Logf("Schema initialization without cache failed: %v", err)
 	}
@@ -145,7 +146,7 @@ resource "aws_instance" "test" {
 	// Test multiple initializations with cache
 	for i := 0; i < 3; i++ {
 		t.Logf("Cache-enabled initialization attempt %d", i+1)
-		err = tfschema.InitProviderSchemasWithCache(testDir, common.Terraform, false, true)
+		err = tfschema.InitProviderSchemasWithCache(context.Background(), testDir, common.Terraform, false, true, common.CommandTimeouts{})
 		
 		if err != nil {
 			t.Logf("Schema initialization failed (expected in CI without terraform init): %v", err)
@@ -177,7 +178,7 @@ provider "aws" {
 
 	// Test with cache disabled
 	t.Log("Testing schema initialization with cache disabled")
-	err = tfschema.InitProviderSchemasWithCache(testDir, common.Terraform, false, false)
+	err = tfschema.InitProviderSchemasWithCache(context.Background(), testDir, common.Terraform, false, false, common.CommandTimeouts{})
 	
 	if err != nil {
 		t.Logf("Schema initialization failed (expected without terraform init): %v", err)
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -16,6 +17,7 @@ import (
 	"time"
 
 	"github.com/cloudyali/terratag/internal/common"
+	"github.com/cloudyali/terratag/internal/utils"
 )
 
 // CacheManager manages a centralized provider cache to avoid storage bloat
@@ -323,8 +325,8 @@ func (cm *CacheManager) copyProviderConfig(sourceDir, targetDir string, iacType
 	return nil
 }
 
-// InitProviders initializes providers in the shared directory
-func (cm *CacheManager) InitProviders(terraformDir string, iacType common.IACType, defaultToTerraform bool) error {
+// InitProviders initializes providers in the shared directory, a timeout of 0 disables the timeout of the init command
+func (cm *CacheManager) InitProviders(ctx context.Context, terraformDir string, iacType common.IACType, defaultToTerraform bool, timeout time.Duration) error {
 	name := "terraform"
 	if iacType == common.Terragrunt || iacType == common.TerragruntRunAll {
 		name = "terragrunt"
@@ -334,7 +336,7 @@ func (cm *CacheManager) InitProviders(terraformDir string, iacType common.IACTyp
 
 	log.Printf("[INFO] Initializing providers in shared directory: %s", terraformDir)
 
-	cmd := exec.Command(name, "init", "-backend=false")
+	cmd := utils.NewCommand(ctx, timeout, name, "init", "-backend=false")
 	cmd.Dir = terraformDir
 
 	output, err := cmd.CombinedOutput()
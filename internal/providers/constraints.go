@@ -0,0 +1,187 @@
+package providers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ConstraintKind is the kind of limit of a cloud provider a tag breaks
+type ConstraintKind string
+
+const (
+	ConstraintTagLimit          ConstraintKind = "tag_limit_exceeded"    // More tags than the provider allows on a resource
+	ConstraintKeyLength         ConstraintKind = "key_length_exceeded"   // Key longer than the provider allows
+	ConstraintValueLength       ConstraintKind = "value_length_exceeded" // Value longer than the provider allows
+	ConstraintReservedPrefix    ConstraintKind = "reserved_prefix"       // Key prefix reserved by the provider
+	ConstraintInvalidCharacters ConstraintKind = "invalid_characters"    // Key or value with characters the provider rejects
+)
+
+// TagConstraints are the limits a cloud provider puts on the tags (or labels) of a resource
+type TagConstraints struct {
+	Provider          Provider
+	MaxTags           int            // Maximum number of tags of a resource
+	MaxKeyLength      int            // Maximum length of a key, in characters
+	MaxValueLength    int            // Maximum length of a value, in characters
+	ReservedPrefixes  []string       // Key prefixes reserved by the provider, compared case insensitively
+	ForbiddenKeyChars string         // Characters keys can't contain
+	KeyPattern        *regexp.Regexp // Pattern keys must match, nil if any key is allowed
+	ValuePattern      *regexp.Regexp // Pattern values must match, nil if any value is allowed
+	CharacterSet      string         // Description of the characters KeyPattern and ValuePattern allow
+}
+
+var tagConstraints = map[Provider]*TagConstraints{
+	AWS: {
+		Provider:         AWS,
+		MaxTags:          50,
+		MaxKeyLength:     128,
+		MaxValueLength:   256,
+		ReservedPrefixes: []string{"aws:"},
+	},
+	GCP: {
+		Provider:       GCP,
+		MaxTags:        64,
+		MaxKeyLength:   63,
+		MaxValueLength: 63,
+		KeyPattern:     regexp.MustCompile(`^[a-z][a-z0-9_-]*$`),
+		ValuePattern:   regexp.MustCompile(`^[a-z0-9_-]*$`),
+		CharacterSet:   "lowercase letters, digits, underscores and dashes, keys starting with a letter",
+	},
+	AZURE: {
+		Provider:          AZURE,
+		MaxTags:           50,
+		MaxKeyLength:      512,
+		MaxValueLength:    256,
+		ForbiddenKeyChars: `<>%&\?/`,
+	},
+}
+
+// ConstraintViolation is a tag, or the tag count of a resource, breaking a limit of the cloud provider
+type ConstraintViolation struct {
+	Kind    ConstraintKind
+	Key     string // Empty for the tag count
+	Value   string
+	Message string
+}
+
+// GetTagConstraints returns the tag constraints of the provider of a resource type, or of the fallback provider
+// (e.g. the cloud provider of a tag standard) when the resource type has no known provider prefix.
+// Unknown providers return nil.
+func GetTagConstraints(resourceType string, fallback string) *TagConstraints {
+	provider := getProviderByResource(resourceType)
+	if provider == "" {
+		provider = Provider(fallback)
+	}
+
+	return tagConstraints[provider]
+}
+
+// Check returns the violations of the number of tags of a resource and of every tag, sorted by key
+func (c *TagConstraints) Check(tags map[string]string) []ConstraintViolation {
+	var violations []ConstraintViolation
+
+	if violation := c.CheckCount(len(tags)); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		violations = append(violations, c.CheckKey(key)...)
+		violations = append(violations, c.CheckValue(key, tags[key])...)
+	}
+
+	return violations
+}
+
+// CheckCount returns a violation if a resource with count tags has more tags than the provider allows
+func (c *TagConstraints) CheckCount(count int) *ConstraintViolation {
+	if c.MaxTags == 0 || count <= c.MaxTags {
+		return nil
+	}
+
+	return &ConstraintViolation{
+		Kind:    ConstraintTagLimit,
+		Message: fmt.Sprintf("Resource has %d %s, %s allows at most %d", count, c.tagsNoun(), c.Provider, c.MaxTags),
+	}
+}
+
+// CheckKey returns the violations of a tag key
+func (c *TagConstraints) CheckKey(key string) []ConstraintViolation {
+	var violations []ConstraintViolation
+
+	if length := utf8.RuneCountInString(key); c.MaxKeyLength > 0 && length > c.MaxKeyLength {
+		violations = append(violations, ConstraintViolation{
+			Kind:    ConstraintKeyLength,
+			Key:     key,
+			Message: fmt.Sprintf("Key '%s' is %d characters long, %s allows at most %d", key, length, c.Provider, c.MaxKeyLength),
+		})
+	}
+
+	for _, prefix := range c.ReservedPrefixes {
+		if strings.HasPrefix(strings.ToLower(key), prefix) {
+			violations = append(violations, ConstraintViolation{
+				Kind:    ConstraintReservedPrefix,
+				Key:     key,
+				Message: fmt.Sprintf("Key '%s' starts with '%s', which is reserved by %s", key, prefix, c.Provider),
+			})
+		}
+	}
+
+	if c.ForbiddenKeyChars != "" && strings.ContainsAny(key, c.ForbiddenKeyChars) {
+		violations = append(violations, ConstraintViolation{
+			Kind:    ConstraintInvalidCharacters,
+			Key:     key,
+			Message: fmt.Sprintf("Key '%s' contains one of the characters %s doesn't allow: %s", key, c.Provider, c.ForbiddenKeyChars),
+		})
+	}
+
+	if c.KeyPattern != nil && !c.KeyPattern.MatchString(key) {
+		violations = append(violations, ConstraintViolation{
+			Kind:    ConstraintInvalidCharacters,
+			Key:     key,
+			Message: fmt.Sprintf("Key '%s' must only contain %s", key, c.CharacterSet),
+		})
+	}
+
+	return violations
+}
+
+// CheckValue returns the violations of a tag value
+func (c *TagConstraints) CheckValue(key string, value string) []ConstraintViolation {
+	var violations []ConstraintViolation
+
+	if length := utf8.RuneCountInString(value); c.MaxValueLength > 0 && length > c.MaxValueLength {
+		violations = append(violations, ConstraintViolation{
+			Kind:    ConstraintValueLength,
+			Key:     key,
+			Value:   value,
+			Message: fmt.Sprintf("Value of '%s' is %d characters long, %s allows at most %d", key, length, c.Provider, c.MaxValueLength),
+		})
+	}
+
+	if c.ValuePattern != nil && !c.ValuePattern.MatchString(value) {
+		violations = append(violations, ConstraintViolation{
+			Kind:    ConstraintInvalidCharacters,
+			Key:     key,
+			Value:   value,
+			Message: fmt.Sprintf("Value '%s' of '%s' must only contain %s", value, key, c.CharacterSet),
+		})
+	}
+
+	return violations
+}
+
+func (c *TagConstraints) tagsNoun() string {
+	if c.Provider == GCP {
+		return "labels"
+	}
+
+	return "tags"
+}
@@ -0,0 +1,139 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetTagConstraints(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceType string
+		fallback     string
+		expected     Provider
+	}{
+		{name: "AWS resource", resourceType: "aws_instance", expected: AWS},
+		{name: "GCP resource", resourceType: "google_storage_bucket", fallback: "aws", expected: GCP},
+		{name: "Azure resource", resourceType: "azurerm_resource_group", expected: AZURE},
+		{name: "unknown prefix uses the fallback", resourceType: "custom_resource", fallback: "gcp", expected: GCP},
+		{name: "unknown provider", resourceType: "custom_resource", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			constraints := GetTagConstraints(tt.resourceType, tt.fallback)
+			if tt.expected == "" {
+				if constraints != nil {
+					t.Errorf("GetTagConstraints(%s) = %v, expected nil", tt.resourceType, constraints.Provider)
+				}
+
+				return
+			}
+
+			if constraints == nil || constraints.Provider != tt.expected {
+				t.Errorf("GetTagConstraints(%s) = %v, expected %s", tt.resourceType, constraints, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTagConstraintsCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider Provider
+		tags     map[string]string
+		expected []ConstraintKind
+	}{
+		{
+			name:     "valid AWS tags",
+			provider: AWS,
+			tags:     map[string]string{"Environment": "prod", "Cost Center": "1234/ops"},
+		},
+		{
+			name:     "AWS reserved prefix",
+			provider: AWS,
+			tags:     map[string]string{"AWS:CloudFormation": "stack"},
+			expected: []ConstraintKind{ConstraintReservedPrefix},
+		},
+		{
+			name:     "AWS key and value lengths",
+			provider: AWS,
+			tags:     map[string]string{strings.Repeat("k", 129): strings.Repeat("v", 257)},
+			expected: []ConstraintKind{ConstraintKeyLength, ConstraintValueLength},
+		},
+		{
+			name:     "valid GCP labels",
+			provider: GCP,
+			tags:     map[string]string{"environment": "prod", "cost-center": "ops_1234", "empty": ""},
+		},
+		{
+			name:     "GCP uppercase key and value",
+			provider: GCP,
+			tags:     map[string]string{"Environment": "Prod"},
+			expected: []ConstraintKind{ConstraintInvalidCharacters, ConstraintInvalidCharacters},
+		},
+		{
+			name:     "GCP key starting with a digit",
+			provider: GCP,
+			tags:     map[string]string{"1team": "ops"},
+			expected: []ConstraintKind{ConstraintInvalidCharacters},
+		},
+		{
+			name:     "GCP lengths count characters",
+			provider: GCP,
+			tags:     map[string]string{"team": strings.Repeat("é", 63), strings.Repeat("k", 64): "ops"},
+			expected: []ConstraintKind{ConstraintKeyLength, ConstraintInvalidCharacters},
+		},
+		{
+			name:     "Azure forbidden characters",
+			provider: AZURE,
+			tags:     map[string]string{"cost/center": "ops", "team": "a<b"},
+			expected: []ConstraintKind{ConstraintInvalidCharacters},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := tagConstraints[tt.provider].Check(tt.tags)
+
+			var kinds []ConstraintKind
+			for _, violation := range violations {
+				kinds = append(kinds, violation.Kind)
+			}
+
+			if strings.Join(kindStrings(kinds), ",") != strings.Join(kindStrings(tt.expected), ",") {
+				t.Errorf("Check() = %v, expected %v", violations, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTagConstraintsCheckCount(t *testing.T) {
+	constraints := tagConstraints[AWS]
+
+	if violation := constraints.CheckCount(50); violation != nil {
+		t.Errorf("CheckCount(50) = %v, expected nil", violation)
+	}
+
+	violation := constraints.CheckCount(51)
+	if violation == nil || violation.Kind != ConstraintTagLimit {
+		t.Fatalf("CheckCount(51) = %v, expected a tag limit violation", violation)
+	}
+
+	if violation.Message != "Resource has 51 tags, aws allows at most 50" {
+		t.Errorf("CheckCount(51) message = %s", violation.Message)
+	}
+
+	if violation := tagConstraints[GCP].CheckCount(65); violation == nil || !strings.Contains(violation.Message, "65 labels") {
+		t.Errorf("CheckCount(65) = %v, expected a label limit violation", violation)
+	}
+}
+
+func kindStrings(kinds []ConstraintKind) []string {
+	strs := make([]string, len(kinds))
+	for i, kind := range kinds {
+		strs[i] = string(kind)
+	}
+
+	return strs
+}
@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// maxLabelValueLength is the maximum length of a Google label value
+const maxLabelValueLength = 63
+
+var invalidLabelValueCharacters = regexp.MustCompile(`[^a-z0-9_-]`)
+
+// UsesLabels returns true if the resources of a resource type are labelled with Google labels, the fallback provider
+// (e.g. the cloud provider of a tag standard) is used when the resource type has no known provider prefix
+func UsesLabels(resourceType string, fallback string) bool {
+	constraints := GetTagConstraints(resourceType, fallback)
+
+	return constraints != nil && constraints.Provider == GCP
+}
+
+// SanitizeLabelValue transforms a tag value into a valid Google label value: lower cased, with the characters other
+// than letters, digits, underscores and dashes replaced by underscores, truncated to 63 characters
+func SanitizeLabelValue(value string) string {
+	value = invalidLabelValueCharacters.ReplaceAllString(strings.ToLower(value), "_")
+	if len(value) > maxLabelValueLength {
+		value = value[:maxLabelValueLength]
+	}
+
+	return value
+}
+
+// SanitizeLabelValueExpression wraps an HCL expression in the functions applying the SanitizeLabelValue
+// transformation when Terraform evaluates it
+func SanitizeLabelValueExpression(expression string) string {
+	return fmt.Sprintf(`substr(replace(lower(%s), "/%s/", "_"), 0, %d)`, expression, invalidLabelValueCharacters, maxLabelValueLength)
+}
+
+// LabelValueSanitizer sanitizes tag values into Google label values and detects the collisions, where different
+// values of a key are sanitized to the same label value. It is safe for concurrent use.
+type LabelValueSanitizer struct {
+	mu         sync.Mutex
+	values     map[string]map[string]string // First value sanitized to each label value, by key
+	collisions map[string]bool              // Collisions already reported, by key and values
+}
+
+func NewLabelValueSanitizer() *LabelValueSanitizer {
+	return &LabelValueSanitizer{
+		values:     map[string]map[string]string{},
+		collisions: map[string]bool{},
+	}
+}
+
+// SanitizeLabelValue returns the label value of the value of a tag. collision is the other value of the key that was
+// sanitized to the same label value before, it is only returned the first time the collision is detected.
+func (s *LabelValueSanitizer) SanitizeLabelValue(key string, value string) (label string, collision string) {
+	label = SanitizeLabelValue(value)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.values[key] == nil {
+		s.values[key] = map[string]string{}
+	}
+
+	previous, ok := s.values[key][label]
+	if !ok {
+		s.values[key][label] = value
+
+		return label, ""
+	}
+
+	collisionKey := strings.Join([]string{key, previous, value}, "\x00")
+	if previous == value || s.collisions[collisionKey] {
+		return label, ""
+	}
+
+	s.collisions[collisionKey] = true
+
+	return label, previous
+}
@@ -0,0 +1,76 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeLabelValue(t *testing.T) {
+	tests := map[string]string{
+		"Platform Team":           "platform_team",
+		"platform-team":           "platform-team",
+		"Jane.Doe@corp.com":       "jane_doe_corp_com",
+		"":                        "",
+		strings.Repeat("a", 70):   strings.Repeat("a", 63),
+		"Équipe Données":          "_quipe_donn_es",
+		"cost_center-1234/europe": "cost_center-1234_europe",
+	}
+
+	for value, expected := range tests {
+		if label := SanitizeLabelValue(value); label != expected {
+			t.Errorf("SanitizeLabelValue(%q) = %q, expected %q", value, label, expected)
+		}
+	}
+}
+
+func TestSanitizeLabelValueExpression(t *testing.T) {
+	expected := `substr(replace(lower(var.owner), "/[^a-z0-9_-]/", "_"), 0, 63)`
+
+	if expression := SanitizeLabelValueExpression("var.owner"); expression != expected {
+		t.Errorf("SanitizeLabelValueExpression() = %s, expected %s", expression, expected)
+	}
+}
+
+func TestLabelValueSanitizer(t *testing.T) {
+	sanitizer := NewLabelValueSanitizer()
+
+	if label, collision := sanitizer.SanitizeLabelValue("Owner", "Platform Team"); label != "platform_team" || collision != "" {
+		t.Errorf("SanitizeLabelValue() = %q, %q", label, collision)
+	}
+
+	if _, collision := sanitizer.SanitizeLabelValue("Owner", "Platform Team"); collision != "" {
+		t.Errorf("same value reported as a collision with %q", collision)
+	}
+
+	if _, collision := sanitizer.SanitizeLabelValue("Team", "platform_team"); collision != "" {
+		t.Errorf("value of another key reported as a collision with %q", collision)
+	}
+
+	if _, collision := sanitizer.SanitizeLabelValue("Owner", "platform_team"); collision != "Platform Team" {
+		t.Errorf("collision = %q, expected Platform Team", collision)
+	}
+
+	if _, collision := sanitizer.SanitizeLabelValue("Owner", "platform_team"); collision != "" {
+		t.Errorf("collision reported twice: %q", collision)
+	}
+}
+
+func TestUsesLabels(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		fallback     string
+		expected     bool
+	}{
+		{resourceType: "google_storage_bucket", expected: true},
+		{resourceType: "google_storage_bucket", fallback: "aws", expected: true},
+		{resourceType: "aws_s3_bucket", fallback: "gcp", expected: false},
+		{resourceType: "custom_resource", fallback: "gcp", expected: true},
+		{resourceType: "custom_resource", expected: false},
+	}
+
+	for _, tt := range tests {
+		if uses := UsesLabels(tt.resourceType, tt.fallback); uses != tt.expected {
+			t.Errorf("UsesLabels(%s, %s) = %v, expected %v", tt.resourceType, tt.fallback, uses, tt.expected)
+		}
+	}
+}
@@ -160,3 +160,14 @@ func isSupportedProvider(provider Provider) bool {
 }
 
 type Provider string
+
+// ResourceProvider returns the cloud provider of a resource type by its prefix, empty if the prefix is unknown
+func ResourceProvider(resourceType string) string {
+	return string(getProviderByResource(resourceType))
+}
+
+// ProviderBlockProvider returns the cloud provider of a Terraform provider block by its name (e.g. google-beta), empty
+// if the provider is unknown
+func ProviderBlockProvider(name string) string {
+	return ResourceProvider(strings.TrimSuffix(name, "-beta") + "_")
+}
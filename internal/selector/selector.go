@@ -0,0 +1,218 @@
+package selector
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+)
+
+// Kinds of selectors, a selector without a kind prefix matches the resource type
+const (
+	KindType    = "type"    // Regex matched against the resource type, e.g. type:aws_iam_.*
+	KindName    = "name"    // Regex matched against the resource name, e.g. name:^legacy_
+	KindAddress = "address" // Regex matched against the resource address, e.g. address:^module\.network\.
+	KindModule  = "module"  // Module address, matches the resources of the module and its child modules, e.g. module:module.network
+	KindFile    = "file"    // Glob matched against the path of the file relative to the tagged directory, e.g. file:generated/**
+)
+
+// subjects describe what a selector kind is matched against in the reasons of excluded resources
+var subjects = map[string]string{
+	KindType:    "resource type",
+	KindName:    "resource name",
+	KindAddress: "resource address",
+	KindModule:  "module",
+	KindFile:    "file",
+}
+
+// Resource is a resource block as selectors see it
+type Resource struct {
+	Type    string
+	Name    string
+	Modules []string // Addresses of the modules instantiating the resource, e.g. module.network, "" for the root module
+	File    string   // Slash separated path of the file relative to the tagged directory, empty if unknown
+}
+
+// Addresses returns the addresses of the resource in every module instantiating it, e.g. module.network.aws_subnet.private
+func (r Resource) Addresses() []string {
+	modules := r.Modules
+	if len(modules) == 0 {
+		modules = []string{""}
+	}
+
+	addresses := make([]string, len(modules))
+	for i, module := range modules {
+		addresses[i] = r.Type + "." + r.Name
+		if module != "" {
+			addresses[i] = module + "." + addresses[i]
+		}
+	}
+
+	return addresses
+}
+
+// FromAddress returns the resource of a resource instance address, e.g. module.network["a"].aws_subnet.private[0]
+func FromAddress(address string) Resource {
+	parts := strings.Split(withoutInstanceKeys(address), ".")
+	if len(parts) < 2 {
+		return Resource{Name: address}
+	}
+
+	return Resource{
+		Type:    parts[len(parts)-2],
+		Name:    parts[len(parts)-1],
+		Modules: []string{strings.Join(parts[:len(parts)-2], ".")},
+	}
+}
+
+// withoutInstanceKeys removes the count and for_each keys of an address, e.g. [0] or ["a.b"]
+func withoutInstanceKeys(address string) string {
+	var builder strings.Builder
+
+	depth := 0
+	quoted := false
+
+	for i := 0; i < len(address); i++ {
+		c := address[i]
+
+		switch {
+		case quoted && c == '\\':
+			i++
+		case c == '"' && depth > 0:
+			quoted = !quoted
+		case quoted:
+		case c == '[':
+			depth++
+		case c == ']' && depth > 0:
+			depth--
+		case depth == 0:
+			builder.WriteByte(c)
+		}
+	}
+
+	return builder.String()
+}
+
+// selector matches resources by one of their properties
+type selector struct {
+	text    string // Selector as written
+	kind    string
+	pattern string
+	regexp  *regexp.Regexp // Compiled pattern of the regex kinds
+}
+
+// Selectors are the parsed -filter and -skip selectors
+type Selectors struct {
+	filters []selector
+	skips   []selector
+}
+
+// Parse parses the whitespace separated selectors of -filter and -skip
+func Parse(filter string, skip string) (*Selectors, error) {
+	filters, err := parseSelectors(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -filter: %w", err)
+	}
+
+	skips, err := parseSelectors(skip)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -skip: %w", err)
+	}
+
+	return &Selectors{filters: filters, skips: skips}, nil
+}
+
+func parseSelectors(text string) ([]selector, error) {
+	var selectors []selector
+
+	for _, field := range strings.Fields(text) {
+		s := selector{text: field, kind: KindType, pattern: field}
+
+		if kind, pattern, ok := strings.Cut(field, ":"); ok && subjects[kind] != "" {
+			s.kind, s.pattern = kind, pattern
+		}
+
+		if s.pattern == "" {
+			return nil, fmt.Errorf("selector %q has no pattern", field)
+		}
+
+		switch s.kind {
+		case KindModule:
+			if !strings.HasPrefix(s.pattern, "module.") {
+				return nil, fmt.Errorf("selector %q must be a module address, e.g. module:module.network", field)
+			}
+		case KindFile:
+			if _, err := doublestar.Match(s.pattern, s.pattern); err != nil {
+				return nil, fmt.Errorf("selector %q is not a valid glob: %w", field, err)
+			}
+		default:
+			compiled, err := regexp.Compile(s.pattern)
+			if err != nil {
+				return nil, fmt.Errorf("selector %q is not a valid regex: %w", field, err)
+			}
+
+			s.regexp = compiled
+		}
+
+		selectors = append(selectors, s)
+	}
+
+	return selectors, nil
+}
+
+// Select returns true if a resource matches every -filter selector and no -skip selector, or why it doesn't.
+// File selectors are ignored for resources whose file is unknown.
+func (s *Selectors) Select(resource Resource) (bool, string) {
+	for _, filter := range s.filters {
+		if matched, known := filter.matches(resource); known && !matched {
+			return false, fmt.Sprintf("%s doesn't match -filter %s", subjects[filter.kind], filter.text)
+		}
+	}
+
+	for _, skip := range s.skips {
+		if matched, _ := skip.matches(resource); matched {
+			return false, fmt.Sprintf("%s matches -skip %s", subjects[skip.kind], skip.text)
+		}
+	}
+
+	return true, ""
+}
+
+// matches returns true if any instance of the resource matches the selector, and false if the property the
+// selector matches is unknown
+func (s selector) matches(resource Resource) (matched bool, known bool) {
+	switch s.kind {
+	case KindType:
+		return s.regexp.MatchString(resource.Type), true
+	case KindName:
+		return s.regexp.MatchString(resource.Name), true
+	case KindAddress:
+		for _, address := range resource.Addresses() {
+			if s.regexp.MatchString(address) {
+				return true, true
+			}
+		}
+
+		return false, true
+	case KindModule:
+		for _, module := range resource.Modules {
+			if module == s.pattern || strings.HasPrefix(module, s.pattern+".") {
+				return true, true
+			}
+		}
+
+		return false, true
+	case KindFile:
+		if resource.File == "" {
+			return false, false
+		}
+
+		matched, _ := doublestar.Match(s.pattern, path.Clean(resource.File))
+
+		return matched, true
+	}
+
+	return false, false
+}
@@ -0,0 +1,94 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect(t *testing.T) {
+	subnet := Resource{Type: "aws_subnet", Name: "private", Modules: []string{"module.network"}, File: "modules/network/main.tf"}
+	nested := Resource{Type: "aws_subnet", Name: "public", Modules: []string{"module.network.module.subnets"}, File: "modules/subnets/main.tf"}
+	bucket := Resource{Type: "aws_s3_bucket", Name: "legacy_logs", File: "generated/buckets.tf"}
+	planned := Resource{Type: "aws_iam_role", Name: "app", Modules: []string{"module.app"}}
+
+	tests := []struct {
+		name     string
+		filter   string
+		skip     string
+		selected []Resource
+		reason   string // Reason the first excluded resource is excluded for
+	}{
+		{
+			name:     "resource type regex",
+			filter:   ".*",
+			skip:     "aws_iam_.*",
+			selected: []Resource{subnet, nested, bucket},
+			reason:   "resource type matches -skip aws_iam_.*",
+		},
+		{
+			name:     "module and its child modules",
+			skip:     "module:module.network",
+			selected: []Resource{bucket, planned},
+			reason:   "module matches -skip module:module.network",
+		},
+		{
+			name:     "file glob, unknown files are not skipped",
+			skip:     "file:generated/**",
+			selected: []Resource{subnet, nested, planned},
+			reason:   "file matches -skip file:generated/**",
+		},
+		{
+			name:     "every filter must match",
+			filter:   "aws_subnet address:^module\\.network\\.aws_",
+			selected: []Resource{subnet},
+			reason:   "resource address doesn't match -filter address:^module\\.network\\.aws_",
+		},
+		{
+			name:     "any skip matches",
+			skip:     "name:^legacy_ type:aws_iam_role",
+			selected: []Resource{subnet, nested},
+			reason:   "resource name matches -skip name:^legacy_",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selectors, err := Parse(tt.filter, tt.skip)
+			require.NoError(t, err)
+
+			var selected []Resource
+			var reasons []string
+
+			for _, resource := range []Resource{subnet, nested, bucket, planned} {
+				ok, reason := selectors.Select(resource)
+				if ok {
+					selected = append(selected, resource)
+				} else {
+					reasons = append(reasons, reason)
+				}
+			}
+
+			assert.Equal(t, tt.selected, selected)
+			require.NotEmpty(t, reasons)
+			assert.Equal(t, tt.reason, reasons[0])
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, filter := range []string{"aws_(", "name:", "module:network", "file:[", "address:+"} {
+		_, err := Parse(filter, "")
+		assert.Error(t, err, filter)
+	}
+}
+
+func TestFromAddress(t *testing.T) {
+	assert.Equal(t, Resource{Type: "aws_instance", Name: "web", Modules: []string{""}}, FromAddress("aws_instance.web[0]"))
+	assert.Equal(t,
+		Resource{Type: "aws_subnet", Name: "private", Modules: []string{"module.network.module.subnets"}},
+		FromAddress(`module.network["eu.west"].module.subnets[1].aws_subnet.private["a]b"]`))
+
+	assert.Equal(t, []string{"aws_instance.web"}, Resource{Type: "aws_instance", Name: "web"}.Addresses())
+}
@@ -434,7 +434,7 @@ func (s *OperationsService) executeValidation(ctx context.Context, operationID i
 	log.Printf("Filter pattern: %s", args.Filter)
 	log.Printf("Skip pattern: %s", args.Skip)
 	
-	err := validation.ValidateStandards(args)
+	err := validation.ValidateStandards(ctx, args)
 	if err != nil {
 		log.Printf("Terratag validation failed: %v", err)
 		logger.WithError(err).Error("Core validation engine failed")
@@ -578,7 +578,7 @@ func (s *OperationsService) executeTagging(ctx context.Context, operationID int6
 	log.Printf("Filter pattern: %s", args.Filter)
 	log.Printf("Skip pattern: %s", args.Skip)
 	
-	err := terratag.Terratag(args)
+	report, err := terratag.Terratag(ctx, args)
 	if err != nil {
 		log.Printf("Terratag tagging failed: %v", err)
 		logger.WithError(err).Error("Core tagging engine failed")
@@ -590,14 +590,10 @@ func (s *OperationsService) executeTagging(ctx context.Context, operationID int6
 	logger.Info("Core tagging engine completed successfully")
 	s.logOperation(ctx, operationID, "info", "Core tagging engine completed successfully", nil)
 
-	// Parse and store tagging results
-	logger.Info("Parsing and storing tagging results")
-	s.logOperation(ctx, operationID, "info", "Parsing and storing tagging results", nil)
-	if err := s.parseAndStoreTaggingResults(ctx, operationID, args); err != nil {
-		logger.WithError(err).Warn("Failed to parse tagging results")
-		s.logOperation(ctx, operationID, "warning", "Failed to parse tagging results", map[string]interface{}{"error": err.Error()})
-		// Don't fail the operation if result parsing fails
-	}
+	// Store the per-resource tagging results reported by the engine
+	logger.Info("Storing tagging results")
+	s.logOperation(ctx, operationID, "info", "Storing tagging results", nil)
+	s.storeTaggingReport(ctx, operationID, args, report)
 	
 	logger.Info("Tagging completed successfully")
 	s.logOperation(ctx, operationID, "info", "Tagging completed successfully", nil)
@@ -933,7 +929,7 @@ func (s *OperationsService) parseAndStoreValidationResults(ctx context.Context,
 		extractArgs.IsSkipTerratagFiles = true // Skip .terratag.tf files
 		
 		// Import the validation package to use its enhanced resource extraction
-		allResources, err := s.collectResourcesWithEnhancedInfo(files, extractArgs, tagStandard.CloudProvider)
+		allResources, err := s.collectResourcesWithEnhancedInfo(files, extractArgs, tagStandard)
 		if err != nil {
 			logger.WithError(err).Error("Failed to collect resources with enhanced info")
 			return fmt.Errorf("failed to collect resources: %w", err)
@@ -1025,61 +1021,49 @@ func (s *OperationsService) parseAndStoreValidationResults(ctx context.Context,
 	return nil
 }
 
-// Parse and store tagging results in database
-func (s *OperationsService) parseAndStoreTaggingResults(ctx context.Context, operationID int64, args cli.Args) error {
-	// Look for .terratag.tf files generated by terratag
-	terratagFiles, err := filepath.Glob(filepath.Join(args.Dir, "**/*.terratag.tf"))
-	if err != nil {
-		return fmt.Errorf("failed to find terratag files: %w", err)
-	}
-	
-	// Also look for backup files to see what was modified
-	backupFiles, err := filepath.Glob(filepath.Join(args.Dir, "**/*.tf.bak"))
-	if err != nil {
-		return fmt.Errorf("failed to find backup files: %w", err)
+// Store the tagging report of the core engine in database, one result per file and per processed block
+func (s *OperationsService) storeTaggingReport(ctx context.Context, operationID int64, args cli.Args, report *terratag.TaggingReport) {
+	if report == nil {
+		return
 	}
-	
-	// Process terratag files
-	for _, file := range terratagFiles {
-		relativePath := strings.TrimPrefix(file, args.Dir+"/")
-		originalFile := strings.TrimSuffix(file, ".terratag.tf") + ".tf"
-		
-		// Count resources in the file (simplified)
-		resources, err := s.extractResourcesFromFile(file)
-		if err != nil {
-			s.storeOperationResult(ctx, operationID, relativePath, "", "error", err.Error(), nil, 0, "")
-			continue
+
+	for _, fileResult := range report.Files {
+		relativePath := fileResult.Path
+		if rel, err := filepath.Rel(args.Dir, fileResult.Path); err == nil {
+			relativePath = rel
 		}
-		
-		for _, resource := range resources {
+
+		// Tagged and unchanged files are represented by the results of their blocks
+		if fileResult.Status == terratag.FileError || fileResult.Status == terratag.FileSkipped {
 			details := map[string]interface{}{
-				"original_file": filepath.Base(originalFile),
-				"resource_type": resource.Type,
+				"file_status": string(fileResult.Status),
+				"dry_run":     report.DryRun,
 			}
-			
-			s.storeOperationResult(ctx, operationID, relativePath, resource.Type+"."+resource.Name, "tagged", "Resource successfully tagged", details, resource.LineNumber, resource.Snippet)
+
+			s.storeOperationResult(ctx, operationID, relativePath, "", string(fileResult.Status), fileResult.Error, details, 0, "")
 		}
-	}
-	
-	// Process backup files to see what was modified
-	for _, backupFile := range backupFiles {
-		relativePath := strings.TrimPrefix(backupFile, args.Dir+"/")
-		originalFile := strings.TrimSuffix(backupFile, ".bak")
-		
-		details := map[string]interface{}{
-			"original_file": filepath.Base(originalFile),
-			"backup_created": true,
+
+		for _, resource := range fileResult.Resources {
+			details := map[string]interface{}{
+				"block":         resource.Block,
+				"resource_type": resource.Type,
+				"tag_attribute": resource.TagAttribute,
+				"dry_run":       report.DryRun,
+			}
+
+			resourceName := resource.Type
+			if resource.Name != "" {
+				resourceName += "." + resource.Name
+			}
+
+			s.storeOperationResult(ctx, operationID, relativePath, resourceName, string(resource.Status), resource.Reason, details, resource.Line, "")
 		}
-		
-		s.storeOperationResult(ctx, operationID, relativePath, "", "backed_up", "Original file backed up", details, 0, "")
 	}
-	
-	return nil
 }
 
 // Helper to get terraform files matching patterns
 // collectResourcesWithEnhancedInfo uses the validation system to extract resources with line numbers and snippets
-func (s *OperationsService) collectResourcesWithEnhancedInfo(filePaths []string, args cli.Args, cloudProvider string) ([]standards.ResourceInfo, error) {
+func (s *OperationsService) collectResourcesWithEnhancedInfo(filePaths []string, args cli.Args, tagStandard *standards.TagStandard) ([]standards.ResourceInfo, error) {
 	var allResources []standards.ResourceInfo
 	
 	for _, filePath := range filePaths {
@@ -1170,8 +1154,8 @@ func (s *OperationsService) collectResourcesWithEnhancedInfo(filePaths []string,
 				}
 			}
 
-			// Check if resource supports tagging
-			if !standards.IsTaggableResource(resourceType, cloudProvider) {
+			// Check if resource supports tagging, for the providers of the standard
+			if !tagStandard.IsTaggableResource(resourceType) {
 				continue
 			}
 
@@ -1365,46 +1349,6 @@ func (s *OperationsService) getTerraformFiles(dir, filter, skip string) ([]strin
 	return files, err
 }
 
-// Simplified resource extraction (in production would use proper HCL parsing)
-type TerraformResource struct {
-	Type       string
-	Name       string
-	Tags       map[string]interface{}
-	LineNumber int
-	Snippet    string
-}
-
-func (s *OperationsService) extractResourcesFromFile(filePath string) ([]TerraformResource, error) {
-	content, err := ioutil.ReadFile(filePath)
-	if err != nil {
-		return nil, err
-	}
-	
-	// Simple regex-based extraction (in production would use HCL parser)
-	var resources []TerraformResource
-	lines := strings.Split(string(content), "\n")
-	
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "resource \"") {
-			// Extract resource type and name from: resource "aws_instance" "example" {
-			parts := strings.Fields(line)
-			if len(parts) >= 3 {
-				resourceType := strings.Trim(parts[1], "\"")
-				resourceName := strings.Trim(parts[2], "\"")
-				
-				resources = append(resources, TerraformResource{
-					Type: resourceType,
-					Name: resourceName,
-					Tags: make(map[string]interface{}),
-				})
-			}
-		}
-	}
-	
-	return resources, nil
-}
-
 // Extract variable resolution information from resource tags
 func (s *OperationsService) extractVariableResolutionInfo(tags map[string]interface{}) map[string]interface{} {
 	variableInfo := map[string]interface{}{
@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/cloudyali/terratag/internal/db"
@@ -431,10 +432,10 @@ func (s *TagStandardsService) validateYamlContent(content string, cloudProvider
 		return fmt.Errorf("invalid YAML syntax: %w", err)
 	}
 
-	// Validate cloud provider matches
-	if standard.CloudProvider != cloudProvider {
+	// Validate cloud provider matches, multi-cloud standards match any of their providers
+	if !slices.Contains(standard.Providers(), cloudProvider) {
 		return fmt.Errorf("cloud provider in content (%s) does not match specified provider (%s)", 
-			standard.CloudProvider, cloudProvider)
+			strings.Join(standard.Providers(), ", "), cloudProvider)
 	}
 
 	// Use the existing validation logic from standards package
@@ -0,0 +1,25 @@
+package standards
+
+// MergeEffectiveTags computes the tags a resource ends up with once provider default tags are applied.
+// Following AWS default_tags precedence, resource level tags override provider defaults with the same key.
+// The returned sources map records the layer each effective tag came from.
+func MergeEffectiveTags(providerTags, resourceTags map[string]string) (map[string]string, map[string]TagSource) {
+	tags := make(map[string]string, len(providerTags)+len(resourceTags))
+	sources := make(map[string]TagSource, len(providerTags)+len(resourceTags))
+
+	for key, value := range providerTags {
+		tags[key] = value
+		sources[key] = TagSourceProviderDefault
+	}
+
+	for key, value := range resourceTags {
+		if _, inherited := providerTags[key]; inherited {
+			sources[key] = TagSourceResourceOverride
+		} else {
+			sources[key] = TagSourceResource
+		}
+		tags[key] = value
+	}
+
+	return tags, sources
+}
@@ -0,0 +1,127 @@
+package standards
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"os"
+	"sort"
+	"strings"
+)
+
+//go:embed report.html.tmpl
+var htmlReportTemplate string
+
+var htmlReportFuncs = template.FuncMap{
+	"percent": func(rate float64) string {
+		return fmt.Sprintf("%.1f%%", rate*100)
+	},
+	"describeFix": describeFix,
+	"humanize": func(value string) string {
+		return strings.ReplaceAll(strings.ReplaceAll(value, "_", " "), "-", " ")
+	},
+	"join": strings.Join,
+}
+
+// htmlReport is the view of a validation report rendered by the HTML template
+type htmlReport struct {
+	ValidationReport
+	Generated  string
+	Services   []htmlService
+	Categories []htmlCategory
+	Violations []ViolationSummary
+	Resources  []htmlResource
+}
+
+type htmlService struct {
+	Name string
+	ServiceTaggingInfo
+}
+
+type htmlCategory struct {
+	Name  string
+	Count int
+}
+
+type htmlResource struct {
+	ValidationResult
+	Status string // compliant or non-compliant, used by the client-side filters
+	Search string // Lower-cased text the client-side search matches
+}
+
+// generateHTMLReport creates a self-contained HTML report, with embedded CSS and client-side filtering, that can be
+// opened from CI artifacts without the API server
+func (r *ReportGenerator) generateHTMLReport(report ValidationReport, outputPath string) error {
+	tmpl, err := template.New("report").Funcs(htmlReportFuncs).Parse(htmlReportTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse HTML report template: %w", err)
+	}
+
+	var output strings.Builder
+	if err := tmpl.Execute(&output, newHTMLReport(report)); err != nil {
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+
+	if outputPath == "" || outputPath == "-" {
+		fmt.Print(output.String())
+		return nil
+	}
+
+	return os.WriteFile(outputPath, []byte(output.String()), 0644)
+}
+
+// newHTMLReport prepares the view of a validation report: services sorted by tagging rate, categories and
+// violations by count, non-compliant resources first
+func newHTMLReport(report ValidationReport) htmlReport {
+	view := htmlReport{
+		ValidationReport: report,
+		Generated:        report.Timestamp.Format("2006-01-02 15:04:05"),
+		Violations:       append([]ViolationSummary(nil), report.Summary.MostCommonViolations...),
+	}
+
+	for name, info := range report.TaggingSupport.ServiceBreakdown {
+		view.Services = append(view.Services, htmlService{Name: name, ServiceTaggingInfo: info})
+	}
+	sort.Slice(view.Services, func(i, j int) bool {
+		if view.Services[i].TaggingRate != view.Services[j].TaggingRate {
+			return view.Services[i].TaggingRate > view.Services[j].TaggingRate
+		}
+		return view.Services[i].Name < view.Services[j].Name
+	})
+
+	for name, count := range report.TaggingSupport.CategoryBreakdown {
+		view.Categories = append(view.Categories, htmlCategory{Name: name, Count: count})
+	}
+	sort.Slice(view.Categories, func(i, j int) bool {
+		if view.Categories[i].Count != view.Categories[j].Count {
+			return view.Categories[i].Count > view.Categories[j].Count
+		}
+		return view.Categories[i].Name < view.Categories[j].Name
+	})
+
+	sort.SliceStable(view.Violations, func(i, j int) bool {
+		return view.Violations[i].Count > view.Violations[j].Count
+	})
+
+	for _, result := range report.Results {
+		resource := htmlResource{ValidationResult: result, Status: "compliant"}
+		if !result.IsCompliant {
+			resource.Status = "non-compliant"
+		}
+
+		search := []string{result.ResourceType + "." + result.ResourceName, result.FilePath}
+		search = append(search, result.MissingTags...)
+		search = append(search, result.ExtraTags...)
+		for _, violation := range result.Violations {
+			search = append(search, violation.TagKey, string(violation.ViolationType))
+		}
+		resource.Search = strings.ToLower(strings.Join(search, " "))
+
+		view.Resources = append(view.Resources, resource)
+	}
+	sort.SliceStable(view.Resources, func(i, j int) bool {
+		return !view.Resources[i].IsCompliant && view.Resources[j].IsCompliant
+	})
+
+	return view
+}
@@ -0,0 +1,71 @@
+package standards
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateHTMLReport(t *testing.T) {
+	report := ValidationReport{
+		Timestamp:             time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		StandardFile:          "tag-standard.yaml",
+		TotalResources:        2,
+		CompliantResources:    1,
+		NonCompliantResources: 1,
+		TaggingSupport: TaggingSupportSummary{
+			TotalResourcesAnalyzed:  2,
+			ResourcesSupportingTags: 2,
+			TaggingSupportRate:      1,
+			ServiceBreakdown: map[string]ServiceTaggingInfo{
+				"s3":  {TotalResources: 1, TaggableResources: 1, TaggingRate: 1},
+				"ec2": {TotalResources: 1, TaggableResources: 1, TaggingRate: 1},
+			},
+		},
+		Results: []ValidationResult{
+			{ResourceType: "aws_s3_bucket", ResourceName: "logs", FilePath: "storage.tf", IsCompliant: true},
+			{
+				ResourceType: "aws_instance",
+				ResourceName: "web",
+				FilePath:     "main.tf",
+				LineNumber:   3,
+				Snippet:      `resource "aws_instance" "web" { tags = { Name = "<web>" } }`,
+				MissingTags:  []string{"Owner"},
+				SuggestedFixes: []SuggestedFix{
+					{TagKey: "Owner", SuggestedValue: "platform", Action: ActionAdd},
+				},
+			},
+		},
+		Summary: ValidationSummary{ComplianceRate: 0.5},
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	generator := NewReportGenerator(ValidationOptions{ReportFormat: ReportFormatHTML})
+	require.NoError(t, generator.GenerateReport(report, outputPath))
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	html := string(data)
+
+	assert.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+	assert.Contains(t, html, "<style>", "CSS is embedded")
+	assert.Contains(t, html, `<div class="value">50.0%</div>`)
+	assert.Contains(t, html, `<span style="width: 50.0%">`)
+	assert.Contains(t, html, "<td>ec2</td>")
+	assert.Contains(t, html, "add tag &#39;Owner&#39; with value &#39;platform&#39;")
+	assert.Contains(t, html, "&lt;web&gt;", "snippets are escaped")
+	assert.NotContains(t, html, "ZgotmplZ")
+
+	// Non-compliant resources are listed first and open, with the text the client-side search matches
+	web := strings.Index(html, `data-search="aws_instance.web main.tf owner"`)
+	logs := strings.Index(html, `data-search="aws_s3_bucket.logs storage.tf"`)
+	require.NotEqual(t, -1, web)
+	require.NotEqual(t, -1, logs)
+	assert.Less(t, web, logs)
+	assert.Contains(t, html, `data-search="aws_instance.web main.tf owner" open>`)
+}
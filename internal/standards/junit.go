@@ -0,0 +1,177 @@
+package standards
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// generateJUnitReport creates a JUnit XML report with a test suite per file and a test case per resource.
+// Non-compliant resources are failures, resources exempted by an annotation are skipped. Like the other
+// formats, the report doesn't fail the run, -strict-mode does.
+func (r *ReportGenerator) generateJUnitReport(report ValidationReport, outputPath string) error {
+	data, err := xml.MarshalIndent(newJUnitTestSuites(report), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report to JUnit XML: %w", err)
+	}
+
+	data = append([]byte(xml.Header), data...)
+
+	if outputPath == "" || outputPath == "-" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	return os.WriteFile(outputPath, data, 0644)
+}
+
+// newJUnitTestSuites converts a validation report into JUnit test suites, sorted by file
+func newJUnitTestSuites(report ValidationReport) junitTestSuites {
+	suites := map[string]*junitTestSuite{}
+
+	suite := func(filePath string) *junitTestSuite {
+		if suites[filePath] == nil {
+			suites[filePath] = &junitTestSuite{Name: filePath}
+			if !report.Timestamp.IsZero() {
+				suites[filePath].Timestamp = report.Timestamp.Format(time.RFC3339)
+			}
+		}
+
+		return suites[filePath]
+	}
+
+	for _, result := range report.Results {
+		testCase := junitTestCase{
+			Name:      result.ResourceType + "." + result.ResourceName,
+			ClassName: result.ResourceType,
+			File:      result.FilePath,
+			Line:      result.LineNumber,
+		}
+
+		s := suite(result.FilePath)
+		if !result.IsCompliant {
+			testCase.Failure = newJUnitFailure(result)
+			s.Failures++
+		}
+
+		s.Cases = append(s.Cases, testCase)
+	}
+
+	// Resources exempted in full by an annotation aren't validated
+	for _, suppressed := range report.SuppressedResources {
+		if len(suppressed.IgnoredTags) > 0 {
+			continue
+		}
+
+		s := suite(suppressed.FilePath)
+		s.Cases = append(s.Cases, junitTestCase{
+			Name:      suppressed.ResourceType + "." + suppressed.ResourceName,
+			ClassName: suppressed.ResourceType,
+			File:      suppressed.FilePath,
+			Line:      suppressed.LineNumber,
+			Skipped:   &junitSkipped{Message: suppressedReason(suppressed)},
+		})
+		s.Skipped++
+	}
+
+	filePaths := make([]string, 0, len(suites))
+	for filePath := range suites {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	testSuites := junitTestSuites{Name: "terratag"}
+	for _, filePath := range filePaths {
+		s := suites[filePath]
+		s.Tests = len(s.Cases)
+
+		testSuites.Tests += s.Tests
+		testSuites.Failures += s.Failures
+		testSuites.Skipped += s.Skipped
+		testSuites.Suites = append(testSuites.Suites, *s)
+	}
+
+	return testSuites
+}
+
+// newJUnitFailure returns the failure of a non-compliant resource: the message summarizes the issues, the text
+// lists the violation messages and the suggested fixes
+func newJUnitFailure(result ValidationResult) *junitFailure {
+	var issues, lines []string
+
+	if len(result.MissingTags) > 0 {
+		issues = append(issues, fmt.Sprintf("Missing: %s", strings.Join(result.MissingTags, ", ")))
+		for _, key := range result.MissingTags {
+			lines = append(lines, fmt.Sprintf("%s: Required tag '%s' is missing", ViolationMissingRequired, key))
+		}
+	}
+
+	if len(result.Violations) > 0 {
+		keys := make([]string, len(result.Violations))
+		for i, violation := range result.Violations {
+			keys[i] = violation.TagKey
+			lines = append(lines, fmt.Sprintf("%s: %s", violation.ViolationType, violation.Message))
+		}
+		issues = append(issues, fmt.Sprintf("Invalid: %s", strings.Join(keys, ", ")))
+	}
+
+	if len(result.ExtraTags) > 0 {
+		issues = append(issues, fmt.Sprintf("Extra: %s", strings.Join(result.ExtraTags, ", ")))
+		for _, key := range result.ExtraTags {
+			lines = append(lines, fmt.Sprintf("%s: Tag '%s' is not allowed on resource type '%s'", ViolationNotAllowed, key, result.ResourceType))
+		}
+	}
+
+	if len(result.SuggestedFixes) > 0 {
+		lines = append(lines, "", "Suggested fixes:")
+		for _, fix := range result.SuggestedFixes {
+			lines = append(lines, "- "+describeFix(fix))
+		}
+	}
+
+	return &junitFailure{
+		Message: strings.Join(issues, "; "),
+		Type:    "tag_compliance",
+		Text:    strings.Join(lines, "\n"),
+	}
+}
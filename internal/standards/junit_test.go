@@ -0,0 +1,86 @@
+package standards
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateJUnitReport(t *testing.T) {
+	report := ValidationReport{
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Results: []ValidationResult{
+			{
+				ResourceType: "aws_instance",
+				ResourceName: "web",
+				FilePath:     "main.tf",
+				LineNumber:   3,
+				MissingTags:  []string{"Owner"},
+				Violations: []TagViolation{{
+					TagKey:        "Environment",
+					TagValue:      "qa",
+					ViolationType: ViolationInvalidValue,
+					Message:       "Value 'qa' is not in allowed values: [prod dev]",
+				}},
+				SuggestedFixes: []SuggestedFix{
+					{TagKey: "Owner", SuggestedValue: "platform", Action: ActionAdd},
+					{TagKey: "Environment", CurrentValue: "qa", SuggestedValue: "prod", Action: ActionUpdate},
+				},
+			},
+			{ResourceType: "aws_s3_bucket", ResourceName: "logs", FilePath: "storage.tf", LineNumber: 1, IsCompliant: true},
+			{ResourceType: "aws_vpc", ResourceName: "main", FilePath: "main.tf", LineNumber: 20, IsCompliant: true},
+		},
+		SuppressedResources: []SuppressedResource{
+			{ResourceType: "aws_instance", ResourceName: "legacy", FilePath: "storage.tf", Annotation: "terratag:ignore", Reason: "vendor managed"},
+			{ResourceType: "aws_instance", ResourceName: "shared", FilePath: "main.tf", Annotation: "terratag:ignore=Owner", IgnoredTags: []string{"Owner"}},
+		},
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.xml")
+	generator := NewReportGenerator(ValidationOptions{ReportFormat: ReportFormatJUnit})
+	require.NoError(t, generator.GenerateReport(report, outputPath))
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(data, &suites))
+
+	assert.Equal(t, 4, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, 1, suites.Skipped)
+	require.Len(t, suites.Suites, 2)
+
+	main := suites.Suites[0]
+	assert.Equal(t, "main.tf", main.Name)
+	assert.Equal(t, "2024-05-01T12:00:00Z", main.Timestamp)
+	assert.Equal(t, 2, main.Tests)
+	assert.Equal(t, 1, main.Failures)
+	require.Len(t, main.Cases, 2)
+
+	web := main.Cases[0]
+	assert.Equal(t, "aws_instance.web", web.Name)
+	assert.Equal(t, "aws_instance", web.ClassName)
+	assert.Equal(t, 3, web.Line)
+	require.NotNil(t, web.Failure)
+	assert.Equal(t, "Missing: Owner; Invalid: Environment", web.Failure.Message)
+	assert.Equal(t, `missing_required: Required tag 'Owner' is missing
+invalid_value: Value 'qa' is not in allowed values: [prod dev]
+
+Suggested fixes:
+- add tag 'Owner' with value 'platform'
+- update tag 'Environment' from 'qa' to 'prod'`, web.Failure.Text)
+	assert.Nil(t, main.Cases[1].Failure)
+
+	storage := suites.Suites[1]
+	assert.Equal(t, "storage.tf", storage.Name)
+	assert.Equal(t, 1, storage.Skipped)
+	require.Len(t, storage.Cases, 2)
+	require.NotNil(t, storage.Cases[1].Skipped)
+	assert.Equal(t, "vendor managed", storage.Cases[1].Skipped.Message)
+}
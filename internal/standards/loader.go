@@ -7,6 +7,9 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/bmatcuk/doublestar"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"gopkg.in/yaml.v3"
 )
 
@@ -84,20 +87,6 @@ func validateStandard(standard *TagStandard) error {
 		return fmt.Errorf("unsupported schema version %d, expected %d", standard.Version, SupportedSchemaVersion)
 	}
 
-	// Check cloud provider
-	if standard.CloudProvider == "" {
-		return fmt.Errorf("cloud_provider is required")
-	}
-
-	validProviders := map[string]bool{
-		"aws":   true,
-		"gcp":   true,
-		"azure": true,
-	}
-	if !validProviders[standard.CloudProvider] {
-		return fmt.Errorf("unsupported cloud provider: %s", standard.CloudProvider)
-	}
-
 	// Validate required tags
 	if err := validateTagSpecs(standard.RequiredTags, "required_tags"); err != nil {
 		return err
@@ -128,6 +117,16 @@ func validateStandard(standard *TagStandard) error {
 		return err
 	}
 
+	// Validate the cloud providers and their overrides
+	if err := validateProviders(standard, tagKeys); err != nil {
+		return err
+	}
+
+	// Validate directory overlays
+	if err := validateDirectoryOverlays(standard); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -177,6 +176,16 @@ func validateTagSpec(tag TagSpec) error {
 		}
 	}
 
+	// Expression default values are written unquoted into the tagged files, make sure they parse
+	if tag.DefaultIsExpression {
+		if tag.DefaultValue == "" {
+			return fmt.Errorf("default_is_expression requires a default_value for tag '%s'", tag.Key)
+		}
+		if err := ValidateTagExpression(tag.DefaultValue); err != nil {
+			return fmt.Errorf("invalid default_value expression for tag '%s': %w", tag.Key, err)
+		}
+	}
+
 	// Validate examples against the tag spec itself
 	if len(tag.Examples) > 0 {
 		for _, example := range tag.Examples {
@@ -189,6 +198,15 @@ func validateTagSpec(tag TagSpec) error {
 	return nil
 }
 
+// ValidateTagExpression checks that a tag value is a single, parseable HCL expression
+func ValidateTagExpression(expression string) error {
+	if _, diags := hclsyntax.ParseExpression([]byte(expression), "", hcl.InitialPos); diags.HasErrors() {
+		return diags
+	}
+
+	return nil
+}
+
 // validateResourceRules validates resource-specific rules
 func validateResourceRules(rules []ResourceRule, globalTagKeys map[string]bool) error {
 	for i, rule := range rules {
@@ -222,6 +240,40 @@ func validateResourceRules(rules []ResourceRule, globalTagKeys map[string]bool)
 	return nil
 }
 
+// validateDirectoryOverlays validates the directory overlays, values of tags defined in the standard must match their spec
+func validateDirectoryOverlays(standard *TagStandard) error {
+	for i, overlay := range standard.DirectoryOverlays {
+		if overlay.Path == "" {
+			return fmt.Errorf("directory_overlays[%d]: path is required", i)
+		}
+
+		if _, err := doublestar.Match(overlay.Path, overlay.Path); err != nil {
+			return fmt.Errorf("directory_overlays[%d]: invalid path glob '%s': %w", i, overlay.Path, err)
+		}
+
+		if len(overlay.Tags) == 0 {
+			return fmt.Errorf("directory_overlays[%d]: tags cannot be empty", i)
+		}
+
+		for key, value := range overlay.Tags {
+			if key == "" {
+				return fmt.Errorf("directory_overlays[%d]: empty tag key", i)
+			}
+
+			spec := standard.findTagSpec(key)
+			if spec == nil {
+				continue
+			}
+
+			if err := validateTagValue(*spec, value); err != nil {
+				return fmt.Errorf("directory_overlays[%d]: invalid value '%s' for tag '%s': %w", i, value, key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 // isValidDataType checks if a data type is supported
 func isValidDataType(dataType DataType) bool {
 	switch dataType {
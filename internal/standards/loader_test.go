@@ -0,0 +1,66 @@
+package standards
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDirectoryOverlays(t *testing.T) {
+	newStandard := func(overlay DirectoryOverlay) *TagStandard {
+		return &TagStandard{
+			Version:       1,
+			CloudProvider: "aws",
+			RequiredTags: []TagSpec{
+				{Key: "Environment", AllowedValues: []string{"prod", "dev"}},
+			},
+			DirectoryOverlays: []DirectoryOverlay{overlay},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		overlay       DirectoryOverlay
+		errorContains string
+	}{
+		{
+			name:    "valid overlay",
+			overlay: DirectoryOverlay{Path: "envs/prod/**", Tags: map[string]string{"Environment": "prod", "Stack": "network"}},
+		},
+		{
+			name:          "missing path",
+			overlay:       DirectoryOverlay{Tags: map[string]string{"Environment": "prod"}},
+			errorContains: "directory_overlays[0]: path is required",
+		},
+		{
+			name:          "invalid glob",
+			overlay:       DirectoryOverlay{Path: "envs/[prod", Tags: map[string]string{"Environment": "prod"}},
+			errorContains: "invalid path glob 'envs/[prod'",
+		},
+		{
+			name:          "no tags",
+			overlay:       DirectoryOverlay{Path: "envs/prod/**"},
+			errorContains: "directory_overlays[0]: tags cannot be empty",
+		},
+		{
+			name:          "value violating the tag spec",
+			overlay:       DirectoryOverlay{Path: "envs/qa/**", Tags: map[string]string{"Environment": "qa"}},
+			errorContains: "directory_overlays[0]: invalid value 'qa' for tag 'Environment'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStandard(newStandard(tt.overlay))
+			if tt.errorContains == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.errorContains)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package standards
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -35,6 +36,12 @@ func (r *ReportGenerator) GenerateReport(report ValidationReport, outputPath str
 		return r.generateTableReport(report, outputPath)
 	case ReportFormatMarkdown:
 		return r.generateMarkdownReport(report, outputPath)
+	case ReportFormatSARIF:
+		return r.generateSARIFReport(report, outputPath)
+	case ReportFormatJUnit:
+		return r.generateJUnitReport(report, outputPath)
+	case ReportFormatHTML:
+		return r.generateHTMLReport(report, outputPath)
 	default:
 		return fmt.Errorf("unsupported report format: %s", r.options.ReportFormat)
 	}
@@ -84,6 +91,12 @@ func (r *ReportGenerator) generateTableReport(report ValidationReport, outputPat
 	if len(report.Results) > 0 {
 		r.writeDetailedResults(&output, report)
 	}
+
+	// Resources exempted by annotations
+	r.writeSuppressedResources(&output, report)
+
+	// Values sanitized into Google label values
+	r.writeLabelValues(&output, report)
 	
 	// Resource type breakdown
 	r.writeResourceTypeBreakdown(&output, report)
@@ -201,6 +214,34 @@ func (r *ReportGenerator) generateMarkdownReport(report ValidationReport, output
 		}
 	}
 
+	// Resources exempted by annotations
+	if len(report.SuppressedResources) > 0 {
+		output.WriteString("## Suppressed Resources\n\n")
+		output.WriteString("| Resource | Type | File | Annotation | Reason |\n")
+		output.WriteString("|----------|------|------|------------|--------|\n")
+
+		for _, suppressed := range report.SuppressedResources {
+			output.WriteString(fmt.Sprintf("| %s | %s | %s | `%s` | %s |\n",
+				suppressed.ResourceName, suppressed.ResourceType, suppressedLocation(suppressed), suppressed.Annotation, suppressedReason(suppressed)))
+		}
+		output.WriteString("\n")
+	}
+
+	// Values sanitized into Google label values
+	if hasLabelValues(report) {
+		output.WriteString("## Label Values\n\n")
+		output.WriteString("| Resource | Type | Tag | Original | Label |\n")
+		output.WriteString("|----------|------|-----|----------|-------|\n")
+
+		for _, result := range report.Results {
+			for _, label := range result.LabelValues {
+				output.WriteString(fmt.Sprintf("| %s | %s | %s | `%s` | `%s` |\n",
+					result.ResourceName, result.ResourceType, label.TagKey, label.Original, label.Transformed))
+			}
+		}
+		output.WriteString("\n")
+	}
+
 	if outputPath == "" || outputPath == "-" {
 		fmt.Print(output.String())
 		return nil
@@ -262,6 +303,78 @@ func (r *ReportGenerator) writeDetailedResults(output *strings.Builder, report V
 	output.WriteString("\n")
 }
 
+// writeSuppressedResources writes the resources exempted from validation by annotations
+func (r *ReportGenerator) writeSuppressedResources(output *strings.Builder, report ValidationReport) {
+	if len(report.SuppressedResources) == 0 {
+		return
+	}
+
+	output.WriteString("SUPPRESSED RESOURCES\n")
+	output.WriteString("--------------------\n\n")
+
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Resource\tType\tFile\tAnnotation\tReason\n")
+	fmt.Fprintf(w, "--------\t----\t----\t----------\t------\n")
+
+	for _, suppressed := range report.SuppressedResources {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			suppressed.ResourceName, suppressed.ResourceType, suppressedLocation(suppressed), suppressed.Annotation, suppressedReason(suppressed))
+	}
+	w.Flush()
+	output.WriteString("\n")
+}
+
+// writeLabelValues writes the tag values sanitized into Google label values with their original value
+func (r *ReportGenerator) writeLabelValues(output *strings.Builder, report ValidationReport) {
+	if !hasLabelValues(report) {
+		return
+	}
+
+	output.WriteString("LABEL VALUES\n")
+	output.WriteString("------------\n\n")
+
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Resource\tType\tTag\tOriginal\tLabel\n")
+	fmt.Fprintf(w, "--------\t----\t---\t--------\t-----\n")
+
+	for _, result := range report.Results {
+		for _, label := range result.LabelValues {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", result.ResourceName, result.ResourceType, label.TagKey, label.Original, label.Transformed)
+		}
+	}
+	w.Flush()
+	output.WriteString("\n")
+}
+
+// hasLabelValues returns true if the values of a resource of the report were sanitized into label values
+func hasLabelValues(report ValidationReport) bool {
+	for _, result := range report.Results {
+		if len(result.LabelValues) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// suppressedLocation returns the file and line of a suppressed resource
+func suppressedLocation(suppressed SuppressedResource) string {
+	if suppressed.LineNumber == 0 {
+		return suppressed.FilePath
+	}
+
+	return fmt.Sprintf("%s:%d", suppressed.FilePath, suppressed.LineNumber)
+}
+
+// suppressedReason returns the reason of a suppression, exemptions without one are flagged
+func suppressedReason(suppressed SuppressedResource) string {
+	if suppressed.Reason == "" {
+		return "(no reason given)"
+	}
+
+	return suppressed.Reason
+}
+
 // writeResourceTypeBreakdown writes resource type compliance breakdown
 func (r *ReportGenerator) writeResourceTypeBreakdown(output *strings.Builder, report ValidationReport) {
 	if len(report.Summary.ResourceTypeBreakdown) == 0 {
@@ -398,6 +511,33 @@ func (r *ReportGenerator) writeViolationSummary(output *strings.Builder, report
 	output.WriteString("\n")
 }
 
+// suggestedFixFor returns the suggested fix of a tag of a resource
+func suggestedFixFor(result ValidationResult, tagKey string) (SuggestedFix, bool) {
+	for _, fix := range result.SuggestedFixes {
+		if fix.TagKey == tagKey {
+			return fix, true
+		}
+	}
+
+	return SuggestedFix{}, false
+}
+
+// describeFix returns a plain text description of a suggested fix
+func describeFix(fix SuggestedFix) string {
+	switch fix.Action {
+	case ActionAdd:
+		return fmt.Sprintf("add tag '%s' with value '%s'", fix.TagKey, fix.SuggestedValue)
+	case ActionUpdate:
+		return fmt.Sprintf("update tag '%s' from '%s' to '%s'", fix.TagKey, fix.CurrentValue, fix.SuggestedValue)
+	case ActionRemove:
+		return fmt.Sprintf("remove tag '%s'", fix.TagKey)
+	case ActionFormat:
+		return fmt.Sprintf("format tag '%s' to '%s'", fix.TagKey, fix.SuggestedValue)
+	default:
+		return fix.Reason
+	}
+}
+
 // filterNonCompliantResources returns only non-compliant resources
 func (r *ReportGenerator) filterNonCompliantResources(results []ValidationResult) []ValidationResult {
 	var nonCompliant []ValidationResult
@@ -409,15 +549,18 @@ func (r *ReportGenerator) filterNonCompliantResources(results []ValidationResult
 	return nonCompliant
 }
 
-// PrintSummary prints a quick summary to stdout
-func PrintSummary(report ValidationReport) {
-	fmt.Printf("Tag Compliance Summary:\n")
-	fmt.Printf("  Total resources: %d\n", report.TotalResources)
-	fmt.Printf("  Compliant: %d (%.1f%%)\n", report.CompliantResources, report.Summary.ComplianceRate*100)
-	fmt.Printf("  Non-compliant: %d\n", report.NonCompliantResources)
+// PrintSummary prints a quick summary to w, pass os.Stderr when the report itself is written to stdout
+func PrintSummary(w io.Writer, report ValidationReport) {
+	fmt.Fprintf(w, "Tag Compliance Summary:\n")
+	fmt.Fprintf(w, "  Total resources: %d\n", report.TotalResources)
+	fmt.Fprintf(w, "  Compliant: %d (%.1f%%)\n", report.CompliantResources, report.Summary.ComplianceRate*100)
+	fmt.Fprintf(w, "  Non-compliant: %d\n", report.NonCompliantResources)
+	if len(report.SuppressedResources) > 0 {
+		fmt.Fprintf(w, "  Suppressed by annotations: %d\n", len(report.SuppressedResources))
+	}
 	
 	if report.NonCompliantResources > 0 {
-		fmt.Printf("\nMost common issues:\n")
+		fmt.Fprintf(w, "\nMost common issues:\n")
 		violations := make([]ViolationSummary, len(report.Summary.MostCommonViolations))
 		copy(violations, report.Summary.MostCommonViolations)
 		sort.Slice(violations, func(i, j int) bool {
@@ -428,7 +571,7 @@ func PrintSummary(report ValidationReport) {
 			if i >= 3 { // Show only top 3
 				break
 			}
-			fmt.Printf("  • %s: %d occurrences\n", 
+			fmt.Fprintf(w, "  • %s: %d occurrences\n", 
 				strings.ReplaceAll(string(violation.ViolationType), "_", " "), violation.Count)
 		}
 	}
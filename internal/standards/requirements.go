@@ -0,0 +1,100 @@
+package standards
+
+import (
+	"regexp"
+	"strings"
+)
+
+// IsGloballyExcluded returns true if the resource type is excluded from the standard
+func (s *TagStandard) IsGloballyExcluded(resourceType string) bool {
+	for _, excluded := range s.GlobalExcludes {
+		if resourceType == excluded {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveTagRequirements returns the required, optional and excluded tags of a resource type,
+// after applying the resource rules matching it
+func (s *TagStandard) EffectiveTagRequirements(resourceType string) ([]TagSpec, []TagSpec, []string) {
+	// Start with global requirements
+	requiredTags := make([]TagSpec, len(s.RequiredTags))
+	copy(requiredTags, s.RequiredTags)
+
+	optionalTags := make([]TagSpec, len(s.OptionalTags))
+	copy(optionalTags, s.OptionalTags)
+
+	var excludedTags []string
+
+	// Apply resource-specific rules
+	for _, rule := range s.ResourceRules {
+		if resourceTypeMatches(resourceType, rule.ResourceTypes) {
+			// Add resource-specific required tags
+			for _, tagKey := range rule.RequiredTags {
+				if spec := s.findTagSpec(tagKey); spec != nil {
+					requiredTags = append(requiredTags, *spec)
+				}
+			}
+
+			// Add resource-specific optional tags
+			for _, tagKey := range rule.OptionalTags {
+				if spec := s.findTagSpec(tagKey); spec != nil {
+					optionalTags = append(optionalTags, *spec)
+				}
+			}
+
+			// Add excluded tags
+			excludedTags = append(excludedTags, rule.ExcludedTags...)
+
+			// Apply overrides
+			for _, override := range rule.OverrideTags {
+				// Replace existing spec with override
+				for i, existing := range requiredTags {
+					if existing.Key == override.Key {
+						requiredTags[i] = override
+						break
+					}
+				}
+				for i, existing := range optionalTags {
+					if existing.Key == override.Key {
+						optionalTags[i] = override
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return requiredTags, optionalTags, excludedTags
+}
+
+func resourceTypeMatches(resourceType string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if resourceType == pattern {
+			return true
+		}
+		// Support wildcard matching
+		if strings.Contains(pattern, "*") {
+			matched, _ := regexp.MatchString(strings.Replace(pattern, "*", ".*", -1), resourceType)
+			if matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *TagStandard) findTagSpec(tagKey string) *TagSpec {
+	for _, tag := range s.RequiredTags {
+		if tag.Key == tagKey {
+			return &tag
+		}
+	}
+	for _, tag := range s.OptionalTags {
+		if tag.Key == tagKey {
+			return &tag
+		}
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package standards
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	// sarifSourceRoot is the base of the relative file URIs, code scanning tools resolve it to the repository root
+	sarifSourceRoot = "%SRCROOT%"
+)
+
+// sarifRuleSpec describes the SARIF rule of a violation type
+type sarifRuleSpec struct {
+	violationType ViolationType
+	description   string
+	level         string // Level of the results of the rule: error or warning
+}
+
+// sarifRules are the rules of every violation type, in the order of the rules of the report
+var sarifRules = []sarifRuleSpec{
+	{ViolationMissingRequired, "A tag required by the tag standard is missing", "error"},
+	{ViolationNotAllowed, "A tag is not defined in the tag standard or is excluded from the resource type", "error"},
+	{ViolationInvalidValue, "A tag value is not one of the allowed values of the tag standard", "error"},
+	{ViolationInvalidFormat, "A tag value doesn't match the format of the tag standard", "error"},
+	{ViolationInvalidDataType, "A tag value doesn't match the data type of the tag standard", "error"},
+	{ViolationLengthExceeded, "A tag value is longer than the tag standard allows", "error"},
+	{ViolationLengthTooShort, "A tag value is shorter than the tag standard allows", "error"},
+	{ViolationCaseMismatch, "A tag value doesn't match the case of the allowed values of the tag standard", "error"},
+	{ViolationUnresolvableValue, "A tag value can't be resolved and validated", "warning"},
+	{ViolationVariableNotDefined, "A tag value references an undefined variable", "warning"},
+	{ViolationLocalNotDefined, "A tag value references an undefined local", "warning"},
+	{ViolationTagLimitExceeded, "A resource has more tags than its cloud provider allows", "error"},
+	{ViolationKeyLengthExceeded, "A tag key is longer than its cloud provider allows", "error"},
+	{ViolationValueLengthExceeded, "A tag value is longer than its cloud provider allows", "error"},
+	{ViolationReservedPrefix, "A tag key starts with a prefix reserved by its cloud provider", "error"},
+	{ViolationInvalidCharacters, "A tag key or value contains characters its cloud provider rejects", "error"},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          sarifProperties   `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifProperties struct {
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+	TagKey       string `json:"tagKey,omitempty"`
+	TagValue     string `json:"tagValue,omitempty"`
+	SuggestedFix string `json:"suggestedFix,omitempty"`
+}
+
+// generateSARIFReport creates a SARIF 2.1.0 report for code scanning tools, with a result per missing tag,
+// tag that isn't allowed and tag violation of the non-compliant resources
+func (r *ReportGenerator) generateSARIFReport(report ValidationReport, outputPath string) error {
+	data, err := json.MarshalIndent(newSARIFLog(report), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report to SARIF: %w", err)
+	}
+
+	if outputPath == "" || outputPath == "-" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	return os.WriteFile(outputPath, data, 0644)
+}
+
+// newSARIFLog converts a validation report into a SARIF log with a single run
+func newSARIFLog(report ValidationReport) sarifLog {
+	driver := sarifDriver{
+		Name:           "terratag",
+		InformationURI: "https://terratag.io",
+		Rules:          make([]sarifRule, len(sarifRules)),
+	}
+
+	for i, rule := range sarifRules {
+		driver.Rules[i] = sarifRule{
+			ID:                   sarifRuleID(rule.violationType),
+			Name:                 sarifRuleName(rule.violationType),
+			ShortDescription:     sarifMessage{Text: rule.description},
+			DefaultConfiguration: sarifConfiguration{Level: rule.level},
+		}
+	}
+
+	results := []sarifResult{}
+
+	for _, result := range report.Results {
+		for _, key := range result.MissingTags {
+			results = append(results, newSARIFResult(result, ViolationMissingRequired, key, "",
+				fmt.Sprintf("Required tag '%s' is missing", key)))
+		}
+
+		for _, key := range result.ExtraTags {
+			results = append(results, newSARIFResult(result, ViolationNotAllowed, key, "",
+				fmt.Sprintf("Tag '%s' is not allowed on resource type '%s'", key, result.ResourceType)))
+		}
+
+		for _, violation := range result.Violations {
+			results = append(results, newSARIFResult(result, violation.ViolationType, violation.TagKey, violation.TagValue, violation.Message))
+		}
+	}
+
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+}
+
+// newSARIFResult returns the SARIF result of an issue with a tag of a resource, the suggested fix of the tag is
+// appended to the message
+func newSARIFResult(result ValidationResult, violationType ViolationType, tagKey, tagValue, message string) sarifResult {
+	ruleIndex := slices.IndexFunc(sarifRules, func(rule sarifRuleSpec) bool {
+		return rule.violationType == violationType
+	})
+
+	level := "error"
+	if ruleIndex >= 0 {
+		level = sarifRules[ruleIndex].level
+	}
+
+	address := result.ResourceType + "." + result.ResourceName
+	properties := sarifProperties{
+		ResourceType: result.ResourceType,
+		ResourceName: result.ResourceName,
+		TagKey:       tagKey,
+		TagValue:     tagValue,
+	}
+
+	text := fmt.Sprintf("%s: %s", address, strings.TrimSuffix(message, "."))
+	if fix, ok := suggestedFixFor(result, tagKey); ok {
+		properties.SuggestedFix = describeFix(fix)
+		text += ". Suggested fix: " + properties.SuggestedFix
+	}
+
+	sarif := sarifResult{
+		RuleID:    sarifRuleID(violationType),
+		RuleIndex: ruleIndex,
+		Level:     level,
+		Message:   sarifMessage{Text: text},
+		// Identifies the result across runs when lines move
+		PartialFingerprints: map[string]string{"resourceTag/v1": address + "/" + tagKey + "/" + string(violationType)},
+		Properties:          properties,
+	}
+
+	if result.FilePath != "" {
+		uri, uriBaseID := sarifArtifactURI(result.FilePath)
+		location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: uri, URIBaseID: uriBaseID},
+		}}
+
+		if result.LineNumber > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: result.LineNumber}
+		}
+
+		sarif.Locations = []sarifLocation{location}
+	}
+
+	return sarif
+}
+
+// sarifRuleID returns the rule ID of a violation type, e.g. terratag/missing_required
+func sarifRuleID(violationType ViolationType) string {
+	return "terratag/" + string(violationType)
+}
+
+// sarifRuleName returns the Pascal case name of a violation type, e.g. MissingRequired
+func sarifRuleName(violationType ViolationType) string {
+	words := strings.Split(string(violationType), "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+
+	return strings.Join(words, "")
+}
+
+// sarifArtifactURI returns the URI of a file: relative paths are relative to the source root, absolute paths are
+// file URIs
+func sarifArtifactURI(filePath string) (string, string) {
+	if !filepath.IsAbs(filePath) {
+		return (&url.URL{Path: path.Clean(filepath.ToSlash(filePath))}).String(), sarifSourceRoot
+	}
+
+	uri := filepath.ToSlash(filePath)
+	if !strings.HasPrefix(uri, "/") {
+		uri = "/" + uri
+	}
+
+	return (&url.URL{Scheme: "file", Path: uri}).String(), ""
+}
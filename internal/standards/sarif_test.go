@@ -0,0 +1,91 @@
+package standards
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSARIFReport(t *testing.T) {
+	report := ValidationReport{
+		Results: []ValidationResult{
+			{
+				ResourceType: "aws_instance",
+				ResourceName: "web",
+				FilePath:     "./envs/prod/main.tf",
+				LineNumber:   12,
+				MissingTags:  []string{"Owner"},
+				ExtraTags:    []string{"Temp"},
+				Violations: []TagViolation{{
+					TagKey:        "Environment",
+					TagValue:      "qa",
+					ViolationType: ViolationInvalidValue,
+					Message:       "Value 'qa' is not in allowed values: [prod dev]",
+				}},
+				SuggestedFixes: []SuggestedFix{
+					{TagKey: "Owner", SuggestedValue: "platform", Action: ActionAdd},
+					{TagKey: "Temp", CurrentValue: "true", Action: ActionRemove},
+					{TagKey: "Environment", CurrentValue: "qa", SuggestedValue: "prod", Action: ActionUpdate},
+				},
+			},
+			{ResourceType: "aws_s3_bucket", ResourceName: "logs", FilePath: "main.tf", IsCompliant: true},
+		},
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.sarif")
+	generator := NewReportGenerator(ValidationOptions{ReportFormat: ReportFormatSARIF})
+	require.NoError(t, generator.GenerateReport(report, outputPath))
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(data, &log))
+
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Equal(t, "terratag", run.Tool.Driver.Name)
+	assert.Equal(t, "terratag/missing_required", run.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "MissingRequired", run.Tool.Driver.Rules[0].Name)
+
+	require.Len(t, run.Results, 3)
+
+	missing := run.Results[0]
+	assert.Equal(t, "terratag/missing_required", missing.RuleID)
+	assert.Equal(t, 0, missing.RuleIndex)
+	assert.Equal(t, "error", missing.Level)
+	assert.Equal(t, "aws_instance.web: Required tag 'Owner' is missing. Suggested fix: add tag 'Owner' with value 'platform'", missing.Message.Text)
+	require.Len(t, missing.Locations, 1)
+	assert.Equal(t, sarifArtifactLocation{URI: "envs/prod/main.tf", URIBaseID: "%SRCROOT%"}, missing.Locations[0].PhysicalLocation.ArtifactLocation)
+	assert.Equal(t, &sarifRegion{StartLine: 12}, missing.Locations[0].PhysicalLocation.Region)
+
+	assert.Equal(t, "terratag/not_allowed", run.Results[1].RuleID)
+	assert.Equal(t, "remove tag 'Temp'", run.Results[1].Properties.SuggestedFix)
+
+	invalid := run.Results[2]
+	assert.Equal(t, "terratag/invalid_value", invalid.RuleID)
+	assert.Equal(t, run.Tool.Driver.Rules[invalid.RuleIndex].ID, invalid.RuleID)
+	assert.Equal(t, "qa", invalid.Properties.TagValue)
+	assert.Equal(t, "aws_instance.web/Environment/invalid_value", invalid.PartialFingerprints["resourceTag/v1"])
+}
+
+func TestSARIFArtifactURI(t *testing.T) {
+	uri, base := sarifArtifactURI(filepath.Join("modules", "my network", "main.tf"))
+	assert.Equal(t, "modules/my%20network/main.tf", uri)
+	assert.Equal(t, "%SRCROOT%", base)
+
+	absolute, err := filepath.Abs("main.tf")
+	require.NoError(t, err)
+
+	uri, base = sarifArtifactURI(absolute)
+	assert.True(t, strings.HasPrefix(uri, "file:///"), uri)
+	assert.True(t, strings.HasSuffix(uri, "/internal/standards/main.tf"), uri)
+	assert.Empty(t, base)
+}
@@ -0,0 +1,184 @@
+package standards
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// templatePlaceholder matches the placeholders of tag default values, e.g. {{env:CI_PROJECT}} or {{dir.name}}
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// TemplateContext holds the values the placeholders of tag default values are resolved with
+type TemplateContext struct {
+	Dir       string                          // Directory of the tagged files
+	WorkDir   string                          // Directory dir.relative is relative to
+	Now       time.Time                       // Time of {{date}}
+	LookupEnv func(key string) (string, bool) // Environment of {{env:NAME}}
+}
+
+// NewTemplateContext returns the context of tagging dir from the current working directory and environment
+func NewTemplateContext(dir string) TemplateContext {
+	workDir, _ := os.Getwd()
+
+	return TemplateContext{
+		Dir:       dir,
+		WorkDir:   workDir,
+		Now:       time.Now(),
+		LookupEnv: os.LookupEnv,
+	}
+}
+
+// ResolveTemplates replaces the placeholders of the default values of the standard:
+//
+//	{{env:NAME}}          value of the NAME environment variable, which must be set
+//	{{dir.name}}          name of ctx.Dir
+//	{{dir.relative}}      path of ctx.Dir relative to the working directory
+//	{{date}}              current UTC date (YYYY-MM-DD)
+//	{{standard.version}}  metadata.version of the standard, or its version if not set
+//
+// Resolved literal default values are validated against their tag specification.
+func (s *TagStandard) ResolveTemplates(ctx TemplateContext) error {
+	resolve := func(specs []TagSpec, path string) error {
+		for i := range specs {
+			spec := &specs[i]
+
+			if !templatePlaceholder.MatchString(spec.DefaultValue) {
+				continue
+			}
+
+			resolved, err := s.resolveTemplate(spec.DefaultValue, ctx)
+			if err != nil {
+				return fmt.Errorf("%s: invalid default_value of tag '%s': %w", path, spec.Key, err)
+			}
+
+			spec.DefaultValue = resolved
+
+			if spec.DefaultIsExpression {
+				if err := ValidateTagExpression(resolved); err != nil {
+					return fmt.Errorf("%s: default_value of tag '%s' resolved to an invalid expression %s: %w", path, spec.Key, resolved, err)
+				}
+			} else if err := validateTagValue(*spec, resolved); err != nil {
+				return fmt.Errorf("%s: default_value of tag '%s' resolved to '%s': %w", path, spec.Key, resolved, err)
+			}
+		}
+
+		return nil
+	}
+
+	if err := resolve(s.RequiredTags, "required_tags"); err != nil {
+		return err
+	}
+
+	if err := resolve(s.OptionalTags, "optional_tags"); err != nil {
+		return err
+	}
+
+	for i := range s.ResourceRules {
+		if err := resolve(s.ResourceRules[i].OverrideTags, fmt.Sprintf("resource_rules[%d].override_tags", i)); err != nil {
+			return err
+		}
+	}
+
+	for _, provider := range slices.Sorted(maps.Keys(s.ProviderOverrides)) {
+		if err := resolve(s.ProviderOverrides[provider].OverrideTags, fmt.Sprintf("provider_overrides.%s.override_tags", provider)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UsesDirTemplates returns true if a default value of the standard has a {{dir.name}} or {{dir.relative}} placeholder,
+// whose value depends on the directory of the tagged files
+func (s *TagStandard) UsesDirTemplates() bool {
+	specs := slices.Concat(s.RequiredTags, s.OptionalTags)
+
+	for _, rule := range s.ResourceRules {
+		specs = append(specs, rule.OverrideTags...)
+	}
+
+	for _, override := range s.ProviderOverrides {
+		specs = append(specs, override.OverrideTags...)
+	}
+
+	for _, spec := range specs {
+		for _, match := range templatePlaceholder.FindAllStringSubmatch(spec.DefaultValue, -1) {
+			if strings.HasPrefix(match[1], "dir.") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (s *TagStandard) resolveTemplate(value string, ctx TemplateContext) (string, error) {
+	var resolveErr error
+
+	resolved := templatePlaceholder.ReplaceAllStringFunc(value, func(match string) string {
+		placeholder := templatePlaceholder.FindStringSubmatch(match)[1]
+
+		replacement, err := s.placeholderValue(placeholder, ctx)
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+
+		return replacement
+	})
+
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+
+	return resolved, nil
+}
+
+func (s *TagStandard) placeholderValue(placeholder string, ctx TemplateContext) (string, error) {
+	if name, ok := strings.CutPrefix(placeholder, "env:"); ok {
+		value, ok := ctx.LookupEnv(strings.TrimSpace(name))
+		if !ok {
+			return "", fmt.Errorf("environment variable %s of placeholder {{%s}} is not set", strings.TrimSpace(name), placeholder)
+		}
+
+		return value, nil
+	}
+
+	switch placeholder {
+	case "dir.name":
+		dir, err := filepath.Abs(ctx.Dir)
+		if err != nil {
+			return "", err
+		}
+
+		return filepath.Base(dir), nil
+	case "dir.relative":
+		dir, err := filepath.Abs(ctx.Dir)
+		if err != nil {
+			return "", err
+		}
+
+		relative, err := filepath.Rel(ctx.WorkDir, dir)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve {{dir.relative}}: %w", err)
+		}
+
+		return filepath.ToSlash(relative), nil
+	case "date":
+		return ctx.Now.UTC().Format("2006-01-02"), nil
+	case "standard.version":
+		if s.Metadata.Version != "" {
+			return s.Metadata.Version, nil
+		}
+
+		return strconv.Itoa(s.Version), nil
+	}
+
+	return "", fmt.Errorf("unknown placeholder {{%s}}", placeholder)
+}
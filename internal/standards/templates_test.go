@@ -0,0 +1,103 @@
+package standards
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTemplateContext(t *testing.T) TemplateContext {
+	workDir := t.TempDir()
+
+	return TemplateContext{
+		Dir:     filepath.Join(workDir, "stacks", "network"),
+		WorkDir: workDir,
+		Now:     time.Date(2024, 3, 9, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*60*60)),
+		LookupEnv: func(key string) (string, bool) {
+			if key == "CI_PROJECT" {
+				return "billing", true
+			}
+
+			return "", false
+		},
+	}
+}
+
+func TestResolveTemplates(t *testing.T) {
+	standard := &TagStandard{
+		Version:  1,
+		Metadata: Metadata{Version: "2.1"},
+		RequiredTags: []TagSpec{
+			{Key: "Project", DefaultValue: "{{env:CI_PROJECT}}"},
+			{Key: "Stack", DefaultValue: "{{ dir.name }}"},
+			{Key: "Path", DefaultValue: "{{dir.relative}}"},
+			{Key: "Owner", DefaultValue: "team@corp.com"},
+		},
+		OptionalTags: []TagSpec{
+			{Key: "TaggedOn", DefaultValue: "{{date}}"},
+			{Key: "Standard", DefaultValue: "v{{standard.version}}"},
+		},
+		ResourceRules: []ResourceRule{
+			{OverrideTags: []TagSpec{{Key: "Component", DefaultValue: `"${local.team}-{{dir.name}}"`, DefaultIsExpression: true}}},
+		},
+	}
+
+	assert.True(t, standard.UsesDirTemplates())
+
+	require.NoError(t, standard.ResolveTemplates(testTemplateContext(t)))
+
+	assert.False(t, standard.UsesDirTemplates())
+
+	assert.Equal(t, "billing", standard.RequiredTags[0].DefaultValue)
+	assert.Equal(t, "network", standard.RequiredTags[1].DefaultValue)
+	assert.Equal(t, "stacks/network", standard.RequiredTags[2].DefaultValue)
+	assert.Equal(t, "team@corp.com", standard.RequiredTags[3].DefaultValue)
+	assert.Equal(t, "2024-03-10", standard.OptionalTags[0].DefaultValue)
+	assert.Equal(t, "v2.1", standard.OptionalTags[1].DefaultValue)
+	assert.Equal(t, `"${local.team}-network"`, standard.ResourceRules[0].OverrideTags[0].DefaultValue)
+}
+
+func TestResolveTemplates_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          TagSpec
+		errorContains string
+	}{
+		{
+			name:          "unset environment variable",
+			spec:          TagSpec{Key: "Project", DefaultValue: "{{env:UNSET}}"},
+			errorContains: "environment variable UNSET of placeholder {{env:UNSET}} is not set",
+		},
+		{
+			name:          "unknown placeholder",
+			spec:          TagSpec{Key: "Stack", DefaultValue: "{{dir.parent}}"},
+			errorContains: "unknown placeholder {{dir.parent}}",
+		},
+		{
+			name:          "resolved value violates the format",
+			spec:          TagSpec{Key: "Stack", DefaultValue: "{{dir.name}}", Format: "^[A-Z]+$"},
+			errorContains: "default_value of tag 'Stack' resolved to 'network'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standard := &TagStandard{Version: 1, RequiredTags: []TagSpec{tt.spec}}
+
+			err := standard.ResolveTemplates(testTemplateContext(t))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+			assert.Contains(t, err.Error(), "required_tags")
+		})
+	}
+}
+
+func TestResolveTemplates_StandardVersion(t *testing.T) {
+	standard := &TagStandard{Version: 1, RequiredTags: []TagSpec{{Key: "Standard", DefaultValue: "{{standard.version}}"}}}
+
+	require.NoError(t, standard.ResolveTemplates(testTemplateContext(t)))
+	assert.Equal(t, "1", standard.RequiredTags[0].DefaultValue)
+}
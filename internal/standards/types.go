@@ -13,10 +13,17 @@ type TagStandard struct {
 	Metadata Metadata `yaml:"metadata"`
 	// CloudProvider specifies the cloud provider (aws, gcp, azure)
 	CloudProvider   string        `yaml:"cloud_provider"`
+	// CloudProviders are the cloud providers of a multi-cloud standard, the first one is the default when
+	// cloud_provider is not set
+	CloudProviders    []string                    `yaml:"cloud_providers,omitempty"`
+	ProviderOverrides map[string]ProviderOverride `yaml:"provider_overrides,omitempty"` // Per-provider variants of the standard
 	RequiredTags    []TagSpec     `yaml:"required_tags"`
 	OptionalTags    []TagSpec     `yaml:"optional_tags"`
 	GlobalExcludes  []string      `yaml:"global_excludes,omitempty"`  // Resource types to exclude globally
 	ResourceRules   []ResourceRule `yaml:"resource_rules,omitempty"`   // Per-resource type rules
+	DirectoryOverlays []DirectoryOverlay `yaml:"directory_overlays,omitempty"` // Per-directory tag values of tagging mode
+	// SanitizeLabelValues transforms the tag values of Google resources into valid label values
+	SanitizeLabelValues bool `yaml:"sanitize_label_values,omitempty"`
 }
 
 // Metadata contains information about the tag standard
@@ -29,16 +36,17 @@ type Metadata struct {
 
 // TagSpec defines the specification for a single tag
 type TagSpec struct {
-	Key             string   `yaml:"key"`
-	Description     string   `yaml:"description"`
-	AllowedValues   []string `yaml:"allowed_values,omitempty"`   // Finite list of allowed values
-	Format          string   `yaml:"format,omitempty"`           // Regex pattern for validation
-	DataType        DataType `yaml:"data_type,omitempty"`        // Expected data type
-	MinLength       int      `yaml:"min_length,omitempty"`       // Minimum string length
-	MaxLength       int      `yaml:"max_length,omitempty"`       // Maximum string length
-	CaseSensitive   bool     `yaml:"case_sensitive,omitempty"`   // Whether values are case sensitive
-	DefaultValue    string   `yaml:"default_value,omitempty"`    // Default value to apply if missing
-	Examples        []string `yaml:"examples,omitempty"`         // Example valid values
+	Key                 string   `yaml:"key"`
+	Description         string   `yaml:"description"`
+	AllowedValues       []string `yaml:"allowed_values,omitempty"`        // Finite list of allowed values
+	Format              string   `yaml:"format,omitempty"`                // Regex pattern for validation
+	DataType            DataType `yaml:"data_type,omitempty"`             // Expected data type
+	MinLength           int      `yaml:"min_length,omitempty"`            // Minimum string length
+	MaxLength           int      `yaml:"max_length,omitempty"`            // Maximum string length
+	CaseSensitive       bool     `yaml:"case_sensitive,omitempty"`        // Whether values are case sensitive
+	DefaultValue        string   `yaml:"default_value,omitempty"`         // Default value to apply if missing
+	DefaultIsExpression bool     `yaml:"default_is_expression,omitempty"` // Default value is a raw HCL expression (var.x, local.y, "${local.team}@corp.com")
+	Examples            []string `yaml:"examples,omitempty"`              // Example valid values
 }
 
 // DataType represents allowed data types for tag values
@@ -65,22 +73,66 @@ type ResourceRule struct {
 	OverrideTags    []TagSpec `yaml:"override_tags"`         // Override global tag specs for these resources
 }
 
+// ProviderOverride adapts a multi-cloud standard to one of its cloud providers
+type ProviderOverride struct {
+	KeyAliases     map[string]string `yaml:"key_aliases,omitempty"`     // Key of the standard to key of the provider, e.g. CostCenter: cost-center
+	OverrideTags   []TagSpec         `yaml:"override_tags,omitempty"`   // Replace the specs of standard tags, by standard key
+	GlobalExcludes []string          `yaml:"global_excludes,omitempty"` // Additional resource types of the provider to exclude
+}
+
+// DirectoryOverlay adds or overrides tag values for the files of the directories matched by a glob
+type DirectoryOverlay struct {
+	Path string            `yaml:"path"` // Glob of the directories relative to the tagged directory, e.g. envs/prod/**
+	Tags map[string]string `yaml:"tags"` // Literal tag values applied on top of the standard tags
+}
+
 // ValidationResult represents the result of tag validation
 type ValidationResult struct {
-	ResourceType         string             `json:"resource_type"`
-	ResourceName         string             `json:"resource_name"`
-	FilePath             string             `json:"file_path"`
-	LineNumber           int                `json:"line_number,omitempty"`        // Line number where resource starts
-	Snippet              string             `json:"snippet,omitempty"`            // Resource definition snippet
-	IsCompliant          bool               `json:"is_compliant"`
-	SupportsTagging      bool               `json:"supports_tagging"`
-	TaggingCapability    TaggingCapability  `json:"tagging_capability"`
-	Violations           []TagViolation     `json:"violations,omitempty"`
-	MissingTags          []string           `json:"missing_tags,omitempty"`
-	ExtraTags            []string           `json:"extra_tags,omitempty"`
-	SuggestedFixes       []SuggestedFix     `json:"suggested_fixes,omitempty"`
+	ResourceType      string               `json:"resource_type"`
+	ResourceName      string               `json:"resource_name"`
+	FilePath          string               `json:"file_path"`
+	LineNumber        int                  `json:"line_number,omitempty"` // Line number where resource starts
+	Snippet           string               `json:"snippet,omitempty"`     // Resource definition snippet
+	IsCompliant       bool                 `json:"is_compliant"`
+	SupportsTagging   bool                 `json:"supports_tagging"`
+	TaggingCapability TaggingCapability    `json:"tagging_capability"`
+	Violations        []TagViolation       `json:"violations,omitempty"`
+	MissingTags       []string             `json:"missing_tags,omitempty"`
+	ExtraTags         []string             `json:"extra_tags,omitempty"`
+	SuggestedFixes    []SuggestedFix       `json:"suggested_fixes,omitempty"`
+	TagSources        map[string]TagSource `json:"tag_sources,omitempty"` // Layer each effective tag came from
+	Suppression       *Suppression         `json:"suppression,omitempty"` // Inline annotation exempting the resource or some of its tags
+	LabelValues       []LabelValue         `json:"label_values,omitempty"` // Values sanitized into Google label values
+}
+
+// LabelValue is a tag value sanitized into a Google label value, either by tagging or as a suggested fix
+type LabelValue struct {
+	TagKey      string `json:"tag_key"`
+	Original    string `json:"original"`
+	Transformed string `json:"transformed"`
+}
+
+// Suppression is an inline annotation exempting a resource, or some of its tags, from validation
+type Suppression struct {
+	Annotation  string   `json:"annotation"`             // Annotations as written, e.g. terratag:ignore=Owner,CostCenter
+	IgnoredTags []string `json:"ignored_tags,omitempty"` // Tags exempted from validation, empty when the whole resource is
+	Reason      string   `json:"reason,omitempty"`
 }
 
+// SuppressesResource returns true if the whole resource is exempted from validation
+func (s *Suppression) SuppressesResource() bool {
+	return s != nil && len(s.IgnoredTags) == 0
+}
+
+// TagSource identifies the configuration layer an effective tag value came from
+type TagSource string
+
+const (
+	TagSourceResource         TagSource = "resource"          // Set on the resource only
+	TagSourceProviderDefault  TagSource = "provider_default"  // Inherited from provider default_tags/default_labels
+	TagSourceResourceOverride TagSource = "resource_override" // Set on the resource, overriding a provider default
+)
+
 // TaggingCapability provides detailed information about resource tagging support
 type TaggingCapability struct {
 	SupportsTagAttribute bool   `json:"supports_tag_attribute"`
@@ -115,6 +167,12 @@ const (
 	ViolationUnresolvableValue   ViolationType = "unresolvable_value"
 	ViolationVariableNotDefined ViolationType = "variable_not_defined"
 	ViolationLocalNotDefined    ViolationType = "local_not_defined"
+	// Limits of the cloud provider, checked whatever the standard allows
+	ViolationTagLimitExceeded    ViolationType = "tag_limit_exceeded"
+	ViolationKeyLengthExceeded   ViolationType = "key_length_exceeded"
+	ViolationValueLengthExceeded ViolationType = "value_length_exceeded"
+	ViolationReservedPrefix      ViolationType = "reserved_prefix"
+	ViolationInvalidCharacters   ViolationType = "invalid_characters"
 )
 
 // SuggestedFix represents a suggested fix for a violation
@@ -146,6 +204,19 @@ type ValidationReport struct {
 	TaggingSupport        TaggingSupportSummary `json:"tagging_support"`
 	Results               []ValidationResult `json:"results"`
 	Summary               ValidationSummary  `json:"summary"`
+	SuppressedResources   []SuppressedResource `json:"suppressed_resources,omitempty"`
+}
+
+// SuppressedResource is a resource exempted from validation, or validated without some of its tags,
+// by an inline annotation. Suppressed resources are listed so that exemptions stay auditable.
+type SuppressedResource struct {
+	ResourceType string   `json:"resource_type"`
+	ResourceName string   `json:"resource_name"`
+	FilePath     string   `json:"file_path"`
+	LineNumber   int      `json:"line_number,omitempty"`
+	Annotation   string   `json:"annotation"`
+	IgnoredTags  []string `json:"ignored_tags,omitempty"` // Empty when the whole resource is exempted
+	Reason       string   `json:"reason,omitempty"`
 }
 
 // TaggingSupportSummary provides insights into tagging capabilities
@@ -191,6 +262,7 @@ type TagValidator struct {
 	standard         *TagStandard
 	compiled         map[string]*regexp.Regexp          // Compiled regex patterns for performance
 	variableResolver *terraform.VariableResolver        // Variable resolver for handling vars and locals
+	variants         map[string]*TagValidator           // Validators of the provider variants of a multi-cloud standard
 }
 
 // ValidationOptions configures validation behavior
@@ -210,4 +282,7 @@ const (
 	ReportFormatYAML     ReportFormat = "yaml"
 	ReportFormatTable    ReportFormat = "table"
 	ReportFormatMarkdown ReportFormat = "markdown"
+	ReportFormatSARIF    ReportFormat = "sarif" // Static analysis results interchange format, for code scanning dashboards
+	ReportFormatJUnit    ReportFormat = "junit" // JUnit XML, rendered natively by CI systems
+	ReportFormatHTML     ReportFormat = "html"  // Self-contained HTML page
 )
\ No newline at end of file
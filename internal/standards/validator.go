@@ -3,6 +3,8 @@ package standards
 import (
 	"fmt"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -45,12 +47,36 @@ func NewTagValidator(standard *TagStandard) (*TagValidator, error) {
 		}
 	}
 
+	// Resources of multi-cloud standards are validated against the variant of their provider
+	if standard.IsMultiCloud() {
+		validator.variants = make(map[string]*TagValidator)
+
+		for _, provider := range standard.Providers() {
+			variant, err := NewTagValidator(standard.ForProvider(provider))
+			if err != nil {
+				return nil, fmt.Errorf("%s variant: %w", provider, err)
+			}
+
+			validator.variants[provider] = variant
+		}
+
+		validator.shareVariableResolver()
+	}
+
 	return validator, nil
 }
 
 // SetVariableResolver sets or replaces the variable resolver
 func (v *TagValidator) SetVariableResolver(resolver *terraform.VariableResolver) {
 	v.variableResolver = resolver
+	v.shareVariableResolver()
+}
+
+// shareVariableResolver makes the validators of the provider variants use the variable resolver
+func (v *TagValidator) shareVariableResolver() {
+	for _, variant := range v.variants {
+		variant.variableResolver = v.variableResolver
+	}
 }
 
 // GetVariableResolver returns the variable resolver
@@ -63,11 +89,22 @@ func (v *TagValidator) LoadVariablesFromDirectory(dirPath string) error {
 	// Always create a fresh resolver to ensure proper variable loading
 	// The existing resolver from NewTagValidator() is empty and needs to be replaced
 	v.variableResolver = terraform.NewVariableResolver(nil)
+	v.shareVariableResolver()
 	return v.variableResolver.LoadFromDirectory(dirPath)
 }
 
 // ValidateResourceTags validates all tags on a single resource
 func (v *TagValidator) ValidateResourceTags(resourceType, resourceName, filePath string, tags map[string]string) ValidationResult {
+	// Resources of the providers a multi-cloud standard doesn't target are validated against the default provider
+	if len(v.variants) > 0 {
+		provider, ok := v.standard.ResourceProvider(resourceType)
+		if !ok {
+			provider = v.standard.DefaultProvider()
+		}
+
+		return v.variants[provider].ValidateResourceTags(resourceType, resourceName, filePath, tags)
+	}
+
 	// Determine tagging capability
 	taggingCapability := v.getTaggingCapability(resourceType)
 	
@@ -145,6 +182,8 @@ func (v *TagValidator) ValidateResourceTags(resourceType, resourceName, filePath
 		tagSpecs[spec.Key] = spec
 	}
 
+	labels := v.standard.SanitizeLabelValues && providers.UsesLabels(resourceType, v.standard.CloudProvider)
+
 	for tagKey, tagValue := range tags {
 		// Check if tag is recognized
 		spec, isRecognized := tagSpecs[tagKey]
@@ -164,6 +203,14 @@ func (v *TagValidator) ValidateResourceTags(resourceType, resourceName, filePath
 			continue
 		}
 
+		// Label values sanitized from a value of the standard are validated as the original value
+		if labels {
+			if original, label, ok := v.labelValueOriginal(spec, tagValue); ok {
+				result.LabelValues = append(result.LabelValues, LabelValue{TagKey: tagKey, Original: original, Transformed: label})
+				tagValue = original
+			}
+		}
+
 		// Validate tag value against specification
 		if violations := v.validateTagValue(spec, tagKey, tagValue); len(violations) > 0 {
 			result.Violations = append(result.Violations, violations...)
@@ -176,60 +223,127 @@ func (v *TagValidator) ValidateResourceTags(resourceType, resourceName, filePath
 		}
 	}
 
+	// Check the limits of the cloud provider, whatever the standard allows
+	if violations := v.validateProviderConstraints(resourceType, tags); len(violations) > 0 {
+		result.Violations = append(result.Violations, violations...)
+		result.IsCompliant = false
+	}
+
+	if labels {
+		v.suggestLabelValues(tags, &result)
+	}
+
 	return result
 }
 
-// getEffectiveTagRequirements returns the effective tag requirements for a resource type
-func (v *TagValidator) getEffectiveTagRequirements(resourceType string) ([]TagSpec, []TagSpec, []string) {
-	// Start with global requirements
-	requiredTags := make([]TagSpec, len(v.standard.RequiredTags))
-	copy(requiredTags, v.standard.RequiredTags)
-	
-	optionalTags := make([]TagSpec, len(v.standard.OptionalTags))
-	copy(optionalTags, v.standard.OptionalTags)
-	
-	var excludedTags []string
-
-	// Apply resource-specific rules
-	for _, rule := range v.standard.ResourceRules {
-		if v.resourceTypeMatches(resourceType, rule.ResourceTypes) {
-			// Add resource-specific required tags
-			for _, tagKey := range rule.RequiredTags {
-				if spec := v.findTagSpec(tagKey); spec != nil {
-					requiredTags = append(requiredTags, *spec)
-				}
-			}
-			
-			// Add resource-specific optional tags
-			for _, tagKey := range rule.OptionalTags {
-				if spec := v.findTagSpec(tagKey); spec != nil {
-					optionalTags = append(optionalTags, *spec)
-				}
-			}
-			
-			// Add excluded tags
-			excludedTags = append(excludedTags, rule.ExcludedTags...)
-			
-			// Apply overrides
-			for _, override := range rule.OverrideTags {
-				// Replace existing spec with override
-				for i, existing := range requiredTags {
-					if existing.Key == override.Key {
-						requiredTags[i] = override
-						break
-					}
-				}
-				for i, existing := range optionalTags {
-					if existing.Key == override.Key {
-						optionalTags[i] = override
-						break
-					}
-				}
-			}
+// labelValueOriginal returns the allowed or default value of a tag spec a label value was sanitized from
+func (v *TagValidator) labelValueOriginal(spec TagSpec, tagValue string) (string, string, bool) {
+	label, uncertainty := v.resolveTagValue(tagValue)
+	if uncertainty != "" {
+		return "", "", false
+	}
+
+	if label == "" {
+		label = tagValue
+	}
+
+	for _, original := range append([]string{spec.DefaultValue}, spec.AllowedValues...) {
+		if original != "" && original != label && providers.SanitizeLabelValue(original) == label {
+			return original, label, true
+		}
+	}
+
+	return "", "", false
+}
+
+// suggestLabelValues suggests the label values tagging would sanitize the values that aren't valid Google label
+// values to, and sorts the label values of the result by key
+func (v *TagValidator) suggestLabelValues(tags map[string]string, result *ValidationResult) {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, uncertainty := v.resolveTagValue(tags[key])
+		if uncertainty != "" {
+			continue
+		}
+
+		label := providers.SanitizeLabelValue(value)
+		if label == value {
+			continue
+		}
+
+		result.LabelValues = append(result.LabelValues, LabelValue{TagKey: key, Original: value, Transformed: label})
+		result.SuggestedFixes = append(result.SuggestedFixes, SuggestedFix{
+			TagKey:         key,
+			CurrentValue:   tags[key],
+			SuggestedValue: label,
+			Action:         ActionUpdate,
+			Reason:         fmt.Sprintf("Value '%s' is not a valid Google label value", value),
+		})
+	}
+
+	sort.SliceStable(result.LabelValues, func(i, j int) bool {
+		return result.LabelValues[i].TagKey < result.LabelValues[j].TagKey
+	})
+}
+
+// constraintViolationTypes maps the kinds of provider constraints to violation types
+var constraintViolationTypes = map[providers.ConstraintKind]ViolationType{
+	providers.ConstraintTagLimit:          ViolationTagLimitExceeded,
+	providers.ConstraintKeyLength:         ViolationKeyLengthExceeded,
+	providers.ConstraintValueLength:       ViolationValueLengthExceeded,
+	providers.ConstraintReservedPrefix:    ViolationReservedPrefix,
+	providers.ConstraintInvalidCharacters: ViolationInvalidCharacters,
+}
+
+// validateProviderConstraints checks the tags of a resource against the tag limits and character sets of its
+// cloud provider. Values that can't be resolved are not checked.
+func (v *TagValidator) validateProviderConstraints(resourceType string, tags map[string]string) []TagViolation {
+	constraints := providers.GetTagConstraints(resourceType, v.standard.CloudProvider)
+	if constraints == nil {
+		return nil
+	}
+
+	var violations []providers.ConstraintViolation
+
+	if violation := constraints.CheckCount(len(tags)); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		violations = append(violations, constraints.CheckKey(key)...)
+
+		if value, uncertainty := v.resolveTagValue(tags[key]); uncertainty == "" {
+			violations = append(violations, constraints.CheckValue(key, value)...)
 		}
 	}
 
-	return requiredTags, optionalTags, excludedTags
+	tagViolations := make([]TagViolation, len(violations))
+	for i, violation := range violations {
+		tagViolations[i] = TagViolation{
+			TagKey:        violation.Key,
+			TagValue:      violation.Value,
+			ViolationType: constraintViolationTypes[violation.Kind],
+			Message:       violation.Message,
+		}
+	}
+
+	return tagViolations
+}
+
+// getEffectiveTagRequirements returns the effective tag requirements for a resource type
+func (v *TagValidator) getEffectiveTagRequirements(resourceType string) ([]TagSpec, []TagSpec, []string) {
+	return v.standard.EffectiveTagRequirements(resourceType)
 }
 
 // validateTagValue validates a single tag value against its specification
@@ -485,7 +599,28 @@ func (v *TagValidator) suggestFixForViolation(spec TagSpec, violation TagViolati
 func (v *TagValidator) ValidateBatch(resources []ResourceInfo) []ValidationResult {
 	results := make([]ValidationResult, len(resources))
 	for i, resource := range resources {
-		results[i] = v.ValidateResourceTags(resource.Type, resource.Name, resource.FilePath, resource.Tags)
+		// Suppressed resources aren't validated, the report lists them with their reason
+		if resource.Suppression.SuppressesResource() {
+			results[i] = ValidationResult{
+				ResourceType: resource.Type,
+				ResourceName: resource.Name,
+				FilePath:     resource.FilePath,
+				LineNumber:   resource.LineNumber,
+				IsCompliant:  true,
+				Suppression:  resource.Suppression,
+			}
+			continue
+		}
+
+		// Validate the effective tag set, provider default tags are applied by the provider at plan time
+		tags, sources := MergeEffectiveTags(resource.ProviderTags, resource.Tags)
+		results[i] = v.ValidateResourceTags(resource.Type, resource.Name, resource.FilePath, tags)
+		if len(sources) > 0 {
+			results[i].TagSources = sources
+		}
+		if resource.Suppression != nil {
+			results[i] = withoutIgnoredTags(results[i], resource.Suppression)
+		}
 		// Copy additional resource information
 		results[i].LineNumber = resource.LineNumber
 		results[i].Snippet = v.enhanceSnippetWithResolvedTags(resource.Snippet, resource.Type, resource.Tags)
@@ -493,9 +628,35 @@ func (v *TagValidator) ValidateBatch(resources []ResourceInfo) []ValidationResul
 	return results
 }
 
+// withoutIgnoredTags drops the issues of the tags a suppression ignores from a validation result
+func withoutIgnoredTags(result ValidationResult, suppression *Suppression) ValidationResult {
+	ignored := func(key string) bool {
+		return contains(suppression.IgnoredTags, key)
+	}
+
+	result.Violations = slices.DeleteFunc(result.Violations, func(violation TagViolation) bool {
+		return ignored(violation.TagKey)
+	})
+	result.MissingTags = slices.DeleteFunc(result.MissingTags, ignored)
+	result.ExtraTags = slices.DeleteFunc(result.ExtraTags, ignored)
+	result.SuggestedFixes = slices.DeleteFunc(result.SuggestedFixes, func(fix SuggestedFix) bool {
+		return ignored(fix.TagKey)
+	})
+	result.LabelValues = slices.DeleteFunc(result.LabelValues, func(label LabelValue) bool {
+		return ignored(label.TagKey)
+	})
+
+	result.IsCompliant = len(result.Violations) == 0 && len(result.MissingTags) == 0 && len(result.ExtraTags) == 0
+	result.Suppression = suppression
+
+	return result
+}
+
 
 // CreateValidationReport creates a comprehensive validation report
 func (v *TagValidator) CreateValidationReport(results []ValidationResult, standardFile string) ValidationReport {
+	results, suppressed := partitionSuppressed(results)
+
 	report := ValidationReport{
 		Timestamp:             time.Now(),
 		StandardFile:          standardFile,
@@ -503,6 +664,7 @@ func (v *TagValidator) CreateValidationReport(results []ValidationResult, standa
 		CompliantResources:    0,
 		NonCompliantResources: 0,
 		Results:               results,
+		SuppressedResources:   suppressed,
 	}
 	
 	// Initialize tagging support summary
@@ -585,45 +747,37 @@ func (v *TagValidator) CreateValidationReport(results []ValidationResult, standa
 	return report
 }
 
-// Helper functions
+// partitionSuppressed returns the results of the validated resources, and the resources exempted from
+// validation in full or in part by an annotation. Fully suppressed resources aren't counted in the report.
+func partitionSuppressed(results []ValidationResult) ([]ValidationResult, []SuppressedResource) {
+	validated := make([]ValidationResult, 0, len(results))
+	var suppressed []SuppressedResource
 
-func (v *TagValidator) isGloballyExcluded(resourceType string) bool {
-	for _, excluded := range v.standard.GlobalExcludes {
-		if resourceType == excluded {
-			return true
+	for _, result := range results {
+		if result.Suppression != nil {
+			suppressed = append(suppressed, SuppressedResource{
+				ResourceType: result.ResourceType,
+				ResourceName: result.ResourceName,
+				FilePath:     result.FilePath,
+				LineNumber:   result.LineNumber,
+				Annotation:   result.Suppression.Annotation,
+				IgnoredTags:  result.Suppression.IgnoredTags,
+				Reason:       result.Suppression.Reason,
+			})
 		}
-	}
-	return false
-}
 
-func (v *TagValidator) resourceTypeMatches(resourceType string, patterns []string) bool {
-	for _, pattern := range patterns {
-		if resourceType == pattern {
-			return true
-		}
-		// Support wildcard matching
-		if strings.Contains(pattern, "*") {
-			matched, _ := regexp.MatchString(strings.Replace(pattern, "*", ".*", -1), resourceType)
-			if matched {
-				return true
-			}
+		if !result.Suppression.SuppressesResource() {
+			validated = append(validated, result)
 		}
 	}
-	return false
+
+	return validated, suppressed
 }
 
-func (v *TagValidator) findTagSpec(tagKey string) *TagSpec {
-	for _, tag := range v.standard.RequiredTags {
-		if tag.Key == tagKey {
-			return &tag
-		}
-	}
-	for _, tag := range v.standard.OptionalTags {
-		if tag.Key == tagKey {
-			return &tag
-		}
-	}
-	return nil
+// Helper functions
+
+func (v *TagValidator) isGloballyExcluded(resourceType string) bool {
+	return v.standard.IsGloballyExcluded(resourceType)
 }
 
 func contains(slice []string, item string) bool {
@@ -705,12 +859,15 @@ func extractAzureService(resourceType string) string {
 
 // ResourceInfo holds information about a resource for validation
 type ResourceInfo struct {
-	Type       string
-	Name       string
-	FilePath   string
-	Tags       map[string]string
-	LineNumber int    // Line number where the resource starts (1-based)
-	Snippet    string // Resource definition snippet
+	Type         string
+	Name         string
+	FilePath     string
+	Tags         map[string]string
+	LineNumber   int               // Line number where the resource starts (1-based)
+	Snippet      string            // Resource definition snippet
+	Provider     string            // Provider configuration address, e.g. "aws" or "aws.west"
+	ProviderTags map[string]string // Default tags inherited from the provider configuration
+	Suppression  *Suppression      // Inline annotation exempting the resource or some of its tags, nil if none
 }
 
 // IsTaggableResource checks if a resource type supports tagging based on cloud provider
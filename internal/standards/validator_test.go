@@ -1,6 +1,7 @@
 package standards
 
 import (
+	"fmt"
 	"regexp"
 	"testing"
 
@@ -227,4 +228,177 @@ func TestCreateExampleStandard(t *testing.T) {
 	assert.NotEmpty(t, standard.RequiredTags)
 	assert.NotEmpty(t, standard.OptionalTags)
 	assert.NotEmpty(t, standard.Metadata.Description)
-}
\ No newline at end of file
+}
+func TestMergeEffectiveTags(t *testing.T) {
+	providerTags := map[string]string{"Environment": "Production", "Owner": "platform@example.com"}
+	resourceTags := map[string]string{"Owner": "team@example.com", "Name": "web"}
+
+	tags, sources := MergeEffectiveTags(providerTags, resourceTags)
+
+	assert.Equal(t, map[string]string{
+		"Environment": "Production",
+		"Owner":       "team@example.com",
+		"Name":        "web",
+	}, tags)
+	assert.Equal(t, TagSourceProviderDefault, sources["Environment"])
+	assert.Equal(t, TagSourceResourceOverride, sources["Owner"])
+	assert.Equal(t, TagSourceResource, sources["Name"])
+}
+
+func TestValidateBatch_Suppression(t *testing.T) {
+	standard := &TagStandard{
+		Version:       1,
+		CloudProvider: "aws",
+		RequiredTags: []TagSpec{
+			{Key: "Name", DataType: DataTypeString},
+			{Key: "Owner", DataType: DataTypeString},
+		},
+	}
+
+	validator, err := NewTagValidator(standard)
+	require.NoError(t, err)
+
+	resources := []ResourceInfo{
+		{Type: "aws_instance", Name: "web", FilePath: "main.tf", Tags: map[string]string{"Name": "web"}},
+		{
+			Type: "aws_instance", Name: "legacy", FilePath: "main.tf", LineNumber: 12,
+			Suppression: &Suppression{Annotation: "terratag:skip-validation", Reason: "decommissioned next quarter"},
+		},
+		{
+			Type: "aws_instance", Name: "shared", FilePath: "main.tf", Tags: map[string]string{"Name": "shared"},
+			Suppression: &Suppression{Annotation: "terratag:ignore=Owner", IgnoredTags: []string{"Owner"}},
+		},
+	}
+
+	results := validator.ValidateBatch(resources)
+	require.Len(t, results, 3)
+
+	assert.False(t, results[0].IsCompliant)
+	assert.Equal(t, []string{"Owner"}, results[0].MissingTags)
+
+	assert.True(t, results[2].IsCompliant, "ignored tags must not make the resource non-compliant")
+	assert.Empty(t, results[2].MissingTags)
+	assert.Empty(t, results[2].SuggestedFixes)
+
+	report := validator.CreateValidationReport(results, "standard.yaml")
+
+	assert.Equal(t, 2, report.TotalResources, "fully suppressed resources aren't counted")
+	assert.Equal(t, 1, report.CompliantResources)
+	assert.Len(t, report.Results, 2)
+	assert.Equal(t, []SuppressedResource{
+		{ResourceType: "aws_instance", ResourceName: "legacy", FilePath: "main.tf", LineNumber: 12, Annotation: "terratag:skip-validation", Reason: "decommissioned next quarter"},
+		{ResourceType: "aws_instance", ResourceName: "shared", FilePath: "main.tf", Annotation: "terratag:ignore=Owner", IgnoredTags: []string{"Owner"}},
+	}, report.SuppressedResources)
+}
+
+func TestValidateResourceTags_ProviderConstraints(t *testing.T) {
+	standard := &TagStandard{
+		Version:       1,
+		CloudProvider: "gcp",
+		RequiredTags: []TagSpec{
+			{Key: "Environment", DataType: DataTypeString},
+			{Key: "owner", DataType: DataTypeString},
+		},
+	}
+
+	validator, err := NewTagValidator(standard)
+	require.NoError(t, err)
+
+	result := validator.ValidateResourceTags("google_storage_bucket", "logs", "main.tf", map[string]string{
+		"Environment": "prod",
+		"owner":       "Platform Team",
+	})
+
+	assert.False(t, result.IsCompliant)
+	require.Len(t, result.Violations, 2)
+	assert.Equal(t, ViolationInvalidCharacters, result.Violations[0].ViolationType)
+	assert.Equal(t, "Environment", result.Violations[0].TagKey)
+	assert.Equal(t, ViolationInvalidCharacters, result.Violations[1].ViolationType)
+	assert.Equal(t, "Platform Team", result.Violations[1].TagValue)
+
+	tags := map[string]string{"Name": "web"}
+	for i := 0; i < 50; i++ {
+		tags[fmt.Sprintf("Extra%d", i)] = "value"
+	}
+	tags["aws:createdBy"] = "terraform"
+
+	standard = &TagStandard{Version: 1, CloudProvider: "aws", OptionalTags: []TagSpec{{Key: "Name", DataType: DataTypeString}}}
+	validator, err = NewTagValidator(standard)
+	require.NoError(t, err)
+
+	result = validator.ValidateResourceTags("aws_instance", "web", "main.tf", tags)
+
+	var types []ViolationType
+	for _, violation := range result.Violations {
+		types = append(types, violation.ViolationType)
+	}
+	assert.Equal(t, []ViolationType{ViolationTagLimitExceeded, ViolationReservedPrefix}, types)
+}
+
+func TestValidateResourceTags_LabelValues(t *testing.T) {
+	standard := &TagStandard{
+		Version:             1,
+		CloudProvider:       "gcp",
+		SanitizeLabelValues: true,
+		RequiredTags: []TagSpec{
+			{Key: "owner", DataType: DataTypeString, AllowedValues: []string{"Platform Team", "Data Team"}, CaseSensitive: true},
+			{Key: "service", DataType: DataTypeString},
+		},
+	}
+
+	validator, err := NewTagValidator(standard)
+	require.NoError(t, err)
+
+	result := validator.ValidateResourceTags("google_storage_bucket", "logs", "main.tf", map[string]string{
+		"owner":   "platform_team",
+		"service": "Billing API",
+	})
+
+	assert.Equal(t, []LabelValue{
+		{TagKey: "owner", Original: "Platform Team", Transformed: "platform_team"},
+		{TagKey: "service", Original: "Billing API", Transformed: "billing_api"},
+	}, result.LabelValues)
+
+	require.Len(t, result.Violations, 1, "the label value of an allowed value is valid")
+	assert.Equal(t, ViolationInvalidCharacters, result.Violations[0].ViolationType)
+	assert.Equal(t, []SuggestedFix{{
+		TagKey:         "service",
+		CurrentValue:   "Billing API",
+		SuggestedValue: "billing_api",
+		Action:         ActionUpdate,
+		Reason:         "Value 'Billing API' is not a valid Google label value",
+	}}, result.SuggestedFixes)
+
+	result = validator.ValidateResourceTags("aws_s3_bucket", "logs", "main.tf", map[string]string{
+		"owner":   "platform_team",
+		"service": "Billing API",
+	})
+	assert.Empty(t, result.LabelValues, "only Google label values are sanitized")
+	assert.Equal(t, ViolationInvalidValue, result.Violations[0].ViolationType)
+}
+
+func TestValidateResourceTags_MultiCloud(t *testing.T) {
+	validator, err := NewTagValidator(newMultiCloudStandard())
+	require.NoError(t, err)
+
+	result := validator.ValidateResourceTags("aws_instance", "web", "main.tf", map[string]string{
+		"CostCenter":  "CC-1234",
+		"Environment": "prod",
+	})
+	assert.True(t, result.IsCompliant)
+
+	result = validator.ValidateResourceTags("google_compute_instance", "web", "main.tf", map[string]string{
+		"cost-center": "cc-1234",
+		"environment": "prod",
+	})
+	assert.True(t, result.IsCompliant, "GCP resources are validated against the aliased keys and overridden specs")
+
+	result = validator.ValidateResourceTags("google_compute_instance", "web", "main.tf", map[string]string{
+		"cost-center": "CC-1234",
+	})
+	assert.False(t, result.IsCompliant)
+	assert.Equal(t, []string{"environment"}, result.MissingTags)
+
+	result = validator.ValidateResourceTags("google_project_iam_member", "admin", "main.tf", map[string]string{})
+	assert.True(t, result.IsCompliant, "the global excludes of the provider apply")
+}
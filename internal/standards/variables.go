@@ -0,0 +1,157 @@
+package standards
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// VariablesFileName is the file Terraform variables for required tags without a value are generated into
+	VariablesFileName = "terratag_variables.tf"
+
+	// VariablesExampleFileName is the example variable definitions file generated next to VariablesFileName.
+	// It is not auto-loaded by Terraform, pass it with -var-file once filled in.
+	VariablesExampleFileName = "terratag.example.tfvars"
+)
+
+var variableNameRegex = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// TagVariableName returns the name of the Terraform variable holding the value of a tag, e.g. terratag_cost_center
+func TagVariableName(key string) string {
+	name := variableNameRegex.ReplaceAllString(strings.ToLower(key), "_")
+
+	return "terratag_" + strings.Trim(name, "_")
+}
+
+// GenerateVariablesFile renders a variable block per tag spec, with validation blocks derived from the spec rules
+func GenerateVariablesFile(specs []TagSpec) (string, error) {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+
+	for i, spec := range specs {
+		if i > 0 {
+			body.AppendNewline()
+		}
+
+		name := TagVariableName(spec.Key)
+
+		description := spec.Description
+		if description == "" {
+			description = fmt.Sprintf("Value of the required %s tag", spec.Key)
+		}
+
+		variable := body.AppendNewBlock("variable", []string{name})
+		variable.Body().SetAttributeValue("description", cty.StringVal(description))
+		variable.Body().SetAttributeRaw("type", hclwrite.TokensForIdentifier("string"))
+
+		for _, rule := range variableValidations(spec, "var."+name) {
+			condition, err := parseExpressionTokens(rule.condition)
+			if err != nil {
+				return "", fmt.Errorf("failed to generate validation for tag '%s': %w", spec.Key, err)
+			}
+
+			variable.Body().AppendNewline()
+			validation := variable.Body().AppendNewBlock("validation", nil)
+			validation.Body().SetAttributeRaw("condition", condition)
+			validation.Body().SetAttributeValue("error_message", cty.StringVal(rule.errorMessage))
+		}
+	}
+
+	return "# Generated by terratag for required tags without a default value.\n" +
+		"# Set the values in a .tfvars file, see " + VariablesExampleFileName + ".\n\n" +
+		string(hclwrite.Format(f.Bytes())), nil
+}
+
+// GenerateVariablesExample renders an example variable definitions file with an entry per tag spec
+func GenerateVariablesExample(specs []TagSpec) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Example values for the variables in " + VariablesFileName + ".\n")
+	sb.WriteString("# Copy this file, fill in the values and pass it with -var-file.\n")
+
+	for _, spec := range specs {
+		sb.WriteString("\n")
+
+		if spec.Description != "" {
+			sb.WriteString("# " + spec.Description + "\n")
+		}
+
+		if spec.Format != "" {
+			sb.WriteString("# Format: " + spec.Format + "\n")
+		}
+
+		sb.WriteString(TagVariableName(spec.Key) + " = \"\"\n")
+	}
+
+	return sb.String()
+}
+
+type variableValidation struct {
+	condition    string
+	errorMessage string
+}
+
+// variableValidations translates the rules of a tag spec into Terraform validation conditions
+func variableValidations(spec TagSpec, reference string) []variableValidation {
+	var validations []variableValidation
+
+	if len(spec.AllowedValues) > 0 {
+		values := make([]cty.Value, 0, len(spec.AllowedValues))
+		value := reference
+
+		for _, allowed := range spec.AllowedValues {
+			if !spec.CaseSensitive {
+				allowed = strings.ToLower(allowed)
+			}
+
+			values = append(values, cty.StringVal(allowed))
+		}
+
+		if !spec.CaseSensitive {
+			value = "lower(" + reference + ")"
+		}
+
+		validations = append(validations, variableValidation{
+			condition:    fmt.Sprintf("contains(%s, %s)", hclwrite.TokensForValue(cty.ListVal(values)).Bytes(), value),
+			errorMessage: fmt.Sprintf("The %s tag must be one of: %s.", spec.Key, strings.Join(spec.AllowedValues, ", ")),
+		})
+	}
+
+	if spec.Format != "" {
+		validations = append(validations, variableValidation{
+			condition:    fmt.Sprintf("can(regex(%s, %s))", hclwrite.TokensForValue(cty.StringVal(spec.Format)).Bytes(), reference),
+			errorMessage: fmt.Sprintf("The %s tag must match the format %s.", spec.Key, spec.Format),
+		})
+	}
+
+	if spec.MinLength > 0 {
+		validations = append(validations, variableValidation{
+			condition:    fmt.Sprintf("length(%s) >= %d", reference, spec.MinLength),
+			errorMessage: fmt.Sprintf("The %s tag must be at least %d characters long.", spec.Key, spec.MinLength),
+		})
+	}
+
+	if spec.MaxLength > 0 {
+		validations = append(validations, variableValidation{
+			condition:    fmt.Sprintf("length(%s) <= %d", reference, spec.MaxLength),
+			errorMessage: fmt.Sprintf("The %s tag must be at most %d characters long.", spec.Key, spec.MaxLength),
+		})
+	}
+
+	return validations
+}
+
+// parseExpressionTokens parses an HCL expression into tokens that can be written as an attribute value
+func parseExpressionTokens(expression string) (hclwrite.Tokens, error) {
+	f, diags := hclwrite.ParseConfig([]byte("value = "+expression), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return f.Body().GetAttribute("value").Expr().BuildTokens(nil), nil
+}
@@ -0,0 +1,43 @@
+package standards
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagVariableName(t *testing.T) {
+	assert.Equal(t, "terratag_environment", TagVariableName("Environment"))
+	assert.Equal(t, "terratag_cost_center", TagVariableName("cost-center"))
+	assert.Equal(t, "terratag_team_owner", TagVariableName("team:owner"))
+}
+
+func TestGenerateVariablesFile(t *testing.T) {
+	specs := []TagSpec{
+		{Key: "CostCenter", Description: "Cost center code", Format: `^CC-\d{4}$`},
+		{Key: "Tier", AllowedValues: []string{"Gold", "Silver"}, MinLength: 2, MaxLength: 10},
+	}
+
+	text, err := GenerateVariablesFile(specs)
+	require.NoError(t, err)
+
+	_, diags := hclsyntax.ParseConfig([]byte(text), VariablesFileName, hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+
+	assert.Contains(t, text, `variable "terratag_costcenter"`)
+	assert.Contains(t, text, `description = "Cost center code"`)
+	assert.Contains(t, text, `condition     = can(regex("^CC-\\d{4}$", var.terratag_costcenter))`)
+	assert.Contains(t, text, `variable "terratag_tier"`)
+	assert.Contains(t, text, `contains(["gold", "silver"], lower(var.terratag_tier))`)
+	assert.Contains(t, text, `length(var.terratag_tier) >= 2`)
+	assert.Contains(t, text, `length(var.terratag_tier) <= 10`)
+}
+
+func TestGenerateVariablesExample(t *testing.T) {
+	example := GenerateVariablesExample([]TagSpec{{Key: "CostCenter", Description: "Cost center code"}})
+
+	assert.Contains(t, example, "# Cost center code\nterratag_costcenter = \"\"\n")
+}
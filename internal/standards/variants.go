@@ -0,0 +1,198 @@
+package standards
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/cloudyali/terratag/internal/providers"
+)
+
+// Providers returns the cloud providers the standard targets, the default provider first
+func (s *TagStandard) Providers() []string {
+	var targets []string
+	if s.CloudProvider != "" {
+		targets = append(targets, s.CloudProvider)
+	}
+
+	for _, provider := range s.CloudProviders {
+		if !slices.Contains(targets, provider) {
+			targets = append(targets, provider)
+		}
+	}
+
+	return targets
+}
+
+// DefaultProvider returns the provider of the resource types without a known provider prefix
+func (s *TagStandard) DefaultProvider() string {
+	if targets := s.Providers(); len(targets) > 0 {
+		return targets[0]
+	}
+
+	return ""
+}
+
+// IsMultiCloud returns true if the standard targets several cloud providers, or adapts its tags to its provider
+func (s *TagStandard) IsMultiCloud() bool {
+	return len(s.Providers()) > 1 || len(s.ProviderOverrides) > 0
+}
+
+// ResourceProvider returns the provider whose variant of the standard applies to a resource type. Resource types
+// without a known provider prefix get the default provider, ok is false for the providers the standard doesn't target.
+func (s *TagStandard) ResourceProvider(resourceType string) (string, bool) {
+	provider := providers.ResourceProvider(resourceType)
+	if provider == "" {
+		return s.DefaultProvider(), true
+	}
+
+	return provider, slices.Contains(s.Providers(), provider)
+}
+
+// IsTaggableResource returns true if a resource type of a provider the standard targets supports tagging
+func (s *TagStandard) IsTaggableResource(resourceType string) bool {
+	provider, ok := s.ResourceProvider(resourceType)
+
+	return ok && IsTaggableResource(resourceType, provider)
+}
+
+// ForProvider returns the variant of the standard for one of its providers: a single provider standard with the
+// override tags, global excludes and key aliases of the provider applied. Override tags replace the spec of the
+// standard tag with the same key, aliases then rename the keys of the tags, resource rules and directory overlays.
+func (s *TagStandard) ForProvider(provider string) *TagStandard {
+	variant := *s
+	variant.CloudProvider = provider
+	variant.CloudProviders = nil
+	variant.ProviderOverrides = nil
+
+	override, ok := s.ProviderOverrides[provider]
+	if !ok {
+		return &variant
+	}
+
+	variant.RequiredTags = overrideTagSpecs(s.RequiredTags, override)
+	variant.OptionalTags = overrideTagSpecs(s.OptionalTags, override)
+	variant.GlobalExcludes = append(slices.Clone(s.GlobalExcludes), override.GlobalExcludes...)
+
+	variant.ResourceRules = make([]ResourceRule, len(s.ResourceRules))
+	for i, rule := range s.ResourceRules {
+		rule.RequiredTags = aliasKeys(rule.RequiredTags, override.KeyAliases)
+		rule.OptionalTags = aliasKeys(rule.OptionalTags, override.KeyAliases)
+		rule.ExcludedTags = aliasKeys(rule.ExcludedTags, override.KeyAliases)
+		rule.OverrideTags = overrideTagSpecs(rule.OverrideTags, ProviderOverride{KeyAliases: override.KeyAliases})
+		variant.ResourceRules[i] = rule
+	}
+
+	variant.DirectoryOverlays = make([]DirectoryOverlay, len(s.DirectoryOverlays))
+	for i, overlay := range s.DirectoryOverlays {
+		tags := make(map[string]string, len(overlay.Tags))
+		for key, value := range overlay.Tags {
+			tags[aliasKey(key, override.KeyAliases)] = value
+		}
+
+		variant.DirectoryOverlays[i] = DirectoryOverlay{Path: overlay.Path, Tags: tags}
+	}
+
+	return &variant
+}
+
+// overrideTagSpecs returns a copy of tag specs with the override tags and key aliases of a provider applied
+func overrideTagSpecs(specs []TagSpec, override ProviderOverride) []TagSpec {
+	overridden := make([]TagSpec, len(specs))
+
+	for i, spec := range specs {
+		for _, overrideSpec := range override.OverrideTags {
+			if overrideSpec.Key == spec.Key {
+				spec = overrideSpec
+			}
+		}
+
+		spec.Key = aliasKey(spec.Key, override.KeyAliases)
+		overridden[i] = spec
+	}
+
+	return overridden
+}
+
+func aliasKeys(keys []string, aliases map[string]string) []string {
+	aliased := make([]string, len(keys))
+	for i, key := range keys {
+		aliased[i] = aliasKey(key, aliases)
+	}
+
+	return aliased
+}
+
+func aliasKey(key string, aliases map[string]string) string {
+	if alias, ok := aliases[key]; ok {
+		return alias
+	}
+
+	return key
+}
+
+// validateProviders validates the cloud providers of a standard and the overrides of its providers
+func validateProviders(standard *TagStandard, tagKeys map[string]bool) error {
+	validProviders := map[string]bool{
+		"aws":   true,
+		"gcp":   true,
+		"azure": true,
+	}
+
+	targets := standard.Providers()
+	if len(targets) == 0 {
+		return fmt.Errorf("cloud_provider is required")
+	}
+
+	for _, provider := range targets {
+		if !validProviders[provider] {
+			return fmt.Errorf("unsupported cloud provider: %s", provider)
+		}
+	}
+
+	for _, provider := range slices.Sorted(maps.Keys(standard.ProviderOverrides)) {
+		override := standard.ProviderOverrides[provider]
+		path := "provider_overrides." + provider
+
+		if !slices.Contains(targets, provider) {
+			return fmt.Errorf("%s: %s is not one of the cloud providers of the standard", path, provider)
+		}
+
+		for _, spec := range override.OverrideTags {
+			if !tagKeys[spec.Key] {
+				return fmt.Errorf("%s.override_tags: tag '%s' not defined in global tags", path, spec.Key)
+			}
+		}
+
+		if err := validateTagSpecs(override.OverrideTags, path+".override_tags"); err != nil {
+			return err
+		}
+
+		// Keys of the standard tags by key of the provider, several keys sharing a provider key collide
+		aliased := map[string][]string{}
+		for _, key := range slices.Sorted(maps.Keys(tagKeys)) {
+			alias := aliasKey(key, override.KeyAliases)
+			aliased[alias] = append(aliased[alias], key)
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(override.KeyAliases)) {
+			alias := override.KeyAliases[key]
+
+			if !tagKeys[key] {
+				return fmt.Errorf("%s.key_aliases: tag '%s' not defined in global tags", path, key)
+			}
+
+			if alias == "" {
+				return fmt.Errorf("%s.key_aliases: empty alias for tag '%s'", path, key)
+			}
+
+			for _, other := range aliased[alias] {
+				if other != key {
+					return fmt.Errorf("%s.key_aliases: alias '%s' of tag '%s' is also the key of tag '%s'", path, alias, key, other)
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package standards
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMultiCloudStandard() *TagStandard {
+	return &TagStandard{
+		Version:        1,
+		CloudProvider:  "aws",
+		CloudProviders: []string{"azure", "gcp"},
+		RequiredTags: []TagSpec{
+			{Key: "CostCenter", AllowedValues: []string{"CC-1234", "CC-5678"}},
+			{Key: "Environment", AllowedValues: []string{"prod", "dev"}},
+		},
+		GlobalExcludes: []string{"aws_iam_role"},
+		ResourceRules: []ResourceRule{
+			{ResourceTypes: []string{"google_sql_*"}, ExcludedTags: []string{"Environment"}},
+		},
+		ProviderOverrides: map[string]ProviderOverride{
+			"gcp": {
+				KeyAliases:     map[string]string{"CostCenter": "cost-center", "Environment": "environment"},
+				OverrideTags:   []TagSpec{{Key: "CostCenter", AllowedValues: []string{"cc-1234", "cc-5678"}}},
+				GlobalExcludes: []string{"google_project_iam_*"},
+			},
+		},
+	}
+}
+
+func TestTagStandard_ForProvider(t *testing.T) {
+	standard := newMultiCloudStandard()
+
+	assert.Equal(t, []string{"aws", "azure", "gcp"}, standard.Providers())
+	assert.True(t, standard.IsMultiCloud())
+
+	azure := standard.ForProvider("azure")
+	assert.Equal(t, "azure", azure.CloudProvider)
+	assert.False(t, azure.IsMultiCloud())
+	assert.Equal(t, standard.RequiredTags, azure.RequiredTags)
+
+	gcp := standard.ForProvider("gcp")
+	assert.Equal(t, []TagSpec{
+		{Key: "cost-center", AllowedValues: []string{"cc-1234", "cc-5678"}},
+		{Key: "environment", AllowedValues: []string{"prod", "dev"}},
+	}, gcp.RequiredTags)
+	assert.Equal(t, []string{"aws_iam_role", "google_project_iam_*"}, gcp.GlobalExcludes)
+	assert.Equal(t, []string{"environment"}, gcp.ResourceRules[0].ExcludedTags)
+
+	// The standard itself is left untouched
+	assert.Equal(t, "CostCenter", standard.RequiredTags[0].Key)
+	assert.Equal(t, []string{"Environment"}, standard.ResourceRules[0].ExcludedTags)
+}
+
+func TestTagStandard_ResourceProvider(t *testing.T) {
+	standard := &TagStandard{CloudProvider: "aws", CloudProviders: []string{"gcp"}}
+
+	tests := []struct {
+		resourceType     string
+		expectedProvider string
+		expectedOk       bool
+	}{
+		{resourceType: "aws_instance", expectedProvider: "aws", expectedOk: true},
+		{resourceType: "google_compute_instance", expectedProvider: "gcp", expectedOk: true},
+		{resourceType: "azurerm_resource_group", expectedProvider: "azure", expectedOk: false},
+		{resourceType: "random_id", expectedProvider: "aws", expectedOk: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.resourceType, func(t *testing.T) {
+			provider, ok := standard.ResourceProvider(tt.resourceType)
+			assert.Equal(t, tt.expectedProvider, provider)
+			assert.Equal(t, tt.expectedOk, ok)
+		})
+	}
+
+	assert.False(t, standard.IsTaggableResource("azurerm_resource_group"))
+}
+
+func TestValidateProviders(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(standard *TagStandard)
+		errorContains string
+	}{
+		{
+			name:   "valid multi-cloud standard",
+			modify: func(standard *TagStandard) {},
+		},
+		{
+			name:   "cloud providers without cloud provider",
+			modify: func(standard *TagStandard) { standard.CloudProvider = "" },
+		},
+		{
+			name: "no cloud provider",
+			modify: func(standard *TagStandard) {
+				standard.CloudProvider = ""
+				standard.CloudProviders = nil
+				standard.ProviderOverrides = nil
+			},
+			errorContains: "cloud_provider is required",
+		},
+		{
+			name:          "unsupported cloud provider",
+			modify:        func(standard *TagStandard) { standard.CloudProviders = append(standard.CloudProviders, "oci") },
+			errorContains: "unsupported cloud provider: oci",
+		},
+		{
+			name:          "override of a provider the standard doesn't target",
+			modify:        func(standard *TagStandard) { standard.CloudProviders = []string{"azure"} },
+			errorContains: "provider_overrides.gcp: gcp is not one of the cloud providers of the standard",
+		},
+		{
+			name: "override of an undefined tag",
+			modify: func(standard *TagStandard) {
+				standard.ProviderOverrides["gcp"] = ProviderOverride{OverrideTags: []TagSpec{{Key: "Team"}}}
+			},
+			errorContains: "provider_overrides.gcp.override_tags: tag 'Team' not defined in global tags",
+		},
+		{
+			name: "alias of an undefined tag",
+			modify: func(standard *TagStandard) {
+				standard.ProviderOverrides["gcp"] = ProviderOverride{KeyAliases: map[string]string{"Team": "team"}}
+			},
+			errorContains: "provider_overrides.gcp.key_aliases: tag 'Team' not defined in global tags",
+		},
+		{
+			name: "empty alias",
+			modify: func(standard *TagStandard) {
+				standard.ProviderOverrides["gcp"] = ProviderOverride{KeyAliases: map[string]string{"CostCenter": ""}}
+			},
+			errorContains: "provider_overrides.gcp.key_aliases: empty alias for tag 'CostCenter'",
+		},
+		{
+			name: "alias colliding with another tag",
+			modify: func(standard *TagStandard) {
+				standard.ProviderOverrides["gcp"] = ProviderOverride{KeyAliases: map[string]string{"CostCenter": "Environment"}}
+			},
+			errorContains: "provider_overrides.gcp.key_aliases: alias 'Environment' of tag 'CostCenter' is also the key of tag 'Environment'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standard := newMultiCloudStandard()
+			tt.modify(standard)
+
+			err := ValidateStandard(standard)
+			if tt.errorContains == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
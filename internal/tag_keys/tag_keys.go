@@ -10,8 +10,25 @@ func GetTerratagAddedKey(filname string) string {
 	return "terratag_added_" + filname
 }
 
+// GetTerratagAddedTagSetKey returns the key of the terratag_added_* local of a resource rule specific tag set,
+// or the key of the standard tags local for an empty tag set name
+func GetTerratagAddedTagSetKey(filename string, tagSet string) string {
+	if tagSet == "" {
+		return GetTerratagAddedKey(filename)
+	}
+
+	return GetTerratagAddedKey(filename) + "__" + tagSet
+}
+
 func GetResourceExistingTagsKey(filename string, resource *hclwrite.Block) string {
 	delimiter := "__"
 
 	return "terratag_found_" + filename + delimiter + strings.Join(resource.Labels(), delimiter)
 }
+
+// GetResourceTraceTagsKey returns the key of the terratag_trace_* local holding the traceability tags of a resource
+func GetResourceTraceTagsKey(filename string, resource *hclwrite.Block) string {
+	delimiter := "__"
+
+	return "terratag_trace_" + filename + delimiter + strings.Join(resource.Labels(), delimiter)
+}
@@ -89,12 +89,19 @@ func tagAutoscalingGroup(args TagBlockArgs) (*Result, error) {
 	if tagsAttr != nil {
 		// "tags" interpolation is used
 		tokens := tagsAttr.Expr().BuildTokens(hclwrite.Tokens{})
+		if convert.ReferencesTerratagAddedLocal(tokens, args.Filename) {
+			key := tag_keys.GetTerratagAddedTagSetKey(args.Filename, args.TagSet)
+			args.Block.Body().SetAttributeRaw("tags", convert.RetargetTerratagAddedLocal(tokens, args.Filename, key))
+
+			return &Result{}, nil
+		}
+
 		expression := strings.TrimSpace(string(tokens.Bytes()))
 		// may be wrapped with ${ } in TF11
 		expression = strings.TrimPrefix(expression, "${")
 		expression = strings.TrimSuffix(expression, "${")
 
-		key := "local." + tag_keys.GetTerratagAddedKey(args.Filename)
+		key := "local." + tag_keys.GetTerratagAddedTagSetKey(args.Filename, args.TagSet)
 		newTagsValue := "flatten([" + key + "," + expression + "])"
 
 		newTags := ParseHclValueStringToTokens(newTagsValue)
@@ -102,7 +109,7 @@ func tagAutoscalingGroup(args TagBlockArgs) (*Result, error) {
 		args.Block.Body().SetAttributeRaw("tags", newTags)
 	} else {
 		// no "tags" interpolation is used, but rather multiple instances of a "tag" block
-		if err := convert.AppendTagBlocks(args.Block, args.Tags); err != nil {
+		if err := convert.AppendTagBlocks(args.Block, args.Tags, args.TagExpressions); err != nil {
 			return nil, err
 		}
 	}
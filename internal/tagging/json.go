@@ -0,0 +1,137 @@
+package tagging
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cloudyali/terratag/internal/tag_keys"
+)
+
+// Resources whose tags can't be merged as a map in the JSON syntax
+var jsonTaggingUnsupported = map[string]bool{
+	"aws_autoscaling_group": true, // tags are 'tag' blocks
+}
+
+// IsJSONTaggable returns false for resources terratag can only tag in the native syntax
+func IsJSONTaggable(resourceType string) bool {
+	return !jsonTaggingUnsupported[resourceType]
+}
+
+// TagJSONBlockArgs are the arguments to tag a block of a JSON syntax (.tf.json) configuration
+type TagJSONBlockArgs struct {
+	Filename         string
+	Body             map[string]interface{}
+	Tags             map[string]string
+	TagSet           string // Name of the resource rule specific tag set, empty for the standard tags
+	TagId            string
+	KeepExistingTags bool
+	TraceTags        map[string]string // Traceability tags of the resource, merged after Tags
+	TraceTagsKey     string            // Name of the terratag_trace_* local holding TraceTags
+}
+
+// TagJSONBlock merges the tags into the tag attribute of a JSON syntax block body.
+// Literal tag objects are merged in place. Tag expressions ("${var.tags}") are merged with
+// the terratag_added_* local (and the terratag_trace_* local of traced resources), in which case
+// true is returned and the locals must be added to the file.
+func TagJSONBlock(args TagJSONBlockArgs) (bool, error) {
+	switch existing := args.Body[args.TagId].(type) {
+	case nil:
+		tags := make(map[string]interface{}, len(args.Tags)+len(args.TraceTags))
+		for key, value := range args.Tags {
+			tags[key] = value
+		}
+
+		for key, value := range args.TraceTags {
+			tags[key] = value
+		}
+
+		args.Body[args.TagId] = tags
+	case map[string]interface{}:
+		for _, tags := range []map[string]string{args.Tags, args.TraceTags} {
+			for key, value := range tags {
+				if _, exists := existing[key]; exists && args.KeepExistingTags {
+					continue
+				}
+
+				existing[key] = value
+			}
+		}
+	case string:
+		terratagAddedKey := "local." + tag_keys.GetTerratagAddedTagSetKey(args.Filename, args.TagSet)
+		if args.TraceTagsKey != "" {
+			terratagAddedKey += ", local." + args.TraceTagsKey
+		}
+
+		// Already merged by a previous run, only the local has to be updated
+		if previousKey := jsonTerratagAddedLocalRegex(args.Filename).FindString(existing); previousKey != "" {
+			if args.TraceTagsKey != "" && regexp.MustCompile(`local\.`+regexp.QuoteMeta(args.TraceTagsKey)+`\b`).MatchString(existing) {
+				terratagAddedKey = "local." + tag_keys.GetTerratagAddedTagSetKey(args.Filename, args.TagSet)
+			}
+
+			args.Body[args.TagId] = strings.Replace(existing, previousKey, terratagAddedKey, 1)
+
+			return true, nil
+		}
+
+		expression := strings.TrimSpace(existing)
+		if !strings.HasPrefix(expression, "${") || !strings.HasSuffix(expression, "}") || strings.Count(expression, "${") != 1 {
+			return false, fmt.Errorf("unsupported %s expression %q", args.TagId, existing)
+		}
+
+		expression = strings.TrimSuffix(strings.TrimPrefix(expression, "${"), "}")
+
+		// Flip the order of arguments in merge based on KeepExistingTags flag, same as TagBlock
+		if args.KeepExistingTags {
+			args.Body[args.TagId] = "${merge(" + terratagAddedKey + ", " + expression + ")}"
+		} else {
+			args.Body[args.TagId] = "${merge(" + expression + ", " + terratagAddedKey + ")}"
+		}
+
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported %s value of type %T", args.TagId, existing)
+	}
+
+	return false, nil
+}
+
+// jsonTerratagAddedLocalRegex matches references to the terratag_added_* locals of a file (standard or tag set specific)
+func jsonTerratagAddedLocalRegex(filename string) *regexp.Regexp {
+	return regexp.MustCompile(`local\.` + regexp.QuoteMeta(tag_keys.GetTerratagAddedKey(filename)) + `(__\w+)?\b`)
+}
+
+// TagJSONProviderBlock merges the tags into the default tags of a JSON syntax provider block body
+func TagJSONProviderBlock(providerName string, args TagJSONBlockArgs) (bool, error) {
+	tagId, ok := providerDefaultTagIds[providerName]
+	if !ok {
+		return false, nil
+	}
+
+	args.TagId = tagId
+
+	if providerName == "aws" {
+		args.Body = getJSONNestedBlock(args.Body, "default_tags")
+	}
+
+	return TagJSONBlock(args)
+}
+
+// getJSONNestedBlock returns the body of a nested block, creating it if missing
+func getJSONNestedBlock(body map[string]interface{}, blockType string) map[string]interface{} {
+	switch nested := body[blockType].(type) {
+	case map[string]interface{}:
+		return nested
+	case []interface{}:
+		if len(nested) > 0 {
+			if obj, ok := nested[0].(map[string]interface{}); ok {
+				return obj
+			}
+		}
+	}
+
+	nested := map[string]interface{}{}
+	body[blockType] = nested
+
+	return nested
+}
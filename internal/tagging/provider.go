@@ -0,0 +1,88 @@
+package tagging
+
+import (
+	"strings"
+)
+
+// providerDefaultTagIds maps provider block names to the attribute that holds provider level default tags.
+// AWS nests the map inside a 'default_tags' block, Google exposes a 'default_labels' attribute (provider >= 5.0).
+var providerDefaultTagIds = map[string]string{
+	"aws":         "tags",
+	"google":      "default_labels",
+	"google-beta": "default_labels",
+}
+
+// Resources that don't inherit provider default tags and must still be tagged individually.
+// See https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/autoscaling_group
+var providerDefaultTagsUnsupported = map[string]bool{
+	"aws_autoscaling_group": true,
+}
+
+// HasProviderDefaultTags returns true if the provider block name supports provider level default tags
+func HasProviderDefaultTags(providerName string) bool {
+	_, ok := providerDefaultTagIds[providerName]
+
+	return ok
+}
+
+// GetProviderDefaultTagsAttribute returns the attribute of a provider block that holds its default tags,
+// or an empty string if the provider doesn't support provider level default tags.
+func GetProviderDefaultTagsAttribute(providerName string) string {
+	tagId, ok := providerDefaultTagIds[providerName]
+	if !ok {
+		return ""
+	}
+
+	if providerName == "aws" {
+		return "default_tags." + tagId
+	}
+
+	return tagId
+}
+
+// GetDefaultTagsProviderName returns the provider block name whose default tags apply to the resource type,
+// or an empty string if the resource has to be tagged on the resource itself.
+func GetDefaultTagsProviderName(resourceType string) string {
+	if providerDefaultTagsUnsupported[resourceType] {
+		return ""
+	}
+
+	if strings.HasPrefix(resourceType, "aws_") {
+		return "aws"
+	}
+
+	if strings.HasPrefix(resourceType, "google_") {
+		return "google"
+	}
+
+	return ""
+}
+
+// TagProviderBlock injects the terratag locals into the default tags of a provider block.
+// Existing default tags are merged the same way TagBlock merges existing resource tags.
+func TagProviderBlock(args TagBlockArgs) (*Result, error) {
+	providerName := args.Block.Labels()[0]
+
+	tagId, ok := providerDefaultTagIds[providerName]
+	if !ok {
+		return &Result{}, nil
+	}
+
+	args.TagId = tagId
+
+	if providerName == "aws" {
+		defaultTags := args.Block.Body().FirstMatchingBlock("default_tags", nil)
+		if defaultTags == nil {
+			defaultTags = args.Block.Body().AppendNewBlock("default_tags", nil)
+		}
+
+		args.Block = defaultTags
+	}
+
+	tagBlock, err := TagBlock(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{SwappedTagsStrings: []string{tagBlock}}, nil
+}
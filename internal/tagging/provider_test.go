@@ -0,0 +1,69 @@
+package tagging
+
+import (
+	"testing"
+
+	"github.com/cloudyali/terratag/internal/common"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagProviderBlock(t *testing.T) {
+	testCases := []struct {
+		name          string
+		providerBlock string
+		expectedMerge string
+		expectedHcl   string
+	}{
+		{
+			name:          "AWS provider without default_tags gets a new block",
+			providerBlock: "provider \"aws\" {\n  region = \"us-east-1\"\n}\n",
+			expectedMerge: "local.terratag_added_main",
+			expectedHcl:   "default_tags {\n    tags = local.terratag_added_main\n  }",
+		},
+		{
+			name:          "AWS provider with existing default_tags is merged",
+			providerBlock: "provider \"aws\" {\n  default_tags {\n    tags = { Team = \"core\" }\n  }\n}\n",
+			expectedMerge: "merge( { \"Team\" = \"core\" }, local.terratag_added_main)",
+		},
+		{
+			name:          "Google provider gets default_labels",
+			providerBlock: "provider \"google\" {\n  project = \"p\"\n}\n",
+			expectedMerge: "local.terratag_added_main",
+			expectedHcl:   "default_labels = local.terratag_added_main",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, diags := hclwrite.ParseConfig([]byte(tc.providerBlock), "main.tf", hcl.InitialPos)
+			require.False(t, diags.HasErrors())
+
+			result, err := TagProviderBlock(TagBlockArgs{
+				Filename: "main",
+				Block:    f.Body().Blocks()[0],
+				Tags:     `{"Owner": "DevOps"}`,
+				Terratag: common.TerratagLocal{
+					Found: map[string]hclwrite.Tokens{},
+					Added: `{"Owner"="DevOps"}`,
+				},
+			})
+			require.NoError(t, err)
+			require.Len(t, result.SwappedTagsStrings, 1)
+			assert.Equal(t, tc.expectedMerge, result.SwappedTagsStrings[0])
+
+			if tc.expectedHcl != "" {
+				assert.Contains(t, string(f.Bytes()), tc.expectedHcl)
+			}
+		})
+	}
+}
+
+func TestGetDefaultTagsProviderName(t *testing.T) {
+	assert.Equal(t, "aws", GetDefaultTagsProviderName("aws_s3_bucket"))
+	assert.Equal(t, "google", GetDefaultTagsProviderName("google_storage_bucket"))
+	assert.Equal(t, "", GetDefaultTagsProviderName("aws_autoscaling_group"))
+	assert.Equal(t, "", GetDefaultTagsProviderName("azurerm_resource_group"))
+}
@@ -33,13 +33,44 @@ func ParseHclValueStringToTokens(hclValueString string) hclwrite.Tokens {
 }
 
 func TagBlock(args TagBlockArgs) (string, error) {
+	terratagAddedKey := tag_keys.GetTerratagAddedTagSetKey(args.Filename, args.TagSet)
+
+	// Tags already merged by a previous run only need the terratag_added_* local to be updated
+	if tagsAttribute := args.Block.Body().GetAttribute(args.TagId); tagsAttribute != nil {
+		existingTags := tagsAttribute.Expr().BuildTokens(hclwrite.Tokens{})
+		if convert.ReferencesTerratagAddedLocal(existingTags, args.Filename) {
+			log.Print("Tags ", args.TagId, " already merged with terratag locals, leaving untouched.")
+
+			// The resource may have moved to another tag set since the previous run
+			retargetedTags := convert.RetargetTerratagAddedLocal(existingTags, args.Filename, terratagAddedKey)
+
+			// Trace tags may have been enabled since the previous run
+			if args.TraceTagsKey != "" && !convert.ReferencesLocal(retargetedTags, args.TraceTagsKey) {
+				retargetedTags = ParseHclValueStringToTokens("merge(" + convert.GetExistingTagsExpression(retargetedTags) + ", local." + args.TraceTagsKey + ")")
+			}
+
+			args.Block.Body().SetAttributeRaw(args.TagId, retargetedTags)
+
+			return convert.GetExistingTagsExpression(retargetedTags), nil
+		}
+	}
+
 	hasExistingTags, err := convert.MoveExistingTags(args.Filename, args.Terratag, args.Block, args.TagId)
 	if err != nil {
 		return "", err
 	}
 
-	terratagAddedKey := "local." + tag_keys.GetTerratagAddedKey(args.Filename)
-	newTagsValue := terratagAddedKey
+	addedTags := "local." + terratagAddedKey
+
+	// Trace tags are specific to the resource, they take precedence over the added tags
+	if args.TraceTagsKey != "" {
+		addedTags += ", local." + args.TraceTagsKey
+	}
+
+	newTagsValue := "local." + terratagAddedKey
+	if args.TraceTagsKey != "" {
+		newTagsValue = "merge( " + addedTags + ")"
+	}
 
 	if hasExistingTags {
 		existingTagsKey := tag_keys.GetResourceExistingTagsKey(args.Filename, args.Block)
@@ -48,10 +79,10 @@ func TagBlock(args TagBlockArgs) (string, error) {
 		// Flip the order of arguments in merge based on KeepExistingTags flag
 		if args.KeepExistingTags {
 			// Existing tags take precedence (come second in merge arguments)
-			newTagsValue = "merge( " + terratagAddedKey + ", " + existingTagsExpression + ")"
+			newTagsValue = "merge( " + addedTags + ", " + existingTagsExpression + ")"
 		} else {
 			// New tags take precedence (come second in merge arguments)
-			newTagsValue = "merge( " + existingTagsExpression + ", " + terratagAddedKey + ")"
+			newTagsValue = "merge( " + existingTagsExpression + ", " + addedTags + ")"
 		}
 	}
 
@@ -61,6 +92,16 @@ func TagBlock(args TagBlockArgs) (string, error) {
 	return newTagsValue, nil
 }
 
+// Resources whose tags can't be merged with a terratag_trace_* local
+var traceTagsUnsupported = map[string]bool{
+	"aws_autoscaling_group": true, // tags are 'tag' blocks or a list of tag objects
+}
+
+// SupportsTraceTags returns false for resources terratag can't add traceability tags to
+func SupportsTraceTags(resourceType string) bool {
+	return !traceTagsUnsupported[resourceType]
+}
+
 func HasResourceTagFn(resourceType string) bool {
 	return resourceTypeToFnMap[resourceType] != nil
 }
@@ -88,9 +129,12 @@ type TagBlockArgs struct {
 	Filename         string
 	Block            *hclwrite.Block
 	Tags             string
+	TagExpressions   map[string]bool // Keys of Tags whose values are raw HCL expressions
+	TagSet           string          // Name of the resource rule specific tag set, empty for the standard tags
 	Terratag         common.TerratagLocal
 	TagId            string
 	KeepExistingTags bool
+	TraceTagsKey     string // Name of the terratag_trace_* local merged after the added tags, empty when trace tags are disabled
 }
 
 type TagResourceFn func(args TagBlockArgs) (*Result, error)
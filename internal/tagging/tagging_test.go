@@ -1,6 +1,7 @@
 package tagging
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/cloudyali/terratag/internal/common"
@@ -69,3 +70,179 @@ func TestTagBlock_MergeOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestTagBlock_Idempotent(t *testing.T) {
+	f := hclwrite.NewEmptyFile()
+	resourceBlock := f.Body().AppendNewBlock("resource", []string{"aws_s3_bucket", "test"})
+	resourceBlock.Body().SetAttributeRaw("tags", ParseHclValueStringToTokens(`{ Name = "Original Name" }`))
+
+	newArgs := func() TagBlockArgs {
+		return TagBlockArgs{
+			Filename: "main",
+			Block:    resourceBlock,
+			Tags:     `{"Owner": "DevOps"}`,
+			Terratag: common.TerratagLocal{
+				Found: map[string]hclwrite.Tokens{},
+				Added: `{"Owner"="DevOps"}`,
+			},
+			TagId: "tags",
+		}
+	}
+
+	first, err := TagBlock(newArgs())
+	assert.NoError(t, err)
+
+	second, err := TagBlock(newArgs())
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second, "Re-tagging should leave the merged expression untouched")
+	assert.Equal(t, 1, strings.Count(string(f.Bytes()), "merge("), "Re-tagging should not nest merge calls")
+}
+
+func TestTagBlock_TraceTags(t *testing.T) {
+	const traceKey = "terratag_trace_main__aws_s3_bucket__test"
+
+	f := hclwrite.NewEmptyFile()
+	resourceBlock := f.Body().AppendNewBlock("resource", []string{"aws_s3_bucket", "test"})
+	untracedBlock := f.Body().AppendNewBlock("resource", []string{"aws_s3_bucket", "untraced"})
+
+	newArgs := func(block *hclwrite.Block, traceTagsKey string) TagBlockArgs {
+		return TagBlockArgs{
+			Filename: "main",
+			Block:    block,
+			Tags:     `{"Owner": "DevOps"}`,
+			Terratag: common.TerratagLocal{
+				Found: map[string]hclwrite.Tokens{},
+				Added: `{"Owner"="DevOps"}`,
+			},
+			TagId:        "tags",
+			TraceTagsKey: traceTagsKey,
+		}
+	}
+
+	result, err := TagBlock(newArgs(resourceBlock, traceKey))
+	assert.NoError(t, err)
+	assert.Equal(t, "merge( local.terratag_added_main, local."+traceKey+")", result)
+
+	// Re-tagging keeps a single reference to the trace local
+	_, err = TagBlock(newArgs(resourceBlock, traceKey))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(f.Bytes()), "local."+traceKey))
+
+	// Trace tags enabled after a previous run are merged last
+	_, err = TagBlock(newArgs(untracedBlock, ""))
+	assert.NoError(t, err)
+
+	result, err = TagBlock(newArgs(untracedBlock, "terratag_trace_main__aws_s3_bucket__untraced"))
+	assert.NoError(t, err)
+	assert.Equal(t, "merge(local.terratag_added_main, local.terratag_trace_main__aws_s3_bucket__untraced)", result)
+}
+
+func TestTagJSONBlock(t *testing.T) {
+	tags := map[string]string{"Name": "Terratag Name", "Owner": "DevOps"}
+
+	testCases := []struct {
+		name             string
+		existing         interface{}
+		keepExistingTags bool
+		expected         interface{}
+		expectedLocal    bool
+	}{
+		{
+			name:     "missing tags",
+			expected: map[string]interface{}{"Name": "Terratag Name", "Owner": "DevOps"},
+		},
+		{
+			name:     "literal tags are merged in place",
+			existing: map[string]interface{}{"Name": "Original Name", "Environment": "Dev"},
+			expected: map[string]interface{}{"Name": "Terratag Name", "Owner": "DevOps", "Environment": "Dev"},
+		},
+		{
+			name:             "literal tags keep existing values",
+			existing:         map[string]interface{}{"Name": "Original Name"},
+			keepExistingTags: true,
+			expected:         map[string]interface{}{"Name": "Original Name", "Owner": "DevOps"},
+		},
+		{
+			name:          "expression is merged with the terratag local",
+			existing:      "${var.tags}",
+			expected:      "${merge(var.tags, local.terratag_added_main)}",
+			expectedLocal: true,
+		},
+		{
+			name:          "expression already merged is left untouched",
+			existing:      "${merge(var.tags, local.terratag_added_main)}",
+			expected:      "${merge(var.tags, local.terratag_added_main)}",
+			expectedLocal: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body := map[string]interface{}{}
+			if tc.existing != nil {
+				body["tags"] = tc.existing
+			}
+
+			usesLocal, err := TagJSONBlock(TagJSONBlockArgs{
+				Filename:         "main",
+				Body:             body,
+				Tags:             tags,
+				TagId:            "tags",
+				KeepExistingTags: tc.keepExistingTags,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedLocal, usesLocal)
+			assert.Equal(t, tc.expected, body["tags"])
+		})
+	}
+}
+
+func TestTagJSONBlock_TraceTags(t *testing.T) {
+	const traceKey = "terratag_trace_main__aws_s3_bucket__test"
+
+	newArgs := func(body map[string]interface{}) TagJSONBlockArgs {
+		return TagJSONBlockArgs{
+			Filename:     "main",
+			Body:         body,
+			Tags:         map[string]string{"Owner": "DevOps"},
+			TagId:        "tags",
+			TraceTags:    map[string]string{"terratag_resource_address": "aws_s3_bucket.test"},
+			TraceTagsKey: traceKey,
+		}
+	}
+
+	literal := map[string]interface{}{"tags": map[string]interface{}{"Name": "logs"}}
+	usesLocal, err := TagJSONBlock(newArgs(literal))
+	assert.NoError(t, err)
+	assert.False(t, usesLocal)
+	assert.Equal(t, map[string]interface{}{"Name": "logs", "Owner": "DevOps", "terratag_resource_address": "aws_s3_bucket.test"}, literal["tags"])
+
+	expression := map[string]interface{}{"tags": "${var.tags}"}
+	usesLocal, err = TagJSONBlock(newArgs(expression))
+	assert.NoError(t, err)
+	assert.True(t, usesLocal)
+	assert.Equal(t, "${merge(var.tags, local.terratag_added_main, local."+traceKey+")}", expression["tags"])
+
+	_, err = TagJSONBlock(newArgs(expression))
+	assert.NoError(t, err)
+	assert.Equal(t, "${merge(var.tags, local.terratag_added_main, local."+traceKey+")}", expression["tags"])
+
+	// Trace tags enabled after a previous run
+	previous := map[string]interface{}{"tags": "${merge(var.tags, local.terratag_added_main)}"}
+	_, err = TagJSONBlock(newArgs(previous))
+	assert.NoError(t, err)
+	assert.Equal(t, "${merge(var.tags, local.terratag_added_main, local."+traceKey+")}", previous["tags"])
+}
+
+func TestTagJSONProviderBlock(t *testing.T) {
+	body := map[string]interface{}{"region": "us-east-1"}
+
+	_, err := TagJSONProviderBlock("aws", TagJSONBlockArgs{
+		Filename: "main",
+		Body:     body,
+		Tags:     map[string]string{"Owner": "DevOps"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"tags": map[string]interface{}{"Owner": "DevOps"}}, body["default_tags"])
+}
@@ -1,6 +1,7 @@
 package terraform
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -103,7 +104,7 @@ inputs = {
 			im := NewInitManager(tempDir, tt.iacType, tt.defaultToTerraform, tt.useCache)
 
 			// Test GetInitStatus
-			initialized, err := im.GetInitStatus()
+			initialized, err := im.GetInitStatus(context.Background())
 			if err != nil {
 				t.Logf("GetInitStatus returned error (expected for uninitialized): %v", err)
 			}
@@ -114,7 +115,7 @@ inputs = {
 			// Test EnsureInitialized (this would actually run terraform init)
 			// For this test, we'll just verify the method can be called without panicking
 			t.Logf("Testing EnsureInitialized for %s (may fail due to missing terraform binary)", tt.name)
-			err = im.EnsureInitialized()
+			err = im.EnsureInitialized(context.Background())
 			if err != nil {
 				t.Logf("EnsureInitialized failed (expected without terraform binary): %v", err)
 			}
@@ -179,7 +180,7 @@ resource "random_id" "example" {
 	}
 
 	// Test public API functions
-	initialized, err := GetInitStatus(tempDir, common.Terraform, false, true)
+	initialized, err := GetInitStatus(context.Background(), tempDir, common.Terraform, false, true, common.CommandTimeouts{})
 	if err != nil {
 		t.Logf("GetInitStatus returned error (expected for uninitialized): %v", err)
 	}
@@ -188,7 +189,7 @@ resource "random_id" "example" {
 	}
 
 	// Test EnsureInitialized
-	err = EnsureInitialized(tempDir, common.Terraform, false, true)
+	err = EnsureInitialized(context.Background(), tempDir, common.Terraform, false, true, common.CommandTimeouts{})
 	if err != nil {
 		t.Logf("EnsureInitialized failed (expected without terraform binary or network): %v", err)
 	}
@@ -230,7 +231,7 @@ func TestInitManagerErrorHandling(t *testing.T) {
 	// Test with invalid directory
 	im := NewInitManager("/nonexistent/directory", common.Terraform, false, false)
 	
-	initialized, err := im.GetInitStatus()
+	initialized, err := im.GetInitStatus(context.Background())
 	if err != nil {
 		t.Logf("GetInitStatus with invalid directory returned error (expected): %v", err)
 	}
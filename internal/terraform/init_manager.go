@@ -1,6 +1,7 @@
 package terraform
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/cloudyali/terratag/internal/common"
 	"github.com/cloudyali/terratag/internal/providers"
+	"github.com/cloudyali/terratag/internal/utils"
 )
 
 // InitManager handles intelligent terraform initialization with error detection and retry logic
@@ -18,6 +20,7 @@ type InitManager struct {
 	iacType           common.IACType
 	defaultToTerraform bool
 	useCache          bool
+	timeouts          common.CommandTimeouts
 	logger            *log.Logger
 }
 
@@ -54,9 +57,14 @@ func NewInitManager(workingDir string, iacType common.IACType, defaultToTerrafor
 	}
 }
 
+// SetTimeouts limits the duration of the validate and init commands run by the manager
+func (im *InitManager) SetTimeouts(timeouts common.CommandTimeouts) {
+	im.timeouts = timeouts
+}
+
 // EnsureInitialized ensures that terraform is properly initialized in the working directory
 // This implements the improved algorithm with automatic init on failure
-func (im *InitManager) EnsureInitialized() error {
+func (im *InitManager) EnsureInitialized(ctx context.Context) error {
 	// Step 1: Check if already initialized
 	if im.isAlreadyInitialized() {
 		im.logger.Printf("Directory %s is already initialized", im.workingDir)
@@ -64,12 +72,17 @@ func (im *InitManager) EnsureInitialized() error {
 	}
 
 	// Step 2: Try to run a simple terraform validate to detect init needs
-	err := im.tryValidateCommand()
+	err := im.tryValidateCommand(ctx)
 	if err == nil {
 		im.logger.Printf("Terraform validate succeeded, initialization appears complete")
 		return nil
 	}
 
+	// Cancellations and timeouts are not init errors
+	if ctx.Err() != nil {
+		return err
+	}
+
 	// Step 3: Detect if this is an init-related error
 	initErr := im.detectInitError(err)
 	if initErr == nil {
@@ -80,12 +93,12 @@ func (im *InitManager) EnsureInitialized() error {
 	im.logger.Printf("Detected init error: %s", initErr.Message)
 
 	// Step 4: Run initialization
-	if err := im.runSmartInit(initErr.Type); err != nil {
+	if err := im.runSmartInit(ctx, initErr.Type); err != nil {
 		return fmt.Errorf("failed to initialize terraform: %w", err)
 	}
 
 	// Step 5: Verify initialization succeeded
-	if err := im.tryValidateCommand(); err != nil {
+	if err := im.tryValidateCommand(ctx); err != nil {
 		return fmt.Errorf("terraform still failing after init: %w", err)
 	}
 
@@ -114,20 +127,25 @@ func (im *InitManager) isAlreadyInitialized() bool {
 }
 
 // tryValidateCommand runs a simple terraform validate to detect initialization status
-func (im *InitManager) tryValidateCommand() error {
+func (im *InitManager) tryValidateCommand(ctx context.Context) error {
 	cmdName := im.getCommandName()
 	
-	var cmd *exec.Cmd
+	var cmd *utils.Command
 	if im.iacType == common.TerragruntRunAll {
-		cmd = exec.Command(cmdName, "run-all", "validate", "-no-color")
+		cmd = utils.NewCommand(ctx, im.timeouts.Validate, cmdName, "run-all", "validate", "-no-color")
 	} else {
-		cmd = exec.Command(cmdName, "validate", "-no-color")
+		cmd = utils.NewCommand(ctx, im.timeouts.Validate, cmdName, "validate", "-no-color")
 	}
 	
 	cmd.Dir = im.workingDir
 	
 	output, err := cmd.CombinedOutput()
 	if err != nil {
+		// Not started, or killed by a cancellation or timeout
+		if ctx.Err() != nil || cmd.ProcessState == nil || !cmd.ProcessState.Exited() {
+			return err
+		}
+
 		return fmt.Errorf("validate command failed: %s", string(output))
 	}
 	
@@ -224,21 +242,27 @@ func (im *InitManager) detectInitError(err error) *InitError {
 }
 
 // runSmartInit runs terraform init with appropriate flags based on error type and cache settings
-func (im *InitManager) runSmartInit(errorType InitErrorType) error {
+func (im *InitManager) runSmartInit(ctx context.Context, errorType InitErrorType) error {
 	// If cache is enabled, try to use cached initialization first
 	if im.useCache {
-		if err := im.trySmartCacheInit(); err == nil {
+		err := im.trySmartCacheInit(ctx)
+		if err == nil {
 			return nil
 		}
+
+		if ctx.Err() != nil {
+			return err
+		}
+
 		im.logger.Printf("Cache-based init failed, falling back to local init")
 	}
 
 	// Fall back to local initialization
-	return im.runLocalInit(errorType)
+	return im.runLocalInit(ctx, errorType)
 }
 
 // trySmartCacheInit attempts to initialize using the provider cache system
-func (im *InitManager) trySmartCacheInit() error {
+func (im *InitManager) trySmartCacheInit(ctx context.Context) error {
 	cacheManager := providers.GetGlobalCacheManager()
 	
 	// Get or create shared terraform directory
@@ -249,7 +273,7 @@ func (im *InitManager) trySmartCacheInit() error {
 
 	// Initialize providers in shared directory if needed
 	if !im.isDirectoryInitialized(sharedTerraformDir) {
-		if err := cacheManager.InitProviders(sharedTerraformDir, im.iacType, im.defaultToTerraform); err != nil {
+		if err := cacheManager.InitProviders(ctx, sharedTerraformDir, im.iacType, im.defaultToTerraform, im.timeouts.Init); err != nil {
 			return fmt.Errorf("failed to initialize shared providers: %w", err)
 		}
 	}
@@ -286,7 +310,7 @@ func (im *InitManager) trySmartCacheInit() error {
 }
 
 // runLocalInit runs terraform init locally with appropriate flags
-func (im *InitManager) runLocalInit(errorType InitErrorType) error {
+func (im *InitManager) runLocalInit(ctx context.Context, errorType InitErrorType) error {
 	cmdName := im.getCommandName()
 	args := []string{"init", "-input=false", "-no-color"}
 
@@ -308,7 +332,7 @@ func (im *InitManager) runLocalInit(errorType InitErrorType) error {
 
 	im.logger.Printf("Running: %s %s", cmdName, strings.Join(args, " "))
 
-	cmd := exec.Command(cmdName, args...)
+	cmd := utils.NewCommand(ctx, im.timeouts.Init, cmdName, args...)
 	cmd.Dir = im.workingDir
 
 	// Capture both stdout and stderr
@@ -381,10 +405,10 @@ func (im *InitManager) copyFile(src, dst string) error {
 }
 
 // GetInitStatus returns the current initialization status
-func (im *InitManager) GetInitStatus() (bool, error) {
+func (im *InitManager) GetInitStatus(ctx context.Context) (bool, error) {
 	if im.isAlreadyInitialized() {
 		// Quick check passed, but verify with validate command
-		if err := im.tryValidateCommand(); err != nil {
+		if err := im.tryValidateCommand(ctx); err != nil {
 			// Initialized but has issues
 			return false, fmt.Errorf("initialized but validation failed: %w", err)
 		}
@@ -395,7 +419,7 @@ func (im *InitManager) GetInitStatus() (bool, error) {
 }
 
 // ForceReinit forces a complete reinitialization
-func (im *InitManager) ForceReinit() error {
+func (im *InitManager) ForceReinit(ctx context.Context) error {
 	im.logger.Printf("Forcing reinitialization of %s", im.workingDir)
 
 	// Remove existing initialization
@@ -415,5 +439,5 @@ func (im *InitManager) ForceReinit() error {
 	}
 
 	// Run initialization
-	return im.runLocalInit(InitErrorGeneric)
+	return im.runLocalInit(ctx, InitErrorGeneric)
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package terraform
 
 import (
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
@@ -499,7 +500,7 @@ resource "aws_instance" "example" {
 	im := NewInitManager(tempDir, common.Terraform, false, false)
 
 	// Check initial status
-	initialized, err := im.GetInitStatus()
+	initialized, err := im.GetInitStatus(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get init status: %v", err)
 	}
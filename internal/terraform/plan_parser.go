@@ -90,13 +90,14 @@ type ConfigVariable struct {
 
 // ResolvedResourceInfo contains resolved information about a resource
 type ResolvedResourceInfo struct {
-	Type         string
-	Name         string
-	Address      string
-	Tags         map[string]string
-	OriginalTags map[string]interface{} // Raw tag expressions from config
-	FilePath     string // We'll derive this from address or set it manually
-	LineNumber   int    // Not available from plan, will be 0
+	Type          string
+	Name          string
+	Address       string
+	Tags          map[string]string
+	EffectiveTags map[string]string      // Tags including provider defaults (tags_all / terraform_labels)
+	OriginalTags  map[string]interface{} // Raw tag expressions from config
+	FilePath      string                 // We'll derive this from address or set it manually
+	LineNumber    int                    // Not available from plan, will be 0
 }
 
 // NewPlanParser creates a new Terraform plan parser
@@ -151,16 +152,21 @@ func (p *PlanParser) ExtractResolvedResources(plan *TerraformPlan) []ResolvedRes
 
 		// Extract tag values from the planned resource or change details
 		tags := extractTagsFromPlanData(change, plannedResources)
-		if tags == nil {
+		effectiveTags := extractEffectiveTagsFromPlanData(change, plannedResources)
+		if tags == nil && effectiveTags == nil {
 			continue // Skip resources without tags
 		}
+		if tags == nil {
+			tags = make(map[string]string)
+		}
 
 		resource := ResolvedResourceInfo{
-			Type:     change.Type,
-			Name:     change.Name,
-			Address:  change.Address,
-			Tags:     tags,
-			FilePath: deriveFilePathFromAddress(change.Address),
+			Type:          change.Type,
+			Name:          change.Name,
+			Address:       change.Address,
+			Tags:          tags,
+			EffectiveTags: effectiveTags,
+			FilePath:      deriveFilePathFromAddress(change.Address),
 		}
 
 		resources = append(resources, resource)
@@ -206,10 +212,36 @@ func extractTagsFromPlanData(change ResourceChange, plannedResources map[string]
 	return tags
 }
 
+// extractEffectiveTagsFromPlanData extracts the tags including provider defaults.
+// AWS exposes them as 'tags_all', Google as 'terraform_labels'.
+func extractEffectiveTagsFromPlanData(change ResourceChange, plannedResources map[string]PlannedResource) map[string]string {
+	var attrName string
+	switch {
+	case strings.HasPrefix(change.Type, "aws_"):
+		attrName = "tags_all"
+	case strings.HasPrefix(change.Type, "google_"):
+		attrName = "terraform_labels"
+	default:
+		return nil
+	}
+
+	values := change.Change.After
+	if _, exists := values[attrName]; !exists {
+		if planned, exists := plannedResources[change.Address]; exists {
+			values = planned.Values
+		}
+	}
+
+	tags := extractMapFromValues(values, attrName)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	return tags
+}
+
 // extractTagsFromValues extracts tags from resource values based on provider conventions
 func extractTagsFromValues(values map[string]interface{}, resourceType string) map[string]string {
-	tags := make(map[string]string)
-
 	// Determine tag attribute name based on resource type/provider
 	var tagAttrName string
 	switch {
@@ -223,8 +255,15 @@ func extractTagsFromValues(values map[string]interface{}, resourceType string) m
 		tagAttrName = "tags"
 	}
 
+	return extractMapFromValues(values, tagAttrName)
+}
+
+// extractMapFromValues converts a map attribute of the resource values to a string map
+func extractMapFromValues(values map[string]interface{}, attrName string) map[string]string {
+	tags := make(map[string]string)
+
 	// Extract tags from the appropriate attribute
-	if tagValues, exists := values[tagAttrName]; exists {
+	if tagValues, exists := values[attrName]; exists {
 		if tagMap, ok := tagValues.(map[string]interface{}); ok {
 			for key, value := range tagMap {
 				if strValue, ok := value.(string); ok {
@@ -1,6 +1,7 @@
 package terraform
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -50,15 +51,17 @@ func ValidateInitRun(dir string, iacType string) error {
 }
 
 // EnsureInitialized ensures terraform is properly initialized, running init if necessary
-func EnsureInitialized(dir string, iacType common.IACType, defaultToTerraform bool, useCache bool) error {
+func EnsureInitialized(ctx context.Context, dir string, iacType common.IACType, defaultToTerraform bool, useCache bool, timeouts common.CommandTimeouts) error {
 	initManager := NewInitManager(dir, iacType, defaultToTerraform, useCache)
-	return initManager.EnsureInitialized()
+	initManager.SetTimeouts(timeouts)
+	return initManager.EnsureInitialized(ctx)
 }
 
 // GetInitStatus returns the current initialization status for a directory
-func GetInitStatus(dir string, iacType common.IACType, defaultToTerraform bool, useCache bool) (bool, error) {
+func GetInitStatus(ctx context.Context, dir string, iacType common.IACType, defaultToTerraform bool, useCache bool, timeouts common.CommandTimeouts) (bool, error) {
 	initManager := NewInitManager(dir, iacType, defaultToTerraform, useCache)
-	return initManager.GetInitStatus()
+	initManager.SetTimeouts(timeouts)
+	return initManager.GetInitStatus(ctx)
 }
 
 func GetFilePaths(dir string, iacType string) ([]string, error) {
@@ -79,7 +82,7 @@ func getTerragruntFilePath(rootDir string) ([]string, error) {
 			return filepath.SkipDir
 		}
 
-		if strings.HasSuffix(path, ".tf") {
+		if strings.HasSuffix(path, ".tf") || strings.HasSuffix(path, ".tf.json") {
 			tfFiles = append(tfFiles, path)
 		}
 
@@ -92,9 +95,7 @@ func getTerragruntFilePath(rootDir string) ([]string, error) {
 }
 
 func getTerraformFilePaths(rootDir string) ([]string, error) {
-	const tfFileMatcher = "/*.tf"
-
-	tfFiles, err := doublestar.Glob(rootDir + tfFileMatcher)
+	tfFiles, err := globTerraformFiles(rootDir)
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +106,7 @@ func getTerraformFilePaths(rootDir string) ([]string, error) {
 	}
 
 	for _, moduleDir := range modulesDirs {
-		matches, err := doublestar.Glob(moduleDir + tfFileMatcher)
+		matches, err := globTerraformFiles(moduleDir)
 		if err != nil {
 			return nil, err
 		}
@@ -125,8 +126,50 @@ func getTerraformFilePaths(rootDir string) ([]string, error) {
 	return funk.UniqString(tfFiles), nil
 }
 
+// globTerraformFiles returns the native (.tf) and JSON syntax (.tf.json) configuration files of a directory
+func globTerraformFiles(dir string) ([]string, error) {
+	var tfFiles []string
+
+	for _, tfFileMatcher := range []string{"/*.tf", "/*.tf.json"} {
+		matches, err := doublestar.Glob(dir + tfFileMatcher)
+		if err != nil {
+			return nil, err
+		}
+
+		tfFiles = append(tfFiles, matches...)
+	}
+
+	return tfFiles, nil
+}
+
 func getTerraformModulesDirPaths(dir string) ([]string, error) {
 	paths := []string{}
+
+	modulesJson, err := readModulesJson(dir)
+	if err != nil || modulesJson == nil {
+		return paths, err
+	}
+
+	for _, module := range modulesJson.Modules {
+		modulePath, err := filepath.EvalSymlinks(dir + "/" + module.Dir)
+		if os.IsNotExist(err) {
+			log.Print("[WARN] Module not found, skipping.", dir+"/"+module.Dir)
+
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, modulePath)
+	}
+
+	return paths, nil
+}
+
+// readModulesJson reads the modules installed by terraform init, or returns nil if init installed none
+func readModulesJson(dir string) (*ModulesJson, error) {
 	modulesJson := ModulesJson{}
 
 	jsonFile, err := os.Open(dir + "/.terraform/modules/modules.json")
@@ -135,7 +178,7 @@ func getTerraformModulesDirPaths(dir string) ([]string, error) {
 	defer jsonFile.Close()
 
 	if os.IsNotExist(err) {
-		return paths, nil
+		return nil, nil
 	}
 
 	byteValue, err := io.ReadAll(jsonFile)
@@ -147,11 +190,23 @@ func getTerraformModulesDirPaths(dir string) ([]string, error) {
 		return nil, err
 	}
 
+	return &modulesJson, nil
+}
+
+// GetModuleAddresses returns the addresses of the modules instantiating the configuration of each module
+// directory, e.g. module.network.module.subnets, keyed by absolute directory with symlinks resolved.
+// The root module is "", directories of local modules called several times have several addresses.
+func GetModuleAddresses(dir string) (map[string][]string, error) {
+	addresses := map[string][]string{}
+
+	modulesJson, err := readModulesJson(dir)
+	if err != nil || modulesJson == nil {
+		return addresses, err
+	}
+
 	for _, module := range modulesJson.Modules {
-		modulePath, err := filepath.EvalSymlinks(dir + "/" + module.Dir)
+		modulePath, err := filepath.EvalSymlinks(filepath.Join(dir, module.Dir))
 		if os.IsNotExist(err) {
-			log.Print("[WARN] Module not found, skipping.", dir+"/"+module.Dir)
-
 			continue
 		}
 
@@ -159,10 +214,20 @@ func getTerraformModulesDirPaths(dir string) ([]string, error) {
 			return nil, err
 		}
 
-		paths = append(paths, modulePath)
+		modulePath, err = filepath.Abs(modulePath)
+		if err != nil {
+			return nil, err
+		}
+
+		address := ""
+		if module.Key != "" {
+			address = "module." + strings.ReplaceAll(module.Key, ".", ".module.")
+		}
+
+		addresses[modulePath] = append(addresses[modulePath], address)
 	}
 
-	return paths, nil
+	return addresses, nil
 }
 
 type ModulesJson struct {
@@ -2,6 +2,7 @@ package tfschema
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,11 +11,13 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/cloudyali/terratag/internal/common"
 	"github.com/cloudyali/terratag/internal/providers"
 	"github.com/cloudyali/terratag/internal/tagging"
 	"github.com/cloudyali/terratag/internal/terraform"
+	"github.com/cloudyali/terratag/internal/utils"
 	"github.com/thoas/go-funk"
 
 	"maps"
@@ -55,12 +58,13 @@ type ProviderSchemas struct {
 
 // InitProviderSchemas fetches and stores the provider schemas for a directory using a centralized cache
 // This can be called ahead of time to pre-populate the schemas cache
-func InitProviderSchemas(dir string, iacType common.IACType, defaultToTerraform bool) error {
-	return InitProviderSchemasWithCache(dir, iacType, defaultToTerraform, true)
+func InitProviderSchemas(ctx context.Context, dir string, iacType common.IACType, defaultToTerraform bool, timeouts common.CommandTimeouts) error {
+	return InitProviderSchemasWithCache(ctx, dir, iacType, defaultToTerraform, true, timeouts)
 }
 
-// InitProviderSchemasWithCache fetches and stores the provider schemas with optional caching
-func InitProviderSchemasWithCache(dir string, iacType common.IACType, defaultToTerraform bool, useCache bool) error {
+// InitProviderSchemasWithCache fetches and stores the provider schemas with optional caching.
+// The init and providers schema commands are killed when ctx is done or their timeout expires.
+func InitProviderSchemasWithCache(ctx context.Context, dir string, iacType common.IACType, defaultToTerraform bool, useCache bool, timeouts common.CommandTimeouts) error {
 	// Check if schemas are already cached in memory
 	if _, exists := providerSchemasMap[dir]; exists {
 		log.Printf("[INFO] Provider schemas already loaded for directory: %s", dir)
@@ -70,7 +74,7 @@ func InitProviderSchemasWithCache(dir string, iacType common.IACType, defaultToT
 	// Skip caching if disabled
 	if !useCache {
 		log.Printf("[INFO] Provider cache disabled, fetching schemas locally for directory: %s", dir)
-		return initProviderSchemasLocal(dir, iacType, defaultToTerraform)
+		return initProviderSchemasLocal(ctx, dir, iacType, defaultToTerraform, timeouts.Schema)
 	}
 
 	// Try to get cached schema first
@@ -95,22 +99,30 @@ func InitProviderSchemasWithCache(dir string, iacType common.IACType, defaultToT
 	sharedTerraformDir, err := cacheManager.GetOrCreateSharedTerraformDir(dir, iacType)
 	if err != nil {
 		log.Printf("[WARN] Failed to create shared terraform directory, falling back to local: %v", err)
-		return initProviderSchemasLocal(dir, iacType, defaultToTerraform)
+		return initProviderSchemasLocal(ctx, dir, iacType, defaultToTerraform, timeouts.Schema)
 	}
 
 	// Initialize providers in shared directory if needed
 	if _, err := os.Stat(filepath.Join(sharedTerraformDir, ".terraform")); os.IsNotExist(err) {
-		if err := cacheManager.InitProviders(sharedTerraformDir, iacType, defaultToTerraform); err != nil {
+		if err := cacheManager.InitProviders(ctx, sharedTerraformDir, iacType, defaultToTerraform, timeouts.Init); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+
 			log.Printf("[WARN] Failed to initialize shared providers, falling back to local: %v", err)
-			return initProviderSchemasLocal(dir, iacType, defaultToTerraform)
+			return initProviderSchemasLocal(ctx, dir, iacType, defaultToTerraform, timeouts.Schema)
 		}
 	}
 
 	// Fetch schema from shared directory
-	schemaData, err := fetchProviderSchemas(sharedTerraformDir, iacType, defaultToTerraform)
+	schemaData, err := fetchProviderSchemas(ctx, sharedTerraformDir, iacType, defaultToTerraform, timeouts.Schema)
 	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+
 		log.Printf("[WARN] Failed to fetch schema from shared directory, falling back to local: %v", err)
-		return initProviderSchemasLocal(dir, iacType, defaultToTerraform)
+		return initProviderSchemasLocal(ctx, dir, iacType, defaultToTerraform, timeouts.Schema)
 	}
 
 	// Parse and store schema
@@ -136,10 +148,10 @@ func InitProviderSchemasWithCache(dir string, iacType common.IACType, defaultToT
 }
 
 // initProviderSchemasLocal is the fallback implementation that works locally
-func initProviderSchemasLocal(dir string, iacType common.IACType, defaultToTerraform bool) error {
+func initProviderSchemasLocal(ctx context.Context, dir string, iacType common.IACType, defaultToTerraform bool, timeout time.Duration) error {
 	log.Print("[INFO] Fetching provider schemas locally for directory: ", dir)
 
-	schemaData, err := fetchProviderSchemas(dir, iacType, defaultToTerraform)
+	schemaData, err := fetchProviderSchemas(ctx, dir, iacType, defaultToTerraform, timeout)
 	if err != nil {
 		return err
 	}
@@ -160,8 +172,8 @@ func initProviderSchemasLocal(dir string, iacType common.IACType, defaultToTerra
 	return nil
 }
 
-// fetchProviderSchemas fetches provider schemas from the specified directory
-func fetchProviderSchemas(dir string, iacType common.IACType, defaultToTerraform bool) (string, error) {
+// fetchProviderSchemas fetches provider schemas from the specified directory, a timeout of 0 disables the timeout
+func fetchProviderSchemas(ctx context.Context, dir string, iacType common.IACType, defaultToTerraform bool, timeout time.Duration) (string, error) {
 	// Use tofu by default (if it exists).
 	name := "terraform"
 	// For terragrunt - use terragrunt.
@@ -171,12 +183,12 @@ func fetchProviderSchemas(dir string, iacType common.IACType, defaultToTerraform
 		name = "tofu"
 	}
 
-	var cmd *exec.Cmd
+	var cmd *utils.Command
 	if iacType == common.TerragruntRunAll {
 		log.Print("[INFO] Using terragrunt run-all mode")
-		cmd = exec.Command(name, "run-all", "providers", "schema", "-json")
+		cmd = utils.NewCommand(ctx, timeout, name, "run-all", "providers", "schema", "-json")
 	} else {
-		cmd = exec.Command(name, "providers", "schema", "-json")
+		cmd = utils.NewCommand(ctx, timeout, name, "providers", "schema", "-json")
 	}
 	cmd.Dir = dir
 
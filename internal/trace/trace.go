@@ -0,0 +1,140 @@
+package trace
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudyali/terratag/internal/file"
+	"github.com/cloudyali/terratag/internal/providers"
+)
+
+// Keys of the traceability tags
+const (
+	TagFile              = "terratag_file"             // Path of the file relative to the repository
+	TagResourceAddress   = "terratag_resource_address" // Address of the resource in its module, e.g. aws_instance.web
+	TagGitRepo           = "git_repo"                  // Remote origin of the repository, e.g. github.com/org/repo
+	TagGitCommit         = "git_commit"                // Last commit that modified the block
+	TagGitLastModifiedBy = "git_last_modified_by"      // Author email of the last commit
+	TagGitLastModifiedAt = "git_last_modified_at"      // Author time of the last commit, UTC
+)
+
+// Tracer computes the traceability tags of the resource blocks of a directory from the local git repository.
+// Outside of a git working tree, only the file and resource address tags are computed.
+type Tracer struct {
+	ctx        context.Context
+	dir        string
+	repository *gitRepository
+}
+
+// NewTracer returns the tracer of the resource blocks of dir. Cancelling ctx kills the running git commands.
+func NewTracer(ctx context.Context, dir string) (*Tracer, error) {
+	repository, err := openGitRepository(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if repository == nil {
+		log.Printf("[WARN] %s is not in a git working tree, only the %s and %s trace tags are added", dir, TagFile, TagResourceAddress)
+	}
+
+	return &Tracer{ctx: ctx, dir: dir, repository: repository}, nil
+}
+
+// FileTags returns the traceability tags of the resource blocks of a file, keyed by resource address
+// (e.g. aws_instance.web). The git tags of blocks with uncommitted changes and of untracked files are omitted.
+// Generated .terratag.tf files of a previous run are traced as their original, from the backup of its content.
+func (t *Tracer) FileTags(path string) map[string]map[string]string {
+	fileTags := map[string]map[string]string{}
+
+	relativePath := t.relativePath(path)
+
+	// The lines of the blocks are blamed in source, which is the file itself unless it is a generated file
+	source, contents := path, ""
+	if file.IsTerratagFile(path) {
+		relativePath = file.OriginalPath(relativePath)
+		contents = path
+
+		if backup := file.OriginalPath(path) + ".bak"; fileExists(backup) {
+			source, contents = backup, backup
+		}
+	}
+
+	var blame map[int]lineCommit
+
+	if t.repository != nil {
+		var err error
+		if blame, err = t.repository.blame(t.ctx, relativePath, contents); err != nil {
+			log.Printf("[INFO] Can't blame %s, it may not be committed yet. Only the %s and %s trace tags are added: %v", path, TagFile, TagResourceAddress, err)
+		}
+	}
+
+	sourceRanges := file.BlockLineRanges(source)
+
+	for key := range file.BlockLineRanges(path) {
+		address, ok := strings.CutPrefix(key, "resource.")
+		if !ok {
+			continue
+		}
+
+		tags := map[string]string{
+			TagFile:            relativePath,
+			TagResourceAddress: address,
+		}
+
+		if t.repository != nil {
+			tags[TagGitRepo] = t.repository.name
+		}
+
+		lineRange := sourceRanges[key]
+
+		if commit, ok := lastCommit(blame, lineRange.Start, lineRange.End); ok {
+			tags[TagGitCommit] = commit.hash
+			tags[TagGitLastModifiedBy] = commit.authorMail
+			tags[TagGitLastModifiedAt] = commit.authorTime.Format("2006-01-02 15:04:05")
+		} else if blame != nil {
+			log.Printf("[INFO] %s of %s has uncommitted changes, its git trace tags are omitted", address, path)
+		}
+
+		fileTags[address] = tags
+	}
+
+	return fileTags
+}
+
+// fileExists returns whether a file exists at path
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+
+	return err == nil
+}
+
+// relativePath returns the slash separated path of a file relative to the repository, or to the tagged
+// directory outside of a git working tree
+func (t *Tracer) relativePath(path string) string {
+	if t.repository != nil {
+		if relative, err := t.repository.relativePath(path); err == nil {
+			return relative
+		}
+	}
+
+	if relative, err := filepath.Rel(t.dir, path); err == nil {
+		return filepath.ToSlash(relative)
+	}
+
+	return filepath.ToSlash(path)
+}
+
+// LabelValues returns a copy of the tags whose values are valid Google label values: lower case letters, digits,
+// underscores and dashes, at most 63 characters long
+func LabelValues(tags map[string]string) map[string]string {
+	labels := make(map[string]string, len(tags))
+
+	for key, value := range tags {
+		labels[key] = providers.SanitizeLabelValue(value)
+	}
+
+	return labels
+}
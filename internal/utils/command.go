@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// commandWaitDelay bounds how long a killed command may keep its output pipes open
+const commandWaitDelay = 5 * time.Second
+
+// Command is an external command bound to a context and an optional timeout.
+// Cancelling the context or reaching the timeout kills the command together with the processes it started,
+// e.g. the terraform processes of terragrunt.
+type Command struct {
+	*exec.Cmd
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timeout time.Duration
+}
+
+// NewCommand creates a command that is killed once ctx is done or after timeout, a timeout of 0 disables it
+func NewCommand(ctx context.Context, timeout time.Duration, name string, args ...string) *Command {
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = commandWaitDelay
+	killProcessGroup(cmd)
+
+	return &Command{
+		Cmd:     cmd,
+		ctx:     ctx,
+		cancel:  cancel,
+		timeout: timeout,
+	}
+}
+
+// Output runs the command and returns its standard output
+func (c *Command) Output() ([]byte, error) {
+	defer c.cancel()
+
+	out, err := c.Cmd.Output()
+
+	return out, c.wrapError(err)
+}
+
+// CombinedOutput runs the command and returns its combined standard output and standard error
+func (c *Command) CombinedOutput() ([]byte, error) {
+	defer c.cancel()
+
+	out, err := c.Cmd.CombinedOutput()
+
+	return out, c.wrapError(err)
+}
+
+// wrapError reports a command killed because of a timeout or a cancellation as such, instead of as an exit error
+func (c *Command) wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	command := strings.Join(c.Args, " ")
+
+	switch ctxErr := c.ctx.Err(); {
+	case errors.Is(ctxErr, context.DeadlineExceeded) && c.timeout > 0:
+		return fmt.Errorf("'%s' timed out after %s: %w", command, c.timeout, ctxErr)
+	case ctxErr != nil:
+		return fmt.Errorf("'%s' was cancelled: %w", command, ctxErr)
+	}
+
+	return err
+}
@@ -0,0 +1,51 @@
+//go:build !windows
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommandTimeout(t *testing.T) {
+	start := time.Now()
+
+	// The child sleep keeps the output pipe open, it has to be killed with the shell
+	_, err := NewCommand(context.Background(), 100*time.Millisecond, "sh", "-c", "sleep 30 & sleep 30").CombinedOutput()
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected a deadline exceeded error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "timed out after 100ms") {
+		t.Errorf("Expected the timeout in the error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Expected the command to be killed promptly, took %s", elapsed)
+	}
+}
+
+func TestCommandCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	_, err := NewCommand(ctx, 0, "sh", "-c", "sleep 30 & sleep 30").Output()
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected a cancelled error, got %v", err)
+	}
+}
+
+func TestCommandSuccess(t *testing.T) {
+	out, err := NewCommand(context.Background(), time.Minute, "echo", "tagged").Output()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if strings.TrimSpace(string(out)) != "tagged" {
+		t.Errorf("Expected output 'tagged', got %q", out)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package utils
+
+import (
+	"os/exec"
+)
+
+// killProcessGroup keeps the default cancellation on Windows, which kills the command process only
+func killProcessGroup(cmd *exec.Cmd) {}
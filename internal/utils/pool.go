@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ParallelMap calls fn for every item with at most parallelism concurrent calls and returns the results
+// in the order of the items. A parallelism lower than 1 defaults to the number of CPUs.
+// Every call gets its own pool, so concurrent ParallelMap calls don't wait for each other.
+func ParallelMap[T any, R any](items []T, parallelism int, fn func(T) R) []R {
+	if parallelism < 1 {
+		parallelism = runtime.NumCPU()
+	}
+
+	if parallelism > len(items) {
+		parallelism = len(items)
+	}
+
+	results := make([]R, len(items))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for worker := 0; worker < parallelism; worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				results[i] = fn(items[i])
+			}
+		}()
+	}
+
+	for i := range items {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
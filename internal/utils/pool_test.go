@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelMap(t *testing.T) {
+	items := []int{5, 3, 8, 1, 9, 2, 7}
+
+	var running, maxRunning int32
+
+	results := ParallelMap(items, 3, func(item int) int {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			previous := atomic.LoadInt32(&maxRunning)
+			if current <= previous || atomic.CompareAndSwapInt32(&maxRunning, previous, current) {
+				break
+			}
+		}
+
+		time.Sleep(time.Duration(item) * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+
+		return item * 10
+	})
+
+	expected := []int{50, 30, 80, 10, 90, 20, 70}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("ParallelMap() = %v, want %v", results, expected)
+	}
+
+	if maxRunning > 3 {
+		t.Errorf("expected at most 3 concurrent calls, got %d", maxRunning)
+	}
+}
+
+func TestParallelMapEmpty(t *testing.T) {
+	results := ParallelMap([]string{}, 0, func(item string) string {
+		return item
+	})
+
+	if len(results) != 0 {
+		t.Errorf("expected no results, got %v", results)
+	}
+}
@@ -0,0 +1,388 @@
+package validation
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/cloudyali/terratag/internal/convert"
+	"github.com/cloudyali/terratag/internal/file"
+	"github.com/cloudyali/terratag/internal/providers"
+	"github.com/cloudyali/terratag/internal/standards"
+)
+
+const autoFixBackupSuffix = ".autofix.bak"
+
+// manualFix is a suggested fix auto-fix could not apply
+type manualFix struct {
+	Resource string
+	Location string
+	TagKey   string
+	Reason   string
+}
+
+// autoFixResult is the outcome of applying the suggested fixes of a validation run
+type autoFixResult struct {
+	FixedFiles []string
+	Applied    int
+	Manual     []manualFix
+}
+
+// applySuggestedFixes rewrites the literal tag keys and values of the validated files according to their suggested
+// fixes. Values set by variables, locals or other computed expressions are left untouched and reported as manual
+// fixes. Original files are backed up to <file>.autofix.bak, so that the fixes can be undone, and restored if any
+// file fails to be written.
+func applySuggestedFixes(results []standards.ValidationResult) (*autoFixResult, error) {
+	fixResult := &autoFixResult{}
+
+	byFile := map[string][]standards.ValidationResult{}
+
+	var paths []string
+
+	for _, result := range results {
+		if len(result.SuggestedFixes) == 0 {
+			continue
+		}
+
+		if _, ok := byFile[result.FilePath]; !ok {
+			paths = append(paths, result.FilePath)
+		}
+
+		byFile[result.FilePath] = append(byFile[result.FilePath], result)
+	}
+
+	sort.Strings(paths)
+
+	fixed := map[string]string{}
+
+	for _, path := range paths {
+		var text string
+		var applied int
+		var err error
+
+		if file.IsJSONFile(path) {
+			text, applied, err = fixJSONFile(path, byFile[path], fixResult)
+		} else {
+			text, applied, err = fixHCLFile(path, byFile[path], fixResult)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to fix %s: %w", path, err)
+		}
+
+		if applied > 0 {
+			fixed[path] = text
+			fixResult.Applied += applied
+			fixResult.FixedFiles = append(fixResult.FixedFiles, path)
+		}
+	}
+
+	if err := writeFixedFiles(fixResult.FixedFiles, fixed); err != nil {
+		return nil, err
+	}
+
+	return fixResult, nil
+}
+
+// fixHCLFile returns the fixed content of a native syntax file and the number of fixes applied
+func fixHCLFile(path string, results []standards.ValidationResult, fixResult *autoFixResult) (string, int, error) {
+	if path == "" {
+		for _, result := range results {
+			addManualFixes(fixResult, result, tagEditsForResult(result, fixResult), "the file of the resource is unknown")
+		}
+
+		return "", 0, nil
+	}
+
+	hclFile, err := file.ReadHCLFile(path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	blocks := map[string]*hclwrite.Block{}
+
+	for _, block := range hclFile.Body().Blocks() {
+		if block.Type() == "resource" && len(block.Labels()) == 2 {
+			blocks[block.Labels()[0]+"."+block.Labels()[1]] = block
+		}
+	}
+
+	applied := 0
+
+	for _, result := range results {
+		edits := tagEditsForResult(result, fixResult)
+		if len(edits) == 0 {
+			continue
+		}
+
+		block, ok := blocks[result.ResourceType+"."+result.ResourceName]
+		if !ok {
+			addManualFixes(fixResult, result, edits, "resource block not found")
+
+			continue
+		}
+
+		tagId := providers.GetTagIdByResource(result.ResourceType)
+		if tagId == "" {
+			addManualFixes(fixResult, result, edits, "tag attribute of the resource type is unknown")
+
+			continue
+		}
+
+		var tokens hclwrite.Tokens
+		if attribute := block.Body().GetAttribute(tagId); attribute != nil {
+			tokens = attribute.Expr().BuildTokens(nil)
+		}
+
+		edited, skipped, err := convert.EditTags(tokens, edits)
+		if err != nil {
+			addManualFixes(fixResult, result, edits, err.Error())
+
+			continue
+		}
+
+		for _, edit := range edits {
+			if reason, ok := skipped[edit.Key]; ok {
+				addManualFix(fixResult, result, edit.Key, reason)
+			}
+		}
+
+		if len(edits) > len(skipped) {
+			block.Body().SetAttributeRaw(tagId, edited)
+			applied += len(edits) - len(skipped)
+		}
+	}
+
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	// Realign the edited tags, the rest of the file is left as written
+	return string(file.KeepLayout(original, hclFile.Bytes())), applied, nil
+}
+
+// fixJSONFile returns the fixed content of a JSON syntax file and the number of fixes applied.
+// Tag expressions ("${var.tags}") and interpolated values are reported as manual fixes.
+func fixJSONFile(path string, results []standards.ValidationResult, fixResult *autoFixResult) (string, int, error) {
+	jsonFile, err := file.ReadJSONFile(path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	blocks := map[string]*file.JSONBlock{}
+
+	for _, block := range jsonFile.Blocks("resource") {
+		blocks[strings.Join(block.Labels, ".")] = block
+	}
+
+	applied := 0
+
+	for _, result := range results {
+		edits := tagEditsForResult(result, fixResult)
+		if len(edits) == 0 {
+			continue
+		}
+
+		block, ok := blocks[result.ResourceType+"."+result.ResourceName]
+		if !ok {
+			addManualFixes(fixResult, result, edits, "resource block not found")
+
+			continue
+		}
+
+		tagId := providers.GetTagIdByResource(result.ResourceType)
+		if tagId == "" {
+			addManualFixes(fixResult, result, edits, "tag attribute of the resource type is unknown")
+
+			continue
+		}
+
+		tags, ok := block.Body[tagId].(map[string]interface{})
+		if block.Body[tagId] == nil {
+			tags = map[string]interface{}{}
+		} else if !ok {
+			addManualFixes(fixResult, result, edits, fmt.Sprintf("tags are computed by %v", block.Body[tagId]))
+
+			continue
+		}
+
+		for _, edit := range edits {
+			value, exists := tags[edit.Key]
+
+			switch {
+			case edit.Action != convert.TagEditSet && !exists:
+				addManualFix(fixResult, result, edit.Key, "tag is not set")
+			case edit.Action == convert.TagEditSet && exists && strings.Contains(fmt.Sprint(value), "${"):
+				addManualFix(fixResult, result, edit.Key, fmt.Sprintf("value %v is computed", value))
+			case edit.Action == convert.TagEditSet:
+				tags[edit.Key] = edit.Value
+				applied++
+			case edit.Action == convert.TagEditRemove:
+				delete(tags, edit.Key)
+				applied++
+			case edit.Action == convert.TagEditRename:
+				if _, ok := tags[edit.NewKey]; ok {
+					addManualFix(fixResult, result, edit.Key, edit.NewKey+" is already set")
+
+					continue
+				}
+
+				tags[edit.NewKey] = value
+				delete(tags, edit.Key)
+				applied++
+			}
+		}
+
+		if len(tags) > 0 {
+			block.Body[tagId] = tags
+		}
+	}
+
+	text, err := jsonFile.Bytes()
+	if err != nil {
+		return "", 0, err
+	}
+
+	return string(text), applied, nil
+}
+
+// tagEditsForResult converts the suggested fixes of a resource to tag edits, one per tag key.
+// A tag that isn't defined in the standard and only differs in case from a missing required tag is renamed
+// instead of being removed and added again. Fixes without a suggested value and fixes of tags inherited from
+// provider default tags are reported as manual fixes.
+func tagEditsForResult(result standards.ValidationResult, fixResult *autoFixResult) []convert.TagEdit {
+	var edits []convert.TagEdit
+
+	handled := map[string]bool{}
+
+	for _, remove := range result.SuggestedFixes {
+		if remove.Action != standards.ActionRemove {
+			continue
+		}
+
+		for _, add := range result.SuggestedFixes {
+			if add.Action == standards.ActionAdd && add.TagKey != remove.TagKey && strings.EqualFold(add.TagKey, remove.TagKey) && !handled[add.TagKey] {
+				edits = append(edits, convert.TagEdit{Action: convert.TagEditRename, Key: remove.TagKey, NewKey: add.TagKey})
+				handled[remove.TagKey] = true
+				handled[add.TagKey] = true
+
+				break
+			}
+		}
+	}
+
+	for _, fix := range result.SuggestedFixes {
+		if handled[fix.TagKey] {
+			continue
+		}
+
+		if result.TagSources[fix.TagKey] == standards.TagSourceProviderDefault {
+			addManualFix(fixResult, result, fix.TagKey, "tag is inherited from provider default tags")
+			handled[fix.TagKey] = true
+
+			continue
+		}
+
+		if fix.Action == standards.ActionRemove {
+			edits = append(edits, convert.TagEdit{Action: convert.TagEditRemove, Key: fix.TagKey})
+			handled[fix.TagKey] = true
+
+			continue
+		}
+
+		if fix.SuggestedValue == "" {
+			addManualFix(fixResult, result, fix.TagKey, "no suggested value: "+fix.Reason)
+			handled[fix.TagKey] = true
+
+			continue
+		}
+
+		edits = append(edits, convert.TagEdit{Action: convert.TagEditSet, Key: fix.TagKey, Value: fix.SuggestedValue})
+		handled[fix.TagKey] = true
+	}
+
+	return edits
+}
+
+func addManualFix(fixResult *autoFixResult, result standards.ValidationResult, tagKey string, reason string) {
+	location := result.FilePath
+	if result.LineNumber > 0 {
+		location = fmt.Sprintf("%s:%d", result.FilePath, result.LineNumber)
+	}
+
+	fixResult.Manual = append(fixResult.Manual, manualFix{
+		Resource: result.ResourceType + "." + result.ResourceName,
+		Location: location,
+		TagKey:   tagKey,
+		Reason:   reason,
+	})
+}
+
+func addManualFixes(fixResult *autoFixResult, result standards.ValidationResult, edits []convert.TagEdit, reason string) {
+	for _, edit := range edits {
+		addManualFix(fixResult, result, edit.Key, reason)
+	}
+}
+
+// writeFixedFiles writes the fixed files after backing them up. The backups are kept, if any file fails to be
+// written the files written up to it are restored from their backups.
+func writeFixedFiles(paths []string, fixed map[string]string) error {
+	var written []string
+
+	restore := func() {
+		for _, path := range written {
+			if err := os.Rename(path+autoFixBackupSuffix, path); err != nil {
+				log.Printf("[ERROR] Failed to restore %s from its backup %s: %v", path, path+autoFixBackupSuffix, err)
+			}
+		}
+	}
+
+	for _, path := range paths {
+		original, err := os.ReadFile(path)
+		if err != nil {
+			restore()
+
+			return err
+		}
+
+		backupPath := path + autoFixBackupSuffix
+		if err := os.WriteFile(backupPath, original, 0644); err != nil {
+			restore()
+
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+
+		written = append(written, path)
+
+		if err := file.CreateFile(path, fixed[path]); err != nil {
+			restore()
+
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+
+		log.Printf("[VALIDATION] Applied suggested fixes to %s", path)
+	}
+
+	return nil
+}
+
+// printAutoFixSummary writes the applied and the manual fixes
+func printAutoFixSummary(w io.Writer, fixResult *autoFixResult) {
+	fmt.Fprintf(w, "Auto-fix applied %d fix(es) in %d file(s)\n", fixResult.Applied, len(fixResult.FixedFiles))
+
+	if len(fixResult.Manual) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nFixes to apply manually:\n")
+
+	for _, manual := range fixResult.Manual {
+		fmt.Fprintf(w, "  • %s %s, tag '%s': %s\n", manual.Location, manual.Resource, manual.TagKey, manual.Reason)
+	}
+}
@@ -0,0 +1,197 @@
+package validation
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudyali/terratag/internal/standards"
+)
+
+func TestApplySuggestedFixes(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tfContent := `resource "aws_instance" "web" {
+  ami = "ami-12345"
+  tags = {
+    environment = "prod"
+    Team        = "plat"
+    Owner       = var.owner
+    Legacy      = "yes"
+  }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket="logs"
+  acl    = "private"
+}
+`
+	path := filepath.Join(tmpDir, "main.tf")
+	if err := os.WriteFile(path, []byte(tfContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	results := []standards.ValidationResult{
+		{
+			ResourceType: "aws_instance",
+			ResourceName: "web",
+			FilePath:     path,
+			LineNumber:   1,
+			SuggestedFixes: []standards.SuggestedFix{
+				{TagKey: "Environment", SuggestedValue: "prod", Action: standards.ActionAdd},
+				{TagKey: "environment", CurrentValue: "prod", Action: standards.ActionRemove},
+				{TagKey: "Team", CurrentValue: "plat", SuggestedValue: "platform", Action: standards.ActionUpdate},
+				{TagKey: "Owner", CurrentValue: "var.owner", SuggestedValue: "team@corp.com", Action: standards.ActionUpdate},
+				{TagKey: "Legacy", CurrentValue: "yes", Action: standards.ActionRemove},
+				{TagKey: "CostCenter", Action: standards.ActionAdd, Reason: "Required tag 'CostCenter' is missing"},
+			},
+		},
+		{
+			ResourceType: "aws_s3_bucket",
+			ResourceName: "logs",
+			FilePath:     path,
+			LineNumber:   11,
+			SuggestedFixes: []standards.SuggestedFix{
+				{TagKey: "Environment", SuggestedValue: "prod", Action: standards.ActionAdd},
+			},
+		},
+	}
+
+	fixResult, err := applySuggestedFixes(results)
+	if err != nil {
+		t.Fatalf("applySuggestedFixes() error = %v", err)
+	}
+
+	if fixResult.Applied != 4 {
+		t.Errorf("expected 4 applied fixes, got %d", fixResult.Applied)
+	}
+
+	if len(fixResult.Manual) != 2 {
+		t.Fatalf("expected 2 manual fixes, got %+v", fixResult.Manual)
+	}
+
+	for _, manual := range fixResult.Manual {
+		if manual.TagKey != "Owner" && manual.TagKey != "CostCenter" {
+			t.Errorf("unexpected manual fix %+v", manual)
+		}
+	}
+
+	fixed, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read fixed file: %v", err)
+	}
+
+	expected := `resource "aws_instance" "web" {
+  ami = "ami-12345"
+  tags = {
+    Environment = "prod"
+    Team        = "platform"
+    Owner       = var.owner
+  }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket="logs"
+  acl    = "private"
+  tags = {
+    Environment = "prod"
+  }
+}
+`
+	if string(fixed) != expected {
+		t.Errorf("unexpected fixed file:\n%s\nwant:\n%s", fixed, expected)
+	}
+
+	// The backup is kept, so that the fixes can be undone
+	backup, err := os.ReadFile(path + autoFixBackupSuffix)
+	if err != nil {
+		t.Fatalf("expected the backup to be kept: %v", err)
+	}
+
+	if string(backup) != tfContent {
+		t.Errorf("unexpected backup:\n%s\nwant:\n%s", backup, tfContent)
+	}
+}
+
+func TestApplySuggestedFixes_ProviderDefaultTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.tf")
+	if err := os.WriteFile(path, []byte("resource \"aws_instance\" \"web\" {\n}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	results := []standards.ValidationResult{
+		{
+			ResourceType: "aws_instance",
+			ResourceName: "web",
+			FilePath:     path,
+			SuggestedFixes: []standards.SuggestedFix{
+				{TagKey: "Team", CurrentValue: "plat", SuggestedValue: "platform", Action: standards.ActionUpdate},
+			},
+			TagSources: map[string]standards.TagSource{"Team": standards.TagSourceProviderDefault},
+		},
+	}
+
+	fixResult, err := applySuggestedFixes(results)
+	if err != nil {
+		t.Fatalf("applySuggestedFixes() error = %v", err)
+	}
+
+	if fixResult.Applied != 0 || len(fixResult.Manual) != 1 {
+		t.Errorf("expected the provider default tag to be fixed manually, got %+v", fixResult)
+	}
+}
+
+func TestApplySuggestedFixes_JSONUnknownTagAttribute(t *testing.T) {
+	content := `{"resource": {"datadog_monitor": {"cpu": {"name": "cpu"}}, "aws_instance": {"web": {"ami": "ami-12345"}}}}`
+
+	path := filepath.Join(t.TempDir(), "main.tf.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	results := []standards.ValidationResult{
+		{
+			ResourceType: "datadog_monitor",
+			ResourceName: "cpu",
+			FilePath:     path,
+			SuggestedFixes: []standards.SuggestedFix{
+				{TagKey: "Environment", SuggestedValue: "prod", Action: standards.ActionAdd},
+			},
+		},
+		{
+			ResourceType: "aws_instance",
+			ResourceName: "web",
+			FilePath:     path,
+			SuggestedFixes: []standards.SuggestedFix{
+				{TagKey: "Environment", SuggestedValue: "prod", Action: standards.ActionAdd},
+			},
+		},
+	}
+
+	fixResult, err := applySuggestedFixes(results)
+	if err != nil {
+		t.Fatalf("applySuggestedFixes() error = %v", err)
+	}
+
+	if fixResult.Applied != 1 || len(fixResult.Manual) != 1 || fixResult.Manual[0].Resource != "datadog_monitor.cpu" {
+		t.Fatalf("expected the datadog_monitor fix to be manual, got %+v", fixResult)
+	}
+
+	fixed, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read fixed file: %v", err)
+	}
+
+	var parsed map[string]map[string]map[string]map[string]interface{}
+	if err := json.Unmarshal(fixed, &parsed); err != nil {
+		t.Fatalf("Failed to parse fixed file: %v", err)
+	}
+
+	if _, ok := parsed["resource"]["datadog_monitor"]["cpu"][""]; ok {
+		t.Errorf("expected no tags to be written to datadog_monitor.cpu, got %s", fixed)
+	}
+	if _, ok := parsed["resource"]["aws_instance"]["web"]["tags"]; !ok {
+		t.Errorf("expected tags to be written to aws_instance.web, got %s", fixed)
+	}
+}
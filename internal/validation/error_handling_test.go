@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -131,7 +132,7 @@ required_tags:
 				defer os.RemoveAll(args.Dir)
 			}
 			
-			err := ValidateStandards(args)
+			err := ValidateStandards(context.Background(), args)
 			
 			if tt.expectError {
 				assert.Error(t, err)
@@ -238,7 +239,10 @@ resource "null_resource" "not_taggable" {
 				Skip:   "",
 			}
 
-			resources, err := extractResourcesFromFile(tfFile, args, "aws")
+			selection, err := newResourceSelection(args, false)
+			require.NoError(t, err)
+
+			resources, err := extractResourcesFromFile(tfFile, args, awsStandard, selection)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -281,7 +285,7 @@ resource "aws_instance" "test_` + string(rune('a'+i)) + `" {
 		Skip:                "",
 	}
 
-	resources, err := collectResources(files, args, "aws")
+	resources, err := collectResources(context.Background(), files, args, awsStandard)
 	require.NoError(t, err)
 
 	// Should have 10 resources (one from each file)
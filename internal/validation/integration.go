@@ -1,12 +1,12 @@
 package validation
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
-	"regexp"
+	"path/filepath"
 	"strings"
-	"sync"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
@@ -18,9 +18,11 @@ import (
 	"github.com/cloudyali/terratag/internal/file"
 	hclutil "github.com/cloudyali/terratag/internal/hcl"
 	"github.com/cloudyali/terratag/internal/providers"
+	"github.com/cloudyali/terratag/internal/selector"
 	"github.com/cloudyali/terratag/internal/standards"
 	"github.com/cloudyali/terratag/internal/terraform"
 	"github.com/cloudyali/terratag/internal/tfschema"
+	"github.com/cloudyali/terratag/internal/utils"
 )
 
 // blockPos holds position information for a resource block
@@ -116,8 +118,9 @@ func findResourceBlockEnd(lines []string, startIdx int) int {
 	return -1
 }
 
-// ValidateStandards validates terraform files against tag standards
-func ValidateStandards(args cli.Args) error {
+// ValidateStandards validates terraform files against tag standards.
+// Cancelling ctx kills running terraform subprocesses and stops collecting resources between files.
+func ValidateStandards(ctx context.Context, args cli.Args) error {
 	// Create cleanup manager for validation
 	cleanupMgr := cleanup.NewCleanupManager(nil)
 	defer func() {
@@ -144,11 +147,12 @@ func ValidateStandards(args cli.Args) error {
 	}
 
 	var resources []standards.ResourceInfo
+	var matches []string
 
 	// Check if plan file is provided for enhanced variable resolution
 	if args.PlanFile != "" {
 		log.Printf("[VALIDATION] Using Terraform plan file for variable resolution: %s", args.PlanFile)
-		resources, err = collectResourcesFromPlan(args.PlanFile, args, standard.CloudProvider)
+		resources, err = collectResourcesFromPlan(args.PlanFile, args, standard)
 		if err != nil {
 			return fmt.Errorf("failed to collect resources from plan: %w", err)
 		}
@@ -169,7 +173,7 @@ func ValidateStandards(args cli.Args) error {
 		// Ensure terraform is initialized if auto-init is enabled
 		if args.AutoInit {
 			log.Printf("[VALIDATION] Auto-init enabled, ensuring terraform is properly initialized")
-			if err := terraform.EnsureInitialized(args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache); err != nil {
+			if err := terraform.EnsureInitialized(ctx, args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache, args.CommandTimeouts()); err != nil {
 				log.Printf("[WARN] Auto-initialization failed during validation: %v", err)
 			} else {
 				log.Printf("[VALIDATION] Terraform initialization verified/completed successfully")
@@ -181,23 +185,23 @@ func ValidateStandards(args cli.Args) error {
 			return err
 		}
 
-		matches, err := terraform.GetFilePaths(args.Dir, args.Type)
+		matches, err = terraform.GetFilePaths(args.Dir, args.Type)
 		if err != nil {
 			return err
 		}
 
 		// Initialize provider schemas
-		if err := tfschema.InitProviderSchemasWithCache(args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache); err != nil {
+		if err := tfschema.InitProviderSchemasWithCache(ctx, args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache, args.CommandTimeouts()); err != nil {
 			log.Printf("[WARN] Failed to pre-initialize provider schemas: %v", err)
 			
 			// If auto-init is enabled and schema init failed, try to resolve
 			if args.AutoInit {
 				log.Printf("[VALIDATION] Attempting to resolve schema issue with auto-init")
-				if initErr := terraform.EnsureInitialized(args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache); initErr != nil {
+				if initErr := terraform.EnsureInitialized(ctx, args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache, args.CommandTimeouts()); initErr != nil {
 					log.Printf("[WARN] Auto-init resolution failed during validation: %v", initErr)
 				} else {
 					// Retry schema initialization
-					if retryErr := tfschema.InitProviderSchemasWithCache(args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache); retryErr != nil {
+					if retryErr := tfschema.InitProviderSchemasWithCache(ctx, args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache, args.CommandTimeouts()); retryErr != nil {
 						log.Printf("[WARN] Schema initialization still failed after auto-init: %v", retryErr)
 					}
 				}
@@ -206,7 +210,7 @@ func ValidateStandards(args cli.Args) error {
 
 		// Collect all resources to validate
 		log.Printf("[VALIDATION] Collecting resources from %d files", len(matches))
-		resources, err = collectResources(matches, args, standard.CloudProvider)
+		resources, err = collectResources(ctx, matches, args, standard)
 		if err != nil {
 			return fmt.Errorf("failed to collect resources: %w", err)
 		}
@@ -221,6 +225,13 @@ func ValidateStandards(args cli.Args) error {
 	// Generate report
 	report := validator.CreateValidationReport(results, args.StandardFile)
 
+	if args.AutoFix {
+		report, err = autoFix(ctx, args, validator, report, matches, standard)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Output report
 	options := standards.ValidationOptions{
 		StrictMode:   args.StrictMode,
@@ -236,7 +247,7 @@ func ValidateStandards(args cli.Args) error {
 	// Print summary to stderr so it doesn't interfere with report output
 	if args.ReportOutput == "" || args.ReportOutput == "-" {
 		fmt.Fprintf(os.Stderr, "\n")
-		standards.PrintSummary(report)
+		standards.PrintSummary(os.Stderr, report)
 	}
 
 	// Exit with error in strict mode if there are violations
@@ -247,41 +258,94 @@ func ValidateStandards(args cli.Args) error {
 	return nil
 }
 
+// autoFix applies the suggested fixes of a report and returns the report of the fixed files.
+// The resources of a plan file can't be re-collected, the report of the plan is returned for them.
+func autoFix(ctx context.Context, args cli.Args, validator *standards.TagValidator, report standards.ValidationReport, matches []string, standard *standards.TagStandard) (standards.ValidationReport, error) {
+	fixResult, err := applySuggestedFixes(report.Results)
+	if err != nil {
+		return report, fmt.Errorf("auto-fix failed: %w", err)
+	}
+
+	printAutoFixSummary(os.Stderr, fixResult)
+
+	if len(fixResult.FixedFiles) == 0 {
+		return report, nil
+	}
+
+	if args.PlanFile != "" {
+		log.Printf("[WARN] Re-run terraform plan and validation to check the fixed files, the report is based on the plan file")
+
+		return report, nil
+	}
+
+	log.Printf("[VALIDATION] Re-validating after auto-fix")
+
+	resources, err := collectResources(ctx, matches, args, standard)
+	if err != nil {
+		return report, fmt.Errorf("failed to collect resources after auto-fix: %w", err)
+	}
+
+	fixedReport := validator.CreateValidationReport(validator.ValidateBatch(resources), args.StandardFile)
+
+	fmt.Fprintf(os.Stderr, "Compliance rate: %.1f%% before auto-fix, %.1f%% after\n\n", report.Summary.ComplianceRate*100, fixedReport.Summary.ComplianceRate*100)
+
+	return fixedReport, nil
+}
+
 // collectResources extracts all resources from terraform files for validation
-func collectResources(filePaths []string, args cli.Args, cloudProvider string) ([]standards.ResourceInfo, error) {
+func collectResources(ctx context.Context, filePaths []string, args cli.Args, standard *standards.TagStandard) ([]standards.ResourceInfo, error) {
 	var resources []standards.ResourceInfo
-	var mu sync.Mutex
-	var wg sync.WaitGroup
+	var paths []string
 
 	for _, path := range filePaths {
 		// Skip previously tagged files if requested
-		if args.IsSkipTerratagFiles && strings.HasSuffix(path, "terratag.tf") {
+		if args.IsSkipTerratagFiles && file.IsTerratagFile(path) {
 			log.Printf("[INFO] Skipping file %s as it's already tagged", path)
 			continue
 		}
 
-		wg.Add(1)
-		go func(filePath string) {
-			defer wg.Done()
+		paths = append(paths, path)
+	}
 
-			fileResources, err := extractResourcesFromFile(filePath, args, cloudProvider)
-			if err != nil {
-				log.Printf("[ERROR] Failed to process file %s: %v", filePath, err)
-				return
-			}
+	selection, err := newResourceSelection(args, true)
+	if err != nil {
+		return nil, err
+	}
+
+	fileResults := utils.ParallelMap(paths, args.Parallelism, func(filePath string) []standards.ResourceInfo {
+		if ctx.Err() != nil {
+			return nil
+		}
 
-			mu.Lock()
-			resources = append(resources, fileResources...)
-			mu.Unlock()
-		}(path)
+		fileResources, err := extractResourcesFromFile(filePath, args, standard, selection)
+		if err != nil {
+			log.Printf("[ERROR] Failed to process file %s: %v", filePath, err)
+			return nil
+		}
+
+		return fileResources
+	})
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Results are in the order of the files, so reports are deterministic
+	for _, fileResources := range fileResults {
+		resources = append(resources, fileResources...)
+	}
+
+	// Attach provider default tags so validation sees the effective tag set
+	providerTags := collectProviderDefaultTags(filePaths, args)
+	for i := range resources {
+		resources[i].ProviderTags = providerTags.resourceTags(resources[i].FilePath, resources[i].Provider)
 	}
 
-	wg.Wait()
 	return resources, nil
 }
 
 // extractResourcesFromFile extracts resources from a single terraform file
-func extractResourcesFromFile(filePath string, args cli.Args, cloudProvider string) ([]standards.ResourceInfo, error) {
+func extractResourcesFromFile(filePath string, args cli.Args, standard *standards.TagStandard, selection *resourceSelection) ([]standards.ResourceInfo, error) {
 	var resources []standards.ResourceInfo
 
 	log.Printf("[INFO] Processing file %s for validation", filePath)
@@ -294,17 +358,17 @@ func extractResourcesFromFile(filePath string, args cli.Args, cloudProvider stri
 
 	// Parse with hcl for position information
 	parser := hclparse.NewParser()
-	hclFile, diags := parser.ParseHCL(content, filePath)
+	var hclFile *hcl.File
+	var diags hcl.Diagnostics
+	if file.IsJSONFile(filePath) {
+		hclFile, diags = parser.ParseJSON(content, filePath)
+	} else {
+		hclFile, diags = parser.ParseHCL(content, filePath)
+	}
 	if diags.HasErrors() {
 		return nil, fmt.Errorf("failed to parse HCL for position tracking: %v", diags)
 	}
 
-	// Parse with hclwrite for tag extraction (existing logic)
-	hclWriteFile, err := file.ReadHCLFile(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read HCL file: %w", err)
-	}
-
 	// Create maps to correlate blocks between parsers
 	blockPositions := make(map[string]blockPos) // key: "resourceType.resourceName"
 
@@ -332,6 +396,23 @@ func extractResourcesFromFile(filePath string, args cli.Args, cloudProvider stri
 		}
 	}
 
+	annotations, err := file.ResourceAnnotations(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource annotations: %w", err)
+	}
+
+	fileResource := selection.fileResource(filePath, args.Dir)
+
+	if file.IsJSONFile(filePath) {
+		return extractResourcesFromJSONFile(filePath, args, standard, selection, blockPositions, annotations, fileResource)
+	}
+
+	// Parse with hclwrite for tag extraction (existing logic)
+	hclWriteFile, err := file.ReadHCLFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HCL file: %w", err)
+	}
+
 	for _, block := range hclWriteFile.Body().Blocks() {
 		if block.Type() != "resource" {
 			continue
@@ -344,33 +425,7 @@ func extractResourcesFromFile(filePath string, args cli.Args, cloudProvider stri
 		resourceType := block.Labels()[0]
 		resourceName := block.Labels()[1]
 
-		// Apply filter if specified
-		if args.Filter != "" {
-			matched, err := regexp.MatchString(args.Filter, resourceType)
-			if err != nil {
-				return nil, fmt.Errorf("invalid filter regex: %w", err)
-			}
-			if !matched {
-				log.Printf("[INFO] Resource %s.%s excluded by filter", resourceType, resourceName)
-				continue
-			}
-		}
-
-		// Apply skip filter if specified
-		if args.Skip != "" {
-			matched, err := regexp.MatchString(args.Skip, resourceType)
-			if err != nil {
-				return nil, fmt.Errorf("invalid skip regex: %w", err)
-			}
-			if matched {
-				log.Printf("[INFO] Resource %s.%s excluded by skip", resourceType, resourceName)
-				continue
-			}
-		}
-
-		// Check if resource supports tagging
-		if !standards.IsTaggableResource(resourceType, cloudProvider) {
-			log.Printf("[INFO] Resource %s.%s is not taggable, skipping", resourceType, resourceName)
+		if !selection.isResourceSelected(fileResource.withLabels(resourceType, resourceName), standard) {
 			continue
 		}
 
@@ -391,12 +446,14 @@ func extractResourcesFromFile(filePath string, args cli.Args, cloudProvider stri
 		pos := blockPositions[resourceKey]
 		
 		resources = append(resources, standards.ResourceInfo{
-			Type:       resourceType,
-			Name:       resourceName,
-			FilePath:   filePath,
-			Tags:       tags,
-			LineNumber: pos.LineNumber,
-			Snippet:    pos.Snippet,
+			Type:        resourceType,
+			Name:        resourceName,
+			FilePath:    filePath,
+			Tags:        tags,
+			LineNumber:  pos.LineNumber,
+			Snippet:     pos.Snippet,
+			Provider:    getResourceProviderAddress(block, resourceType),
+			Suppression: resourceSuppression(annotations, resourceKey),
 		})
 
 		log.Printf("[INFO] Found resource %s.%s with %d tags", resourceType, resourceName, len(tags))
@@ -405,6 +462,175 @@ func extractResourcesFromFile(filePath string, args cli.Args, cloudProvider stri
 	return resources, nil
 }
 
+// extractResourcesFromJSONFile extracts resources from a JSON syntax (.tf.json) terraform file
+func extractResourcesFromJSONFile(filePath string, args cli.Args, standard *standards.TagStandard, selection *resourceSelection, blockPositions map[string]blockPos, annotations map[string]file.Annotation, fileResource fileResource) ([]standards.ResourceInfo, error) {
+	var resources []standards.ResourceInfo
+
+	jsonFile, err := file.ReadJSONFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON file: %w", err)
+	}
+
+	for _, block := range jsonFile.Blocks("resource") {
+		resourceType := block.Labels[0]
+		resourceName := block.Labels[1]
+
+		if !selection.isResourceSelected(fileResource.withLabels(resourceType, resourceName), standard) {
+			continue
+		}
+
+		tags := extractTagsFromJSONResource(block.Body, resourceType)
+
+		pos := blockPositions[resourceType+"."+resourceName]
+
+		resources = append(resources, standards.ResourceInfo{
+			Type:        resourceType,
+			Name:        resourceName,
+			FilePath:    filePath,
+			Tags:        tags,
+			LineNumber:  pos.LineNumber,
+			Snippet:     pos.Snippet,
+			Provider:    getResourceProviderAddress(block.HCLBlock("resource"), resourceType),
+			Suppression: resourceSuppression(annotations, resourceType+"."+resourceName),
+		})
+
+		log.Printf("[INFO] Found resource %s.%s with %d tags", resourceType, resourceName, len(tags))
+	}
+
+	return resources, nil
+}
+
+// resourceSuppression returns what the terratag annotations of a resource exempt from validation, or nil if
+// the resource has no annotations
+func resourceSuppression(annotations map[string]file.Annotation, address string) *standards.Suppression {
+	annotation, ok := annotations[address]
+	if !ok {
+		return nil
+	}
+
+	suppression := &standards.Suppression{
+		Annotation: strings.Join(annotation.Directives, " "),
+		Reason:     annotation.Reason,
+	}
+
+	if !annotation.SkipsValidation() {
+		suppression.IgnoredTags = annotation.IgnoredTags
+	}
+
+	log.Printf("[INFO] Resource %s has the annotation %s", address, suppression.Annotation)
+
+	return suppression
+}
+
+// planResource returns a resource of a plan as the selectors see it
+func planResource(resolved terraform.ResolvedResourceInfo) selector.Resource {
+	resource := selector.FromAddress(resolved.Address)
+	resource.Type = resolved.Type
+	resource.Name = resolved.Name
+
+	return resource
+}
+
+// resourceSelection holds the -filter and -skip selectors and the module addresses they match, parsed and read
+// once per run
+type resourceSelection struct {
+	selectors       *selector.Selectors
+	moduleAddresses map[string][]string // Addresses of the modules instantiating each module directory, keyed by absolute directory
+}
+
+// newResourceSelection parses the selectors of a run, and reads the modules of its directory if the resources
+// are collected from files
+func newResourceSelection(args cli.Args, withModules bool) (*resourceSelection, error) {
+	selectors, err := selector.Parse(args.Filter, args.Skip)
+	if err != nil {
+		return nil, err
+	}
+
+	selection := &resourceSelection{selectors: selectors}
+
+	if withModules {
+		if selection.moduleAddresses, err = terraform.GetModuleAddresses(args.Dir); err != nil {
+			return nil, fmt.Errorf("failed to read the modules of %s: %w", args.Dir, err)
+		}
+	}
+
+	return selection, nil
+}
+
+// fileResource is what the -filter and -skip selectors see of the resources of a file
+type fileResource selector.Resource
+
+// fileResource returns the path of a file relative to dir and the modules instantiating its directory.
+// Files of directories that aren't installed modules are in the root module.
+func (s *resourceSelection) fileResource(filePath string, dir string) fileResource {
+	var resource fileResource
+
+	if relative, err := filepath.Rel(absPath(dir), absPath(filePath)); err == nil {
+		resource.File = filepath.ToSlash(relative)
+	}
+
+	fileDir := absPath(filepath.Dir(filePath))
+	if resolved, err := filepath.EvalSymlinks(fileDir); err == nil {
+		fileDir = resolved
+	}
+
+	resource.Modules = s.moduleAddresses[fileDir]
+
+	return resource
+}
+
+// withLabels returns the resource of a block of the file
+func (f fileResource) withLabels(resourceType, resourceName string) selector.Resource {
+	resource := selector.Resource(f)
+	resource.Type = resourceType
+	resource.Name = resourceName
+
+	return resource
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+
+	return path
+}
+
+// isResourceSelected applies the filter, skip and taggable checks to a resource
+func (s *resourceSelection) isResourceSelected(resource selector.Resource, standard *standards.TagStandard) bool {
+	if selected, reason := s.selectors.Select(resource); !selected {
+		log.Printf("[INFO] Resource %s.%s excluded, %s", resource.Type, resource.Name, reason)
+		return false
+	}
+
+	// Check if resource supports tagging, for the providers of the standard
+	if !standard.IsTaggableResource(resource.Type) {
+		log.Printf("[INFO] Resource %s.%s is not taggable, skipping", resource.Type, resource.Name)
+		return false
+	}
+
+	return true
+}
+
+// extractTagsFromJSONResource extracts the literal tags of a JSON syntax resource body.
+// Tag expressions ("${var.tags}") can't be resolved and are skipped like complex HCL expressions.
+func extractTagsFromJSONResource(body map[string]interface{}, resourceType string) map[string]string {
+	tags := make(map[string]string)
+
+	tagAttrName := providers.GetTagIdByResource(resourceType)
+
+	switch value := body[tagAttrName].(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			tags[k] = fmt.Sprint(v)
+		}
+	case string:
+		log.Printf("[INFO] Complex tag expression detected in %s: %s", tagAttrName, value)
+	}
+
+	return tags
+}
+
 // extractTagsFromResource extracts tags from a terraform resource block
 func extractTagsFromResource(block *hclwrite.Block, resourceType string) (map[string]string, error) {
 	tags := make(map[string]string)
@@ -465,7 +691,7 @@ func ValidateStandardFile(filePath string) error {
 }
 
 // collectResourcesFromPlan extracts resources from a Terraform plan JSON file
-func collectResourcesFromPlan(planPath string, args cli.Args, cloudProvider string) ([]standards.ResourceInfo, error) {
+func collectResourcesFromPlan(planPath string, args cli.Args, standard *standards.TagStandard) ([]standards.ResourceInfo, error) {
 	// Create plan parser
 	planParser := terraform.NewPlanParser(nil)
 	
@@ -478,36 +704,16 @@ func collectResourcesFromPlan(planPath string, args cli.Args, cloudProvider stri
 	// Extract resolved resources
 	resolvedResources := planParser.ExtractResolvedResources(plan)
 	
+	// Plan addresses have the module path, but not the file of the resource
+	selection, err := newResourceSelection(args, false)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert to ResourceInfo format
 	var resources []standards.ResourceInfo
 	for _, resolved := range resolvedResources {
-		// Apply filter if specified
-		if args.Filter != "" {
-			matched, err := regexp.MatchString(args.Filter, resolved.Type)
-			if err != nil {
-				return nil, fmt.Errorf("invalid filter regex: %w", err)
-			}
-			if !matched {
-				log.Printf("[INFO] Resource %s.%s excluded by filter", resolved.Type, resolved.Name)
-				continue
-			}
-		}
-
-		// Apply skip filter if specified
-		if args.Skip != "" {
-			matched, err := regexp.MatchString(args.Skip, resolved.Type)
-			if err != nil {
-				return nil, fmt.Errorf("invalid skip regex: %w", err)
-			}
-			if matched {
-				log.Printf("[INFO] Resource %s.%s excluded by skip", resolved.Type, resolved.Name)
-				continue
-			}
-		}
-
-		// Check if resource supports tagging
-		if !standards.IsTaggableResource(resolved.Type, cloudProvider) {
-			log.Printf("[INFO] Resource %s.%s is not taggable, skipping", resolved.Type, resolved.Name)
+		if !selection.isResourceSelected(planResource(resolved), standard) {
 			continue
 		}
 
@@ -520,6 +726,18 @@ func collectResourcesFromPlan(planPath string, args cli.Args, cloudProvider stri
 			Snippet:    "",                  // Not available from plan
 		}
 
+		// The plan already contains the effective tags (tags_all / terraform_labels),
+		// anything not set on the resource itself was inherited from the provider defaults
+		for key, value := range resolved.EffectiveTags {
+			if _, onResource := resolved.Tags[key]; onResource {
+				continue
+			}
+			if resource.ProviderTags == nil {
+				resource.ProviderTags = make(map[string]string)
+			}
+			resource.ProviderTags[key] = value
+		}
+
 		resources = append(resources, resource)
 		log.Printf("[INFO] Found resource %s.%s with %d resolved tags", resolved.Type, resolved.Name, len(resolved.Tags))
 	}
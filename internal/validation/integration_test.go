@@ -1,14 +1,25 @@
 package validation
 
 import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/cloudyali/terratag/cli"
 	"github.com/cloudyali/terratag/internal/standards"
 )
 
+// awsStandard is the standard of the resources collected by the tests
+var awsStandard = &standards.TagStandard{CloudProvider: "aws"}
+
 func TestValidateStandards(t *testing.T) {
 	// Skip this test as it requires terraform init
 	t.Skip("Skipping test that requires terraform init")
@@ -49,7 +60,7 @@ resource "aws_s3_bucket" "test2" {
 		Skip:                "",
 	}
 
-	resources, err := collectResources([]string{tfFile}, args, "aws")
+	resources, err := collectResources(context.Background(), []string{tfFile}, args, awsStandard)
 	if err != nil {
 		t.Fatalf("collectResources failed: %v", err)
 	}
@@ -105,7 +116,12 @@ resource "local_file" "config" {
 		Skip:   "",
 	}
 
-	resources, err := extractResourcesFromFile(tfFile, args, "aws")
+	selection, err := newResourceSelection(args, false)
+	if err != nil {
+		t.Fatalf("newResourceSelection failed: %v", err)
+	}
+
+	resources, err := extractResourcesFromFile(tfFile, args, awsStandard, selection)
 	if err != nil {
 		t.Fatalf("extractResourcesFromFile failed: %v", err)
 	}
@@ -212,4 +228,464 @@ invalid_yaml: [
 	if err == nil {
 		t.Error("ValidateStandardFile should have failed for invalid file")
 	}
-}
\ No newline at end of file
+}
+func TestCollectResourcesWithProviderDefaultTags(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tfContent := `
+provider "aws" {
+  default_tags {
+    tags = {
+      Environment = "Production"
+      Owner       = "platform"
+    }
+  }
+}
+
+provider "aws" {
+  alias = "west"
+  default_tags {
+    tags = {
+      Environment = "Staging"
+    }
+  }
+}
+
+resource "aws_instance" "default" {
+  ami = "ami-12345"
+  tags = {
+    Owner = "team"
+  }
+}
+
+resource "aws_instance" "west" {
+  provider = aws.west
+  ami      = "ami-12345"
+}
+`
+	tfFile := filepath.Join(tmpDir, "main.tf")
+	if err := os.WriteFile(tfFile, []byte(tfContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	resources, err := collectResources(context.Background(), []string{tfFile}, cli.Args{IsSkipTerratagFiles: true}, awsStandard)
+	if err != nil {
+		t.Fatalf("collectResources failed: %v", err)
+	}
+
+	if len(resources) != 2 {
+		t.Fatalf("Expected 2 resources, got %d", len(resources))
+	}
+
+	byName := make(map[string]standards.ResourceInfo)
+	for _, resource := range resources {
+		byName[resource.Name] = resource
+	}
+
+	if got := byName["default"].ProviderTags["Owner"]; got != "platform" {
+		t.Errorf("Expected default provider Owner tag 'platform', got %q", got)
+	}
+	if got := byName["west"].Provider; got != "aws.west" {
+		t.Errorf("Expected aliased provider 'aws.west', got %q", got)
+	}
+	if got := byName["west"].ProviderTags["Environment"]; got != "Staging" {
+		t.Errorf("Expected aliased provider Environment tag 'Staging', got %q", got)
+	}
+
+	validator, err := standards.NewTagValidator(&standards.TagStandard{
+		Version:       1,
+		CloudProvider: "aws",
+		RequiredTags:  []standards.TagSpec{{Key: "Environment"}, {Key: "Owner"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	results := validator.ValidateBatch([]standards.ResourceInfo{byName["default"]})
+	if len(results) != 1 || !results[0].IsCompliant {
+		t.Fatalf("Expected resource to be compliant through provider default tags, got %+v", results)
+	}
+	if results[0].TagSources["Environment"] != standards.TagSourceProviderDefault {
+		t.Errorf("Expected Environment to come from provider defaults, got %q", results[0].TagSources["Environment"])
+	}
+	if results[0].TagSources["Owner"] != standards.TagSourceResourceOverride {
+		t.Errorf("Expected Owner to be a resource override, got %q", results[0].TagSources["Owner"])
+	}
+}
+
+func TestCollectResourcesWithProviderDefaultTagsPerRootModule(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	provider := func(environment string) string {
+		return "provider \"aws\" {\n  default_tags {\n    tags = {\n      Environment = \"" + environment + "\"\n    }\n  }\n}\n"
+	}
+
+	files := map[string]string{
+		"envs/prod/main.tf": provider("Production") + `
+provider "aws" {
+  alias = "shared"
+  default_tags {
+    tags = {
+      Environment = "Shared"
+    }
+  }
+}
+
+module "network" {
+  source = "../../modules/network"
+}
+
+module "shared" {
+  source    = "../../modules/shared"
+  providers = { aws = aws.shared }
+}
+
+resource "aws_instance" "prod" {
+  ami = "ami-12345"
+}
+`,
+		"envs/dev/main.tf":                          "resource \"aws_instance\" \"dev\" {\n  ami = \"ami-12345\"\n}\n\n" + provider("Development"),
+		"modules/network/main.tf":                   "resource \"aws_vpc\" \"network\" {\n  cidr_block = \"10.0.0.0/16\"\n}\n",
+		"modules/shared/main.tf":                    "resource \"aws_s3_bucket\" \"shared\" {\n  bucket = \"shared\"\n}\n",
+		"envs/prod/.terraform/modules/modules.json": `{"Modules":[{"Key":"","Source":"","Dir":"."},{"Key":"network","Source":"../../modules/network","Dir":"../../modules/network"},{"Key":"shared","Source":"../../modules/shared","Dir":"../../modules/shared"}]}`,
+	}
+
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+		if filepath.Ext(name) == ".tf" {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	resources, err := collectResources(context.Background(), paths, cli.Args{Dir: tmpDir, IsSkipTerratagFiles: true}, awsStandard)
+	if err != nil {
+		t.Fatalf("collectResources failed: %v", err)
+	}
+
+	environments := make(map[string]string)
+	for _, resource := range resources {
+		environments[resource.Name] = resource.ProviderTags["Environment"]
+	}
+
+	expected := map[string]string{
+		"prod":    "Production",
+		"dev":     "Development",
+		"network": "Production",
+		"shared":  "Shared",
+	}
+	for name, environment := range expected {
+		if environments[name] != environment {
+			t.Errorf("Expected %s to inherit Environment %q, got %q", name, environment, environments[name])
+		}
+	}
+}
+
+func TestCollectResourcesFromJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tfJSONContent := `{
+  "provider": {
+    "aws": {
+      "default_tags": {"tags": {"Environment": "Production"}}
+    }
+  },
+  "resource": {
+    "aws_instance": {
+      "web": {"ami": "ami-12345", "tags": {"Name": "web"}}
+    },
+    "aws_s3_bucket": {
+      "logs": {"bucket": "logs", "tags": "${var.tags}"}
+    }
+  }
+}`
+	tfFile := filepath.Join(tmpDir, "main.tf.json")
+	if err := os.WriteFile(tfFile, []byte(tfJSONContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	resources, err := collectResources(context.Background(), []string{tfFile}, cli.Args{IsSkipTerratagFiles: true, Skip: "aws_s3_bucket"}, awsStandard)
+	if err != nil {
+		t.Fatalf("collectResources failed: %v", err)
+	}
+
+	if len(resources) != 1 {
+		t.Fatalf("Expected 1 resource after skip, got %d", len(resources))
+	}
+
+	resource := resources[0]
+	if resource.Type != "aws_instance" || resource.Name != "web" {
+		t.Errorf("Unexpected resource: %+v", resource)
+	}
+	if resource.Tags["Name"] != "web" {
+		t.Errorf("Expected Name tag 'web', got %q", resource.Tags["Name"])
+	}
+	if resource.LineNumber == 0 {
+		t.Error("Expected line number to be set for JSON resource")
+	}
+	if resource.ProviderTags["Environment"] != "Production" {
+		t.Errorf("Expected provider default Environment tag, got %v", resource.ProviderTags)
+	}
+}
+
+func TestCollectResourcesWithTerratagProviderDefaultTags(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  ami = "ami-12345"
+}
+
+resource "google_storage_bucket" "logs" {
+  name = "logs"
+}
+
+provider "aws" {
+  default_tags {
+    tags = merge({
+      "Team" = "storage"
+    }, var.tags, local.terratag_added_main)
+  }
+}
+`,
+		"locals.tf": `locals {
+  terratag_added_main = {"Environment"="Production","Owner"="platform"}
+}
+`,
+		"google.tf.json": `{
+  "provider": {"google": {"default_labels": "${merge(var.labels, local.terratag_added_google)}"}},
+  "locals": {"terratag_added_google": {"environment": "production"}}
+}`,
+		// Generated by -strategy=provider for the providers without a block, it isn't skipped with its tagged copies
+		"terratag_providers.terratag.tf": `provider "aws" {
+  alias = "west"
+  default_tags {
+    tags = local.terratag_added_terratag_providers
+  }
+}
+
+locals {
+  terratag_added_terratag_providers = {"Environment"="Staging"}
+}
+`,
+	}
+
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	defaults := collectProviderDefaultTags(paths, cli.Args{IsSkipTerratagFiles: true})
+
+	tests := []struct {
+		provider string
+		expected map[string]string
+	}{
+		{provider: "aws", expected: map[string]string{"Team": "storage", "Environment": "Production", "Owner": "platform"}},
+		{provider: "google", expected: map[string]string{"environment": "production"}},
+		{provider: "aws.west", expected: map[string]string{"Environment": "Staging"}},
+	}
+
+	for _, tt := range tests {
+		got := defaults.resourceTags(filepath.Join(tmpDir, "main.tf"), tt.provider)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Expected default tags %v for provider %s, got %v", tt.expected, tt.provider, got)
+		}
+	}
+}
+
+func TestCollectResourcesWithAnnotations(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tfContent := `
+# terratag:ignore reason="managed outside of this repository"
+resource "aws_instance" "ignored" {
+  ami = "ami-12345"
+}
+
+# terratag:ignore=Owner
+resource "aws_instance" "shared" {
+  ami = "ami-12345"
+}
+
+resource "aws_instance" "web" {
+  ami = "ami-12345"
+}
+`
+	tfFile := filepath.Join(tmpDir, "main.tf")
+	if err := os.WriteFile(tfFile, []byte(tfContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	resources, err := collectResources(context.Background(), []string{tfFile}, cli.Args{IsSkipTerratagFiles: true}, awsStandard)
+	if err != nil {
+		t.Fatalf("collectResources failed: %v", err)
+	}
+
+	suppressions := map[string]*standards.Suppression{}
+	for _, resource := range resources {
+		suppressions[resource.Name] = resource.Suppression
+	}
+
+	if len(suppressions) != 3 {
+		t.Fatalf("Expected 3 resources, got %d", len(resources))
+	}
+	if s := suppressions["ignored"]; !s.SuppressesResource() || s.Reason != "managed outside of this repository" {
+		t.Errorf("Expected ignored to be suppressed with its reason, got %+v", s)
+	}
+	if s := suppressions["shared"]; s == nil || s.SuppressesResource() || len(s.IgnoredTags) != 1 || s.IgnoredTags[0] != "Owner" {
+		t.Errorf("Expected shared to ignore its Owner tag, got %+v", s)
+	}
+	if s := suppressions["web"]; s != nil {
+		t.Errorf("Expected web not to be suppressed, got %+v", s)
+	}
+}
+
+func TestCollectResourcesWithSelectors(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"main.tf":                 "resource \"aws_instance\" \"web\" {\n  ami = \"ami-12345\"\n}\n",
+		"generated/buckets.tf":    "resource \"aws_s3_bucket\" \"generated\" {\n  bucket = \"generated\"\n}\n",
+		"modules/network/main.tf": "resource \"aws_vpc\" \"main\" {\n  cidr_block = \"10.0.0.0/16\"\n}\n",
+		".terraform/modules/modules.json": `{"Modules":[{"Key":"","Source":"","Dir":"."},{"Key":"network","Source":"./modules/network","Dir":"modules/network"}]}`,
+	}
+
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+		if filepath.Ext(name) == ".tf" {
+			paths = append(paths, path)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		filter   string
+		skip     string
+		expected []string
+	}{
+		{name: "skip a module", skip: "module:module.network", expected: []string{"generated", "web"}},
+		{name: "skip a file glob", skip: "file:generated/**", expected: []string{"main", "web"}},
+		{name: "filter by address", filter: `address:^module\.network\.`, expected: []string{"main"}},
+		{name: "filter by name", filter: "name:^web$", expected: []string{"web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := collectResources(context.Background(), paths, cli.Args{Dir: tmpDir, Filter: tt.filter, Skip: tt.skip}, awsStandard)
+			if err != nil {
+				t.Fatalf("collectResources failed: %v", err)
+			}
+
+			var names []string
+			for _, resource := range resources {
+				names = append(names, resource.Name)
+			}
+			sort.Strings(names)
+
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected resources %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestValidateStandardsReportToStdout(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"main.tf":       "resource \"aws_s3_bucket\" \"logs\" {\n  bucket = \"logs\"\n}\n",
+		"standard.yaml": "version: 1\nmetadata:\n  description: \"Stdout test standard\"\ncloud_provider: \"aws\"\nrequired_tags:\n  - key: \"Owner\"\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(tmpDir, ".terraform"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	tests := []struct {
+		format string
+		parse  func([]byte) error
+	}{
+		{format: "sarif", parse: func(data []byte) error { return json.Unmarshal(data, &map[string]interface{}{}) }},
+		{format: "json", parse: func(data []byte) error { return json.Unmarshal(data, &map[string]interface{}{}) }},
+		{format: "junit", parse: func(data []byte) error { return xml.Unmarshal(data, &struct{}{}) }},
+		{format: "html", parse: func(data []byte) error {
+			if !strings.HasSuffix(strings.TrimSpace(string(data)), "</html>") {
+				return fmt.Errorf("output doesn't end with the HTML document")
+			}
+			return nil
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			args := cli.Args{
+				ValidateOnly: true,
+				StandardFile: filepath.Join(tmpDir, "standard.yaml"),
+				Dir:          tmpDir,
+				Type:         "terraform",
+				ReportFormat: tt.format,
+			}
+
+			stdout := captureStdout(t, func() {
+				if err := ValidateStandards(context.Background(), args); err != nil {
+					t.Errorf("ValidateStandards failed: %v", err)
+				}
+			})
+
+			if strings.Contains(string(stdout), "Tag Compliance Summary") {
+				t.Errorf("Expected the summary not to be written to stdout")
+			}
+			if err := tt.parse(stdout); err != nil {
+				t.Errorf("Failed to parse the %s report written to stdout: %v\n%s", tt.format, err, stdout)
+			}
+		})
+	}
+}
+
+// captureStdout returns what f writes to stdout
+func captureStdout(t *testing.T, f func()) []byte {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- data
+	}()
+
+	f()
+	w.Close()
+
+	return <-output
+}
@@ -0,0 +1,534 @@
+package validation
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"log"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/cloudyali/terratag/cli"
+	"github.com/cloudyali/terratag/internal/file"
+	hclutil "github.com/cloudyali/terratag/internal/hcl"
+	"github.com/cloudyali/terratag/internal/terraform"
+)
+
+// providerDefaultTags holds the provider default tags of the directories of the validated files, and the module
+// calls resources of child modules inherit their provider configurations through
+type providerDefaultTags struct {
+	byDir   map[string]map[string]map[string]string // Directory -> provider address -> default tags
+	modules map[string][]moduleInstance             // Child module directory -> instances, from the modules.json of the roots
+	dirs    map[string]map[string]string            // Root directory -> module address -> module directory
+	calls   map[string]map[string]moduleProviders   // Directory -> module call name -> providers argument
+}
+
+// moduleInstance is a module address of a root module, e.g. module.network.module.subnets
+type moduleInstance struct {
+	root    string
+	address string
+}
+
+// moduleProviders maps the provider addresses of a child module to the ones of its caller, nil if the module
+// call has no providers argument and inherits the default provider configurations
+type moduleProviders map[string]string
+
+// providerConfig is a configuration file read for the default tags of its provider blocks
+type providerConfig struct {
+	path     string
+	dir      string
+	hclFile  *hclwrite.File
+	jsonFile *file.JSONFile
+}
+
+// localTags are the locals of a directory whose values are maps, e.g. the terratag_added_* locals, by name
+type localTags map[string]map[string]string
+
+// collectProviderDefaultTags extracts provider level default tags (AWS default_tags, Google default_labels)
+// from the provider blocks of every directory, keyed by provider address ("aws", "aws.west", "google-beta").
+// Default tags may be maps, locals whose values are maps, such as the terratag_added_* locals of
+// -strategy=provider, or merges of them. Computed default tags are skipped.
+func collectProviderDefaultTags(filePaths []string, args cli.Args) *providerDefaultTags {
+	defaults := &providerDefaultTags{
+		byDir:   make(map[string]map[string]map[string]string),
+		modules: make(map[string][]moduleInstance),
+		dirs:    make(map[string]map[string]string),
+		calls:   make(map[string]map[string]moduleProviders),
+	}
+
+	var configs []providerConfig
+
+	locals := make(map[string]localTags)
+
+	for _, path := range filePaths {
+		if args.IsSkipTerratagFiles && isTaggedCopy(path, filePaths) {
+			continue
+		}
+
+		config := providerConfig{path: path, dir: resolvedDir(path)}
+		if defaults.byDir[config.dir] == nil {
+			defaults.byDir[config.dir] = make(map[string]map[string]string)
+			defaults.addModules(config.dir)
+			locals[config.dir] = make(localTags)
+		}
+
+		var err error
+		if file.IsJSONFile(path) {
+			config.jsonFile, err = file.ReadJSONFile(path)
+		} else {
+			config.hclFile, err = file.ReadHCLFile(path)
+		}
+
+		if err != nil {
+			log.Printf("[WARN] Failed to read %s for provider default tags: %v", path, err)
+			continue
+		}
+
+		// Locals are shared by the files of a module, a provider block may use the locals of another file
+		collectLocalTags(config, locals[config.dir])
+
+		configs = append(configs, config)
+	}
+
+	for _, config := range configs {
+		if config.jsonFile != nil {
+			collectJSONProviderDefaultTags(config, defaults.byDir[config.dir], locals[config.dir])
+			continue
+		}
+
+		providerTags := defaults.byDir[config.dir]
+
+		for _, block := range config.hclFile.Body().Blocks() {
+			if block.Type() != "provider" || len(block.Labels()) == 0 {
+				continue
+			}
+
+			address := getProviderBlockAddress(block)
+			if _, exists := providerTags[address]; exists {
+				log.Printf("[WARN] Provider %s configured more than once in %s, using the first default tags found", address, config.dir)
+				continue
+			}
+
+			tags, err := extractProviderDefaultTags(block, locals[config.dir])
+			if err != nil {
+				log.Printf("[INFO] Skipping default tags of provider %s in %s: %v", address, config.path, err)
+				continue
+			}
+
+			if len(tags) > 0 {
+				log.Printf("[VALIDATION] Found %d default tags on provider %s", len(tags), address)
+				providerTags[address] = tags
+			}
+		}
+	}
+
+	return defaults
+}
+
+// isTaggedCopy returns true if path is a file generated by terratag whose original is validated too, e.g. the
+// main.terratag.tf of main.tf. Generated files without an original, such as the providers terratag creates with
+// -strategy=provider, configure the providers of their directory.
+func isTaggedCopy(path string, filePaths []string) bool {
+	if !file.IsTerratagFile(path) {
+		return false
+	}
+
+	original := strings.Replace(path, ".terratag.tf", ".tf", 1)
+
+	return slices.Contains(filePaths, original)
+}
+
+// collectLocalTags adds the locals of a configuration file whose values are maps
+func collectLocalTags(config providerConfig, locals localTags) {
+	if config.jsonFile != nil {
+		for _, block := range config.jsonFile.Blocks("locals") {
+			for name, value := range block.Body {
+				if literal, ok := value.(map[string]interface{}); ok {
+					locals[name] = jsonTags(literal)
+				}
+			}
+		}
+
+		return
+	}
+
+	for _, block := range config.hclFile.Body().Blocks() {
+		if block.Type() != "locals" {
+			continue
+		}
+
+		for name, attr := range block.Body().Attributes() {
+			tokens := attr.Expr().BuildTokens(nil)
+
+			if _, ok := parseExpression(tokens.Bytes()).(*hclsyntax.ObjectConsExpr); !ok {
+				continue
+			}
+
+			if tags, err := hclutil.ParseHclMapToStringMap(tokens); err == nil {
+				locals[name] = tags
+			}
+		}
+	}
+}
+
+// addModules records the child modules installed for dir, if dir is an initialized root module
+func (p *providerDefaultTags) addModules(dir string) {
+	moduleAddresses, err := terraform.GetModuleAddresses(dir)
+	if err != nil {
+		log.Printf("[WARN] Failed to read the modules of %s for provider default tags: %v", dir, err)
+		return
+	}
+
+	for moduleDir, addresses := range moduleAddresses {
+		for _, address := range addresses {
+			if p.dirs[dir] == nil {
+				p.dirs[dir] = make(map[string]string)
+			}
+
+			p.dirs[dir][address] = moduleDir
+			p.modules[moduleDir] = append(p.modules[moduleDir], moduleInstance{root: dir, address: address})
+		}
+	}
+}
+
+// resourceTags returns the default tags of the provider configuration a resource of a file is created with.
+// Resources of root modules use the provider blocks of their directory. Resources of child modules use the
+// provider blocks of their root module, through the providers argument of every module call on the way.
+// Modules instantiated more than once are resolved through their first instance.
+func (p *providerDefaultTags) resourceTags(filePath string, provider string) map[string]string {
+	dir := resolvedDir(filePath)
+
+	instances := p.modules[dir]
+	if len(instances) == 0 || slices.ContainsFunc(instances, func(instance moduleInstance) bool { return instance.address == "" }) {
+		return p.byDir[dir][provider]
+	}
+
+	instance := slices.MinFunc(instances, func(a, b moduleInstance) int {
+		return cmp.Or(cmp.Compare(a.root, b.root), cmp.Compare(a.address, b.address))
+	})
+
+	for address := instance.address; address != ""; {
+		var name string
+
+		if i := strings.LastIndex(address, ".module."); i >= 0 {
+			address, name = address[:i], address[i+len(".module."):]
+		} else {
+			address, name = "", strings.TrimPrefix(address, "module.")
+		}
+
+		callerDir := instance.root
+		if address != "" {
+			callerDir = p.dirs[instance.root][address]
+		}
+
+		if provider = p.callerProvider(callerDir, name, provider); provider == "" {
+			return nil
+		}
+	}
+
+	return p.byDir[instance.root][provider]
+}
+
+// callerProvider returns the provider address of the caller passed as provider to a module call, empty if
+// the module doesn't get that provider configuration
+func (p *providerDefaultTags) callerProvider(callerDir string, name string, provider string) string {
+	if p.calls[callerDir] == nil {
+		p.calls[callerDir] = readModuleProviders(callerDir)
+	}
+
+	providers, ok := p.calls[callerDir][name]
+	if ok && providers != nil {
+		return providers[provider]
+	}
+
+	// Only default provider configurations are inherited, aliased ones must be passed explicitly
+	if strings.Contains(provider, ".") {
+		return ""
+	}
+
+	return provider
+}
+
+// readModuleProviders returns the providers argument of the module calls of the configuration files of dir
+func readModuleProviders(dir string) map[string]moduleProviders {
+	calls := make(map[string]moduleProviders)
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.tf"))
+	jsonPaths, _ := filepath.Glob(filepath.Join(dir, "*.tf.json"))
+
+	for _, path := range append(paths, jsonPaths...) {
+		if file.IsJSONFile(path) {
+			jsonFile, err := file.ReadJSONFile(path)
+			if err != nil {
+				log.Printf("[WARN] Failed to read %s for module providers: %v", path, err)
+				continue
+			}
+
+			for _, block := range jsonFile.Blocks("module") {
+				literal, ok := block.Body["providers"].(map[string]interface{})
+				if !ok {
+					calls[block.Labels[0]] = nil
+					continue
+				}
+
+				providers := make(moduleProviders, len(literal))
+				for child, caller := range literal {
+					providers[child] = strings.TrimSuffix(strings.TrimPrefix(fmt.Sprint(caller), "${"), "}")
+				}
+				calls[block.Labels[0]] = providers
+			}
+
+			continue
+		}
+
+		hclFile, err := file.ReadHCLFile(path)
+		if err != nil {
+			log.Printf("[WARN] Failed to read %s for module providers: %v", path, err)
+			continue
+		}
+
+		for _, block := range hclFile.Body().Blocks() {
+			if block.Type() != "module" || len(block.Labels()) == 0 {
+				continue
+			}
+
+			calls[block.Labels()[0]] = parseModuleProviders(block.Body().GetAttribute("providers"))
+		}
+	}
+
+	return calls
+}
+
+// parseModuleProviders parses the providers argument of a module block, e.g. { aws = aws.west }
+func parseModuleProviders(attr *hclwrite.Attribute) moduleProviders {
+	if attr == nil {
+		return nil
+	}
+
+	providers := make(moduleProviders)
+
+	expr, diags := hclsyntax.ParseExpression(attr.Expr().BuildTokens(nil).Bytes(), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return providers
+	}
+
+	object, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return providers
+	}
+
+	for _, item := range object.Items {
+		child, childDiags := hcl.AbsTraversalForExpr(item.KeyExpr)
+		caller, callerDiags := hcl.AbsTraversalForExpr(item.ValueExpr)
+		if childDiags.HasErrors() || callerDiags.HasErrors() {
+			continue
+		}
+
+		providers[traversalAddress(child)] = traversalAddress(caller)
+	}
+
+	return providers
+}
+
+// traversalAddress returns the address of a provider reference, e.g. aws.west
+func traversalAddress(traversal hcl.Traversal) string {
+	parts := []string{traversal.RootName()}
+	for _, step := range traversal[1:] {
+		if attr, ok := step.(hcl.TraverseAttr); ok {
+			parts = append(parts, attr.Name)
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// resolvedDir returns the absolute directory of a file, with symlinks resolved like the module directories
+func resolvedDir(path string) string {
+	dir := absPath(filepath.Dir(path))
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+
+	return dir
+}
+
+// collectJSONProviderDefaultTags adds the default tags of the provider blocks of a JSON syntax (.tf.json) file
+func collectJSONProviderDefaultTags(config providerConfig, providerTags map[string]map[string]string, locals localTags) {
+	for _, block := range config.jsonFile.Blocks("provider") {
+		address := block.Labels[0]
+		if alias, ok := block.Body["alias"].(string); ok && alias != "" {
+			address += "." + alias
+		}
+
+		if _, exists := providerTags[address]; exists {
+			log.Printf("[WARN] Provider %s configured more than once in %s, using the first default tags found", address, config.dir)
+			continue
+		}
+
+		var defaultTags interface{}
+		switch block.Labels[0] {
+		case "aws":
+			switch nested := block.Body["default_tags"].(type) {
+			case map[string]interface{}:
+				defaultTags = nested["tags"]
+			case []interface{}:
+				if len(nested) > 0 {
+					if obj, ok := nested[0].(map[string]interface{}); ok {
+						defaultTags = obj["tags"]
+					}
+				}
+			}
+		case "google", "google-beta":
+			defaultTags = block.Body["default_labels"]
+		}
+
+		var tags map[string]string
+
+		switch value := defaultTags.(type) {
+		case map[string]interface{}:
+			tags = jsonTags(value)
+		case string:
+			// Expressions are "${...}" templates, e.g. "${merge(var.tags, local.terratag_added_main)}"
+			var err error
+			if tags, err = resolveDefaultTags([]byte(value), true, locals); err != nil {
+				log.Printf("[INFO] Skipping default tags of provider %s in %s: %v", address, config.path, err)
+				continue
+			}
+		}
+
+		if len(tags) == 0 {
+			continue
+		}
+
+		log.Printf("[VALIDATION] Found %d default tags on provider %s", len(tags), address)
+		providerTags[address] = tags
+	}
+}
+
+// extractProviderDefaultTags returns the default tags of a provider block
+func extractProviderDefaultTags(block *hclwrite.Block, locals localTags) (map[string]string, error) {
+	var attr *hclwrite.Attribute
+
+	switch block.Labels()[0] {
+	case "aws":
+		defaultTags := block.Body().FirstMatchingBlock("default_tags", nil)
+		if defaultTags == nil {
+			return nil, nil
+		}
+		attr = defaultTags.Body().GetAttribute("tags")
+	case "google", "google-beta":
+		attr = block.Body().GetAttribute("default_labels")
+	}
+
+	if attr == nil {
+		return nil, nil
+	}
+
+	return resolveDefaultTags(attr.Expr().BuildTokens(nil).Bytes(), false, locals)
+}
+
+// resolveDefaultTags returns the tags of a default tags expression: a map, a local whose value is a map, or a merge
+// of them. The arguments of a merge that can't be resolved, e.g. var.tags, are skipped. template is set for the
+// "${...}" expressions of JSON syntax files.
+func resolveDefaultTags(src []byte, template bool, locals localTags) (map[string]string, error) {
+	var expr hclsyntax.Expression
+	if template {
+		expr, _ = hclsyntax.ParseTemplate(src, "", hcl.InitialPos)
+	} else {
+		expr = parseExpression(src)
+	}
+
+	if wrap, ok := expr.(*hclsyntax.TemplateWrapExpr); ok {
+		expr = wrap.Wrapped
+	}
+
+	switch e := expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		// The map is parsed from hclwrite tokens, like the tags of resources
+		parsed, diags := hclwrite.ParseConfig(append([]byte("tags = "), e.Range().SliceBytes(src)...), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		return hclutil.ParseHclMapToStringMap(parsed.Body().GetAttribute("tags").Expr().BuildTokens(nil))
+	case *hclsyntax.ScopeTraversalExpr:
+		if len(e.Traversal) == 2 && e.Traversal.RootName() == "local" {
+			if attr, ok := e.Traversal[1].(hcl.TraverseAttr); ok && locals[attr.Name] != nil {
+				return maps.Clone(locals[attr.Name]), nil
+			}
+		}
+	case *hclsyntax.FunctionCallExpr:
+		if e.Name == "merge" {
+			tags := make(map[string]string)
+
+			for _, arg := range e.Args {
+				argTags, err := resolveDefaultTags(arg.Range().SliceBytes(src), false, locals)
+				if err != nil {
+					log.Printf("[INFO] Skipping the computed default tags %s of a merge: %v", arg.Range().SliceBytes(src), err)
+					continue
+				}
+
+				maps.Copy(tags, argTags)
+			}
+
+			return tags, nil
+		}
+	}
+
+	return nil, fmt.Errorf("computed default tags %s", bytes.TrimSpace(src))
+}
+
+// parseExpression parses a native syntax expression, nil if it isn't valid
+func parseExpression(src []byte) hclsyntax.Expression {
+	expr, diags := hclsyntax.ParseExpression(src, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil
+	}
+
+	return expr
+}
+
+// jsonTags returns the tags of a JSON syntax map
+func jsonTags(literal map[string]interface{}) map[string]string {
+	tags := make(map[string]string, len(literal))
+	for key, value := range literal {
+		tags[key] = fmt.Sprint(value)
+	}
+
+	return tags
+}
+
+// getProviderBlockAddress returns the address resources use to reference a provider block
+func getProviderBlockAddress(block *hclwrite.Block) string {
+	name := block.Labels()[0]
+
+	if aliasAttr := block.Body().GetAttribute("alias"); aliasAttr != nil {
+		alias := strings.Trim(strings.TrimSpace(string(aliasAttr.Expr().BuildTokens(nil).Bytes())), "\"")
+		if alias != "" {
+			return name + "." + alias
+		}
+	}
+
+	return name
+}
+
+// getResourceProviderAddress returns the provider address a resource is configured with,
+// honouring the 'provider' meta-argument and defaulting to the resource type prefix
+func getResourceProviderAddress(block *hclwrite.Block, resourceType string) string {
+	if providerAttr := block.Body().GetAttribute("provider"); providerAttr != nil {
+		address := strings.Trim(strings.TrimSpace(string(providerAttr.Expr().BuildTokens(nil).Bytes())), "\"")
+		if address != "" {
+			return address
+		}
+	}
+
+	name, _, _ := strings.Cut(resourceType, "_")
+
+	return name
+}
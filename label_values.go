@@ -0,0 +1,105 @@
+package terratag
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/cloudyali/terratag/internal/common"
+	"github.com/cloudyali/terratag/internal/providers"
+)
+
+// labelTagSetName is the name of the tag set of the standard tags sanitized into Google label values
+const labelTagSetName = "labels"
+
+// resourceLabelTagSet returns the tag set of a resource type with its values sanitized into valid Google label
+// values, when the tag standard sanitizes label values and the resources of the type are labelled
+func resourceLabelTagSet(tagSet *common.TagSet, resourceType string, args *common.TaggingArgs) (*common.TagSet, error) {
+	return labelTagSet(tagSet, providers.UsesLabels(resourceType, args.CloudProvider), args)
+}
+
+// providerTagSet returns the tag set of the default tags of a provider, the standard tags (or the tags of the cloud
+// provider with a multi-cloud standard) with their values sanitized into valid label values for the Google providers
+// when the tag standard sanitizes label values
+func providerTagSet(providerName string, args *common.TaggingArgs) (*common.TagSet, error) {
+	tagSet := &common.TagSet{Tags: args.Tags, TagExpressions: args.TagExpressions}
+
+	if resolver, ok := args.TagSets.(common.ProviderTagSetResolver); ok {
+		resolved, err := resolver.ResolveProviderTagSet(providers.ProviderBlockProvider(providerName))
+		if err != nil {
+			return nil, err
+		}
+
+		if resolved != nil {
+			tagSet = resolved
+		}
+	}
+
+	return labelTagSet(tagSet, strings.HasPrefix(providerName, "google"), args)
+}
+
+// labelTagSet returns a tag set with its values sanitized into valid Google label values. Expression values are
+// wrapped in the HCL functions applying the same transformation when Terraform evaluates them. Tag sets that aren't
+// labels, or whose values are already valid label values, are returned as is. The standard tags are renamed, so
+// that they don't share the terratag_added_* local of the other resources of the file.
+func labelTagSet(tagSet *common.TagSet, labelled bool, args *common.TaggingArgs) (*common.TagSet, error) {
+	if tagSet == nil || !labelled || args.LabelSanitizer == nil {
+		return tagSet, nil
+	}
+
+	tags, changed, err := sanitizeLabelValues(tagSet.Tags, tagSet.TagExpressions, args.LabelSanitizer)
+	if err != nil || !changed {
+		return tagSet, err
+	}
+
+	labels := *tagSet
+	labels.Tags = tags
+
+	if labels.Name == "" {
+		labels.Name = labelTagSetName
+	}
+
+	return &labels, nil
+}
+
+// sanitizeLabelValues returns the JSON tags with their values sanitized into label values, and whether any value
+// changed. Collisions, where different values of a key are sanitized to the same label value, are logged.
+func sanitizeLabelValues(tags string, expressions map[string]bool, sanitizer common.LabelSanitizer) (string, bool, error) {
+	tagsMap, err := toTagsMap(tags)
+	if err != nil {
+		return "", false, err
+	}
+
+	changed := false
+
+	for key, value := range tagsMap {
+		if expressions[key] {
+			tagsMap[key] = providers.SanitizeLabelValueExpression(value)
+			changed = true
+
+			continue
+		}
+
+		label, collision := sanitizer.SanitizeLabelValue(key, value)
+		if collision != "" {
+			log.Printf("[WARN] Values '%s' and '%s' of tag '%s' are both sanitized to the label value '%s'", collision, value, key, label)
+		}
+
+		if label != value {
+			tagsMap[key] = label
+			changed = true
+		}
+	}
+
+	if !changed {
+		return tags, false, nil
+	}
+
+	tagsJSON, err := json.Marshal(tagsMap)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to marshal label values to JSON: %w", err)
+	}
+
+	return string(tagsJSON), true, nil
+}
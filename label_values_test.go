@@ -0,0 +1,66 @@
+package terratag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudyali/terratag/internal/common"
+	"github.com/cloudyali/terratag/internal/providers"
+)
+
+func TestResourceLabelTagSet(t *testing.T) {
+	args := &common.TaggingArgs{
+		Tags:           `{"Environment":"prod","Owner":"Platform Team","CostCenter":"var.cost_center"}`,
+		TagExpressions: map[string]bool{"CostCenter": true},
+		LabelSanitizer: providers.NewLabelValueSanitizer(),
+	}
+
+	labels, err := resolveTagSet(args, "google_storage_bucket")
+	require.NoError(t, err)
+
+	assert.Equal(t, labelTagSetName, labels.Name)
+	assert.JSONEq(t, `{"Environment":"prod","Owner":"platform_team","CostCenter":"substr(replace(lower(var.cost_center), \"/[^a-z0-9_-]/\", \"_\"), 0, 63)"}`, labels.Tags)
+	assert.Equal(t, args.TagExpressions, labels.TagExpressions)
+
+	tags, err := resolveTagSet(args, "aws_s3_bucket")
+	require.NoError(t, err)
+	assert.Empty(t, tags.Name)
+	assert.Equal(t, args.Tags, tags.Tags, "tags of other providers are kept as is")
+
+	ruleTagSet := &common.TagSet{Name: "google_compute_instance", Tags: `{"Owner":"Compute Team"}`}
+	labels, err = resourceLabelTagSet(ruleTagSet, "google_compute_instance", args)
+	require.NoError(t, err)
+	assert.Equal(t, "google_compute_instance", labels.Name)
+	assert.JSONEq(t, `{"Owner":"compute_team"}`, labels.Tags)
+
+	validTagSet := &common.TagSet{Tags: `{"owner":"platform_team"}`}
+	labels, err = resourceLabelTagSet(validTagSet, "google_compute_instance", args)
+	require.NoError(t, err)
+	assert.Same(t, validTagSet, labels, "valid label values are kept in the standard tag set")
+
+	args.LabelSanitizer = nil
+	tags, err = resolveTagSet(args, "google_storage_bucket")
+	require.NoError(t, err)
+	assert.Equal(t, args.Tags, tags.Tags, "label values are only sanitized when enabled")
+}
+
+func TestProviderTagSet(t *testing.T) {
+	args := &common.TaggingArgs{
+		Tags:           `{"Owner":"Platform Team"}`,
+		LabelSanitizer: providers.NewLabelValueSanitizer(),
+	}
+
+	for _, providerName := range []string{"google", "google-beta"} {
+		labels, err := providerTagSet(providerName, args)
+		require.NoError(t, err)
+		assert.Equal(t, labelTagSetName, labels.Name)
+		assert.JSONEq(t, `{"Owner":"platform_team"}`, labels.Tags)
+	}
+
+	tags, err := providerTagSet("aws", args)
+	require.NoError(t, err)
+	assert.Empty(t, tags.Name)
+	assert.Equal(t, args.Tags, tags.Tags)
+}
@@ -0,0 +1,457 @@
+package terratag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cloudyali/terratag/cli"
+	"github.com/cloudyali/terratag/internal/common"
+	"github.com/cloudyali/terratag/internal/convert"
+	"github.com/cloudyali/terratag/internal/file"
+	"github.com/cloudyali/terratag/internal/providers"
+	"github.com/cloudyali/terratag/internal/selector"
+	"github.com/cloudyali/terratag/internal/tagging"
+	"github.com/cloudyali/terratag/internal/terraform"
+	"github.com/cloudyali/terratag/internal/utils"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"gopkg.in/yaml.v3"
+)
+
+const terratagFoundPrefix = "terratag_found_"
+
+// tagKeyMigrationsFile is the mapping file of the -migrate mode, e.g.
+//
+//	rename:
+//	  CostCentre: CostCenter
+//	remove:
+//	  - LegacyOwner
+type tagKeyMigrationsFile struct {
+	Rename map[string]string `yaml:"rename"`
+	Remove []string          `yaml:"remove"`
+}
+
+// migrationCounters are the tag key changes of a migration run
+type migrationCounters struct {
+	totalFiles    int
+	migratedFiles int
+	renamed       int
+	removed       int
+	unresolved    int
+	failedFiles   []string
+	diffs         []file.FileDiff
+}
+
+func (c *migrationCounters) Add(other migrationCounters) {
+	c.totalFiles += other.totalFiles
+	c.migratedFiles += other.migratedFiles
+	c.renamed += other.renamed
+	c.removed += other.removed
+	c.unresolved += other.unresolved
+	c.failedFiles = append(c.failedFiles, other.failedFiles...)
+	c.diffs = append(c.diffs, other.diffs...)
+}
+
+// record logs the changes made to the tags of a block and counts them
+func (c *migrationCounters) record(block string, location string, changes *convert.TagKeyChanges) {
+	if changes.Changed() {
+		log.Printf("[INFO] Migrated tag keys of %s: renamed %v, removed %v", block, changes.Renamed, changes.Removed)
+	}
+
+	for _, unresolved := range changes.Unresolved {
+		log.Printf("[WARN] %s %s: %s is computed and was not rewritten, it may contain keys to migrate", location, block, unresolved)
+	}
+
+	c.renamed += len(changes.Renamed)
+	c.removed += len(changes.Removed)
+	c.unresolved += len(changes.Unresolved)
+}
+
+// loadTagKeyMigrations reads a tag key mapping file
+func loadTagKeyMigrations(path string) (convert.TagKeyMigrations, error) {
+	migrations := convert.TagKeyMigrations{
+		Renames:  map[string]string{},
+		Removals: map[string]bool{},
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return migrations, fmt.Errorf("failed to read tag key mapping file %s: %w", path, err)
+	}
+
+	var mapping tagKeyMigrationsFile
+	if err := yaml.Unmarshal(data, &mapping); err != nil {
+		return migrations, fmt.Errorf("failed to parse tag key mapping file %s: %w", path, err)
+	}
+
+	if len(mapping.Rename) == 0 && len(mapping.Remove) == 0 {
+		return migrations, fmt.Errorf("tag key mapping file %s has no rename or remove entries", path)
+	}
+
+	for _, key := range mapping.Remove {
+		if key == "" {
+			return migrations, errors.New("tag keys to remove must not be empty")
+		}
+
+		migrations.Removals[key] = true
+	}
+
+	for oldKey, newKey := range mapping.Rename {
+		if oldKey == "" || newKey == "" {
+			return migrations, errors.New("tag keys to rename must not be empty")
+		}
+
+		if migrations.Removals[oldKey] {
+			return migrations, fmt.Errorf("tag key %s is both renamed and removed", oldKey)
+		}
+
+		if migrations.Removals[newKey] {
+			return migrations, fmt.Errorf("tag key %s is renamed to %s, which is removed", oldKey, newKey)
+		}
+
+		if _, ok := mapping.Rename[newKey]; ok {
+			return migrations, fmt.Errorf("tag key %s is renamed to %s, which is renamed itself", oldKey, newKey)
+		}
+
+		migrations.Renames[oldKey] = newKey
+	}
+
+	return migrations, nil
+}
+
+// migrateTagKeys renames and removes tag keys in the existing tags of the files of args.Dir.
+// Files are rewritten in place, previously tagged .terratag.tf files included, since their terratag_found_*
+// locals hold the original tags of the resources.
+func migrateTagKeys(ctx context.Context, args cli.Args) error {
+	migrations, err := loadTagKeyMigrations(args.Migrate)
+	if err != nil {
+		return err
+	}
+
+	matches, err := terraform.GetFilePaths(args.Dir, args.Type)
+	if err != nil {
+		return err
+	}
+
+	moduleAddresses, err := terraform.GetModuleAddresses(args.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to read the modules of %s: %w", args.Dir, err)
+	}
+
+	selectors, err := selector.Parse(args.Filter, args.Skip)
+	if err != nil {
+		return err
+	}
+
+	taggingArgs := &common.TaggingArgs{
+		Filter:          args.Filter,
+		Skip:            args.Skip,
+		Selectors:       selectors,
+		Dir:             args.Dir,
+		DryRun:          args.DryRun,
+		ModuleAddresses: moduleAddresses,
+	}
+
+	results := utils.ParallelMap(matches, args.Parallelism, func(path string) migrationCounters {
+		if ctx.Err() != nil {
+			return migrationCounters{}
+		}
+
+		return migrateFile(path, migrations, taggingArgs)
+	})
+
+	var total migrationCounters
+
+	for _, perFile := range results {
+		total.Add(perFile)
+	}
+
+	if err := ctx.Err(); err != nil {
+		log.Print("[WARN] Migration cancelled, ", total.migratedFiles, " file/s were migrated before the cancellation")
+
+		return err
+	}
+
+	sort.Slice(total.diffs, func(i, j int) bool {
+		return total.diffs[i].Path < total.diffs[j].Path
+	})
+
+	log.Print("[INFO] Summary:")
+	log.Print("[INFO] Renamed ", total.renamed, " and removed ", total.removed, " tag key/s in ", total.migratedFiles, " file/s (out of ", total.totalFiles, " file/s processed)")
+
+	if total.unresolved > 0 {
+		log.Print("[WARN] ", total.unresolved, " computed tag expression/s or key/s could not be rewritten, see the warnings above")
+	}
+
+	if args.DryRun {
+		if err := printDiffs(total.diffs, args.DiffFormat); err != nil {
+			return err
+		}
+	}
+
+	if len(total.failedFiles) > 0 {
+		sort.Strings(total.failedFiles)
+
+		return fmt.Errorf("failed to migrate the tag keys of %d file/s: %s", len(total.failedFiles), strings.Join(total.failedFiles, ", "))
+	}
+
+	if args.DryRun && args.FailOnDiff && len(total.diffs) > 0 {
+		return fmt.Errorf("dry run found pending tag key migrations in %d file/s", len(total.diffs))
+	}
+
+	return nil
+}
+
+// migrateFile migrates the tag keys of a single file. A file that fails to process is logged and counted as failed.
+func migrateFile(path string, migrations convert.TagKeyMigrations, args *common.TaggingArgs) migrationCounters {
+	log.Print("[INFO] Migrating tag keys of file ", path)
+
+	var perFile migrationCounters
+	var text string
+	var err error
+
+	if file.IsJSONFile(path) {
+		text, err = migrateJSONFile(path, migrations, args, &perFile)
+	} else {
+		text, err = migrateHCLFile(path, migrations, args, &perFile)
+	}
+
+	if err == nil && text != "" {
+		err = writeMigratedFile(path, text, args, &perFile)
+	}
+
+	if err != nil {
+		log.Print("[ERROR] failed to migrate tag keys of file ", path, " due to an error - ", err)
+
+		return migrationCounters{totalFiles: 1, failedFiles: []string{path}}
+	}
+
+	perFile.totalFiles = 1
+
+	return perFile
+}
+
+// migrateHCLFile returns the migrated content of a native syntax file, or an empty string if nothing changed
+func migrateHCLFile(path string, migrations convert.TagKeyMigrations, args *common.TaggingArgs, perFile *migrationCounters) (string, error) {
+	hcl, err := file.ReadHCLFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	lines := file.BlockLines(path)
+	changed := false
+
+	migrateAttribute := func(body *hclwrite.Body, name string, block string, line int) error {
+		attribute := body.GetAttribute(name)
+		if attribute == nil {
+			return nil
+		}
+
+		tokens, changes, err := convert.MigrateTagKeys(attribute.Expr().BuildTokens(nil), migrations)
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s of %s: %w", name, block, err)
+		}
+
+		if changes.Changed() {
+			body.SetAttributeRaw(name, tokens)
+			changed = true
+		}
+
+		perFile.record(block, location(path, line), changes)
+
+		return nil
+	}
+
+	for _, block := range hcl.Body().Blocks() {
+		switch block.Type() {
+		case "resource":
+			labels := block.Labels()
+			name := "resource." + strings.Join(labels, ".")
+
+			if selected, _ := isResourceSelected(labels, path, args); !selected {
+				continue
+			}
+
+			tagIds := []string{"tags", "labels"}
+			if tagId := providers.GetTagIdByResource(labels[0]); tagId != "" {
+				tagIds = []string{tagId}
+			}
+
+			for _, tagId := range tagIds {
+				if err := migrateAttribute(block.Body(), tagId, name, lines[name]); err != nil {
+					return "", err
+				}
+			}
+
+			if labels[0] == "aws_autoscaling_group" {
+				changes, err := convert.MigrateTagBlocks(block, migrations)
+				if err != nil {
+					return "", fmt.Errorf("failed to migrate tag blocks of %s: %w", name, err)
+				}
+
+				changed = changed || changes.Changed()
+
+				perFile.record(name, location(path, lines[name]), changes)
+			}
+		case "provider":
+			providerName := block.Labels()[0]
+			name := "provider." + providerName
+
+			attribute := tagging.GetProviderDefaultTagsAttribute(providerName)
+			if attribute == "" {
+				continue
+			}
+
+			body := block.Body()
+
+			// AWS nests its default tags in a default_tags block
+			if blockName, attributeName, nested := strings.Cut(attribute, "."); nested {
+				defaultTags := body.FirstMatchingBlock(blockName, nil)
+				if defaultTags == nil {
+					continue
+				}
+
+				body = defaultTags.Body()
+				attribute = attributeName
+			}
+
+			if err := migrateAttribute(body, attribute, name, lines[name]); err != nil {
+				return "", err
+			}
+		case "locals":
+			var names []string
+
+			for name := range block.Body().Attributes() {
+				if strings.HasPrefix(name, terratagFoundPrefix) {
+					names = append(names, name)
+				}
+			}
+
+			sort.Strings(names)
+
+			for _, name := range names {
+				if err := migrateAttribute(block.Body(), name, "local."+name, 0); err != nil {
+					return "", err
+				}
+			}
+		}
+	}
+
+	if !changed {
+		return "", nil
+	}
+
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	// Realign the values of renamed keys, the rest of the file is left as written
+	return string(file.KeepLayout(original, hcl.Bytes())), nil
+}
+
+// migrateJSONFile returns the migrated content of a JSON syntax file, or an empty string if nothing changed
+func migrateJSONFile(path string, migrations convert.TagKeyMigrations, args *common.TaggingArgs, perFile *migrationCounters) (string, error) {
+	jsonFile, err := file.ReadJSONFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	lines := file.BlockLines(path)
+	changed := false
+
+	record := func(name string, changes *convert.TagKeyChanges) {
+		changed = changed || changes.Changed()
+
+		perFile.record(name, location(path, lines[name]), changes)
+	}
+
+	for _, resource := range jsonFile.Blocks("resource") {
+		if selected, _ := isResourceSelected(resource.Labels, path, args); !selected {
+			continue
+		}
+
+		name := "resource." + strings.Join(resource.Labels, ".")
+
+		tagIds := []string{"tags", "labels"}
+		if tagId := providers.GetTagIdByResource(resource.Labels[0]); tagId != "" {
+			tagIds = []string{tagId}
+		}
+
+		for _, tagId := range tagIds {
+			record(name, convert.MigrateJSONTagKeys(resource.Body[tagId], migrations))
+		}
+	}
+
+	for _, provider := range jsonFile.Blocks("provider") {
+		name := "provider." + provider.Labels[0]
+
+		attribute := tagging.GetProviderDefaultTagsAttribute(provider.Labels[0])
+		if attribute == "" {
+			continue
+		}
+
+		blockName, attributeName, nested := strings.Cut(attribute, ".")
+		if !nested {
+			record(name, convert.MigrateJSONTagKeys(provider.Body[attribute], migrations))
+
+			continue
+		}
+
+		// Nested blocks are either an object or an array of objects
+		switch defaultTags := provider.Body[blockName].(type) {
+		case map[string]interface{}:
+			record(name, convert.MigrateJSONTagKeys(defaultTags[attributeName], migrations))
+		case []interface{}:
+			for _, item := range defaultTags {
+				if body, ok := item.(map[string]interface{}); ok {
+					record(name, convert.MigrateJSONTagKeys(body[attributeName], migrations))
+				}
+			}
+		}
+	}
+
+	if !changed {
+		return "", nil
+	}
+
+	text, err := jsonFile.Bytes()
+	if err != nil {
+		return "", err
+	}
+
+	return string(text), nil
+}
+
+// writeMigratedFile rewrites a file in place, or records its diff in dry-run mode
+func writeMigratedFile(path string, text string, args *common.TaggingArgs, perFile *migrationCounters) error {
+	if args.DryRun {
+		diff, err := file.UnifiedDiff(path, text, false)
+		if err != nil {
+			return err
+		}
+
+		if diff.Diff != "" {
+			perFile.diffs = append(perFile.diffs, *diff)
+		}
+	} else if err := file.ReplaceWithTerratagFile(path, text, false); err != nil {
+		return err
+	}
+
+	perFile.migratedFiles = 1
+
+	return nil
+}
+
+// location returns path:line, or path if the line of the block is unknown
+func location(path string, line int) string {
+	if line == 0 {
+		return path
+	}
+
+	return fmt.Sprintf("%s:%d", path, line)
+}
@@ -0,0 +1,222 @@
+package terratag
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudyali/terratag/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTagKeyMigrations(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name: "renames and removals",
+			content: `
+rename:
+  CostCentre: CostCenter
+remove:
+  - LegacyOwner
+`,
+		},
+		{
+			name:    "empty mapping",
+			content: "rename: {}\n",
+			errMsg:  "has no rename or remove entries",
+		},
+		{
+			name: "renamed and removed",
+			content: `
+rename:
+  LegacyOwner: Owner
+remove:
+  - LegacyOwner
+`,
+			errMsg: "tag key LegacyOwner is both renamed and removed",
+		},
+		{
+			name: "chained renames",
+			content: `
+rename:
+  cost_centre: CostCentre
+  CostCentre: CostCenter
+`,
+			errMsg: "tag key cost_centre is renamed to CostCentre, which is renamed itself",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, "migrations.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			migrations, err := loadTagKeyMigrations(path)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{"CostCentre": "CostCenter"}, migrations.Renames)
+			assert.Equal(t, map[string]bool{"LegacyOwner": true}, migrations.Removals)
+		})
+	}
+}
+
+func TestMigrateTagKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mapping := filepath.Join(tmpDir, "migrations.yaml")
+	require.NoError(t, os.WriteFile(mapping, []byte("rename:\n  CostCentre: CostCenter\nremove:\n  - LegacyOwner\n"), 0644))
+
+	dir := filepath.Join(tmpDir, "config")
+	require.NoError(t, os.Mkdir(dir, 0755))
+
+	main := `resource "aws_instance" "web" {
+  ami="ami-123"
+  tags = merge(var.tags, {
+    CostCentre  = "cc-1"
+    LegacyOwner = "bob"
+  })
+}
+
+resource "aws_s3_bucket" "skipped" {
+  tags = {
+    CostCentre = "cc-1"
+  }
+}
+
+provider "aws" {
+  default_tags {
+    tags = {
+      CostCentre = "cc-1"
+    }
+  }
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(main), 0644))
+
+	tagged := `locals {
+  terratag_found_app_tf__aws_instance__app = { "CostCentre" = "cc-2", "Name" = "app" }
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.terratag.tf"), []byte(tagged), 0644))
+
+	jsonConfig := `{"resource": {"google_storage_bucket": {"logs": {"labels": {"CostCentre": "cc-3"}}}}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "storage.tf.json"), []byte(jsonConfig), 0644))
+
+	err := migrateTagKeys(context.Background(), cli.Args{
+		Migrate:     mapping,
+		Dir:         dir,
+		Type:        "terraform",
+		Filter:      ".*",
+		Skip:        "aws_s3_bucket",
+		Parallelism: 2,
+	})
+	require.NoError(t, err)
+
+	migrated, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, `resource "aws_instance" "web" {
+  ami="ami-123"
+  tags = merge(var.tags, {
+    CostCenter = "cc-1"
+  })
+}
+
+resource "aws_s3_bucket" "skipped" {
+  tags = {
+    CostCentre = "cc-1"
+  }
+}
+
+provider "aws" {
+  default_tags {
+    tags = {
+      CostCenter = "cc-1"
+    }
+  }
+}
+`, string(migrated))
+
+	backup, err := os.ReadFile(filepath.Join(dir, "main.tf.bak"))
+	require.NoError(t, err)
+	assert.Equal(t, main, string(backup))
+
+	migrated, err = os.ReadFile(filepath.Join(dir, "app.terratag.tf"))
+	require.NoError(t, err)
+	assert.Contains(t, string(migrated), `{ "CostCenter" = "cc-2", "Name" = "app" }`)
+	assert.NoFileExists(t, filepath.Join(dir, "app.terratag.tf.bak"))
+
+	migrated, err = os.ReadFile(filepath.Join(dir, "storage.tf.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(migrated), `"CostCenter": "cc-3"`)
+	assert.NotContains(t, string(migrated), "CostCentre")
+}
+
+func TestMigrateTagKeys_DryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mapping := filepath.Join(tmpDir, "migrations.yaml")
+	require.NoError(t, os.WriteFile(mapping, []byte("remove:\n  - LegacyOwner\n"), 0644))
+
+	main := `resource "aws_instance" "web" {
+  tags = { LegacyOwner = "bob" }
+}
+`
+	path := filepath.Join(tmpDir, "main.tf")
+	require.NoError(t, os.WriteFile(path, []byte(main), 0644))
+
+	err := migrateTagKeys(context.Background(), cli.Args{
+		Migrate:    mapping,
+		Dir:        tmpDir,
+		Type:       "terraform",
+		Filter:     ".*",
+		DryRun:     true,
+		DiffFormat: "json",
+		FailOnDiff: true,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dry run found pending tag key migrations in 1 file/s")
+
+	unchanged, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, main, string(unchanged))
+}
+
+func TestMigrateTagKeys_FailedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mapping := filepath.Join(tmpDir, "migrations.yaml")
+	require.NoError(t, os.WriteFile(mapping, []byte("remove:\n  - LegacyOwner\n"), 0644))
+
+	dir := filepath.Join(tmpDir, "config")
+	require.NoError(t, os.Mkdir(dir, 0755))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte("resource \"aws_instance\" \"web\" {\n  tags = { LegacyOwner = \"bob\" }\n}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.tf"), []byte("resource \"aws_instance\" \"broken\" {\n"), 0644))
+
+	err := migrateTagKeys(context.Background(), cli.Args{
+		Migrate:     mapping,
+		Dir:         dir,
+		Type:        "terraform",
+		Parallelism: 1,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to migrate the tag keys of 1 file/s: "+filepath.Join(dir, "broken.tf"))
+
+	migrated, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, "resource \"aws_instance\" \"web\" {\n  tags = {}\n}\n", string(migrated))
+}
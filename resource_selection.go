@@ -0,0 +1,62 @@
+package terratag
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/cloudyali/terratag/internal/common"
+	"github.com/cloudyali/terratag/internal/selector"
+)
+
+// isResourceSelected applies the -filter and -skip selectors to a resource of a file, and returns why it was excluded
+func isResourceSelected(labels []string, path string, args *common.TaggingArgs) (bool, string) {
+	selected, reason := args.Selectors.Select(selectorResource(labels, path, args))
+	if !selected {
+		log.Print("[INFO] Resource excluded, ", reason, ", skipping.", labels)
+	}
+
+	return selected, reason
+}
+
+// selectorResource returns a resource of a file as the selectors see it. Files of directories that aren't
+// installed modules are in the root module.
+func selectorResource(labels []string, path string, args *common.TaggingArgs) selector.Resource {
+	resource := selector.Resource{Type: labels[0]}
+	if len(labels) > 1 {
+		resource.Name = labels[1]
+	}
+
+	if relative, err := relativeFilePath(args.Dir, path); err == nil {
+		resource.File = relative
+	}
+
+	if abs, err := filepath.Abs(path); err == nil {
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+			abs = resolved
+		}
+
+		resource.Modules = args.ModuleAddresses[filepath.Dir(abs)]
+	}
+
+	return resource
+}
+
+// relativeFilePath returns the slash separated path of a file relative to dir
+func relativeFilePath(dir string, path string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	relative, err := filepath.Rel(absDir, abs)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.ToSlash(relative), nil
+}
@@ -0,0 +1,174 @@
+package terratag
+
+import (
+	"log"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/cloudyali/terratag/internal/common"
+	"github.com/cloudyali/terratag/internal/file"
+	"github.com/cloudyali/terratag/internal/providers"
+	"github.com/cloudyali/terratag/internal/tagging"
+	"github.com/cloudyali/terratag/internal/tfschema"
+)
+
+// fileTagging is what the resources of a file are tagged with, whatever the syntax of the file
+type fileTagging struct {
+	path        string
+	filename    string // Name of the file in the terratag_* locals keys
+	jsonSyntax  bool
+	args        *common.TaggingArgs
+	lines       map[string]int
+	annotations map[string]file.Annotation
+	traces      map[string]map[string]string // Trace tags of the resources, nil if trace tags are disabled
+}
+
+func newFileTagging(path string, args *common.TaggingArgs) (*fileTagging, error) {
+	annotations, err := file.ResourceAnnotations(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileTagging := &fileTagging{
+		path:        path,
+		filename:    file.GetFilename(path),
+		jsonSyntax:  file.IsJSONFile(path),
+		args:        args,
+		lines:       file.BlockLines(path),
+		annotations: annotations,
+	}
+
+	if args.Tracer != nil {
+		fileTagging.traces = args.Tracer.FileTags(path)
+	}
+
+	return fileTagging, nil
+}
+
+// resourceTagging is how a resource is tagged
+type resourceTagging struct {
+	tagSet    *common.TagSet
+	tagId     string
+	traceTags map[string]string
+}
+
+// resourceTagging decides whether and how a resource block is tagged: the selectors, the annotations, the tag
+// standard, the tagging strategy and the tag constraints of its provider are applied. The result of a resource
+// that isn't tagged is recorded and nil is returned. existingKeys returns the keys of the current tags of the
+// resource and whether they are all known.
+func (f *fileTagging) resourceTagging(perFileCounters *counters, resource *hclwrite.Block, existingKeys func(tagId string) ([]string, bool, error)) (*resourceTagging, error) {
+	labels := resource.Labels()
+	resourceType := labels[0]
+
+	log.Print("[INFO] Processing resource ", labels)
+
+	perFileCounters.totalResources += 1
+
+	selected, reason := isResourceSelected(labels, f.path, f.args)
+	if !selected {
+		perFileCounters.addResource(resourceResult(labels, f.lines, ResourceFiltered, reason))
+
+		return nil, nil
+	}
+
+	annotation := f.annotations[strings.Join(labels, ".")]
+	if annotation.Ignore {
+		log.Print("[INFO] Resource ignored by a terratag:ignore annotation, skipping.", labels)
+
+		perFileCounters.addResource(resourceResult(labels, f.lines, ResourceSkipped, ignoredResourceReason(annotation)))
+
+		return nil, nil
+	}
+
+	tagSet, err := resolveTagSet(f.args, resourceType)
+	if err != nil {
+		_, err = perFileCounters.failResource(labels, f.lines, err)
+
+		return nil, err
+	}
+
+	if tagSet == nil {
+		log.Print("[INFO] Resource excluded by the tag standard, skipping.", labels)
+
+		perFileCounters.addResource(resourceResult(labels, f.lines, ResourceSkipped, reasonExcludedByStandard))
+
+		return nil, nil
+	}
+
+	isTaggable, err := tfschema.IsTaggable(f.args.Dir, *resource)
+	if err != nil {
+		_, err = perFileCounters.failResource(labels, f.lines, err)
+
+		return nil, err
+	}
+
+	if isTaggable && f.args.Strategy == common.StrategyProvider && tagging.GetDefaultTagsProviderName(resourceType) != "" {
+		if tagSet.Name == "" {
+			log.Print("[INFO] Resource tagged through provider default tags, skipping.", labels)
+			logProviderIgnoredTags(labels, annotation)
+
+			perFileCounters.addResource(resourceResult(labels, f.lines, ResourceSkipped, reasonProviderDefaultTags))
+
+			return nil, nil
+		}
+
+		logProviderTagSet(labels, tagSet)
+	}
+
+	if tagSet, err = withoutIgnoredTags(tagSet, labels, annotation); err != nil {
+		_, err = perFileCounters.failResource(labels, f.lines, err)
+
+		return nil, err
+	}
+
+	if !isTaggable || (f.jsonSyntax && !tagging.IsJSONTaggable(resourceType)) {
+		log.Print("[INFO] Resource not taggable, skipping.", labels)
+
+		notTaggable := resourceResult(labels, f.lines, ResourceNotTaggable, "")
+		if isTaggable {
+			notTaggable.Reason = "tags of this resource type can't be merged in the JSON syntax"
+		}
+
+		perFileCounters.addResource(notTaggable)
+
+		return nil, nil
+	}
+
+	log.Print("[INFO] Resource taggable, processing...", labels)
+
+	tagId := providers.GetTagIdByResource(resourceType)
+	traceTags := resourceTraceTags(f.args, f.traces, labels, tagId)
+
+	existing, known, err := existingKeys(tagId)
+	if err != nil {
+		_, err = perFileCounters.failResource(labels, f.lines, err)
+
+		return nil, err
+	}
+
+	reason, err = checkTagConstraints(labels, existing, known, tagSet, traceTags)
+	if err != nil {
+		_, err = perFileCounters.failResource(labels, f.lines, err)
+
+		return nil, err
+	}
+
+	if reason != "" {
+		perFileCounters.addResource(resourceResult(labels, f.lines, ResourceSkipped, reason))
+
+		return nil, nil
+	}
+
+	perFileCounters.taggedResources += 1
+
+	return &resourceTagging{tagSet: tagSet, tagId: tagId, traceTags: traceTags}, nil
+}
+
+// taggedResult returns the result of a tagged resource
+func (f *fileTagging) taggedResult(labels []string, tagId string) ResourceResult {
+	tagged := resourceResult(labels, f.lines, ResourceTagged, "")
+	tagged.TagAttribute = tagId
+
+	return tagged
+}
@@ -0,0 +1,70 @@
+package terratag
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cloudyali/terratag/internal/common"
+	"github.com/cloudyali/terratag/internal/file"
+)
+
+// ignoredResourceReason returns the reason reported for a resource skipped by a terratag:ignore annotation
+func ignoredResourceReason(annotation file.Annotation) string {
+	if annotation.Reason == "" {
+		return reasonIgnoreAnnotation
+	}
+
+	return reasonIgnoreAnnotation + ": " + annotation.Reason
+}
+
+// withoutIgnoredTags returns the tag set of a resource without the tags its terratag:ignore=<keys> annotation
+// ignores. The keys of the annotation are the keys of the standard, they are ignored under the keys of the provider
+// of the resource as well. The tag set is named after the resource, so that its terratag_added_* local is specific to it.
+func withoutIgnoredTags(tagSet *common.TagSet, labels []string, annotation file.Annotation) (*common.TagSet, error) {
+	if len(annotation.IgnoredTags) == 0 {
+		return tagSet, nil
+	}
+
+	tags, err := toTagsMap(tagSet.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	expressions := maps.Clone(tagSet.TagExpressions)
+	excluded := slices.Clone(tagSet.ExcludedTags)
+
+	for _, key := range annotation.IgnoredTags {
+		if alias, ok := tagSet.KeyAliases[key]; ok {
+			key = alias
+		}
+
+		delete(tags, key)
+		delete(expressions, key)
+		excluded = append(excluded, key)
+	}
+
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the tags of %s to JSON: %w", strings.Join(labels, "."), err)
+	}
+
+	return &common.TagSet{
+		Name:           strings.Join(labels, "__"),
+		Tags:           string(tagsJSON),
+		TagExpressions: expressions,
+		ExcludedTags:   excluded,
+		KeyAliases:     tagSet.KeyAliases,
+	}, nil
+}
+
+// logProviderIgnoredTags warns that the tags ignored by the annotation of a resource tagged through provider
+// default tags are still inherited from the provider
+func logProviderIgnoredTags(labels []string, annotation file.Annotation) {
+	if len(annotation.IgnoredTags) > 0 {
+		log.Print("[WARN] Ignored tags ", annotation.IgnoredTags, " are still inherited from the provider default tags.", labels)
+	}
+}
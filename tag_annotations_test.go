@@ -0,0 +1,47 @@
+package terratag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudyali/terratag/internal/common"
+	"github.com/cloudyali/terratag/internal/file"
+)
+
+func TestWithoutIgnoredTags(t *testing.T) {
+	tagSet := &common.TagSet{
+		Tags:           `{"CostCenter":"var.cost_center","Owner":"platform","Team":"infra"}`,
+		TagExpressions: map[string]bool{"CostCenter": true},
+		ExcludedTags:   []string{"Environment"},
+	}
+	labels := []string{"aws_s3_bucket", "logs"}
+
+	unchanged, err := withoutIgnoredTags(tagSet, labels, file.Annotation{SkipValidation: true})
+	require.NoError(t, err)
+	assert.Same(t, tagSet, unchanged)
+
+	ignored, err := withoutIgnoredTags(tagSet, labels, file.Annotation{IgnoredTags: []string{"Owner", "CostCenter"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "aws_s3_bucket__logs", ignored.Name)
+	assert.JSONEq(t, `{"Team":"infra"}`, ignored.Tags)
+	assert.Empty(t, ignored.TagExpressions)
+	assert.Equal(t, []string{"Environment", "Owner", "CostCenter"}, ignored.ExcludedTags)
+	assert.Equal(t, []string{"Environment"}, tagSet.ExcludedTags, "the resolved tag set must not be modified")
+
+	aliased := &common.TagSet{
+		Tags:       `{"cost-center":"1234","team":"infra"}`,
+		KeyAliases: map[string]string{"CostCenter": "cost-center", "Team": "team"},
+	}
+
+	ignored, err = withoutIgnoredTags(aliased, []string{"google_storage_bucket", "logs"}, file.Annotation{IgnoredTags: []string{"CostCenter"}})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"team":"infra"}`, ignored.Tags)
+	assert.Equal(t, []string{"cost-center"}, ignored.ExcludedTags)
+	assert.Equal(t, aliased.KeyAliases, ignored.KeyAliases)
+
+	assert.Equal(t, reasonIgnoreAnnotation+": vendor managed", ignoredResourceReason(file.Annotation{Ignore: true, Reason: "vendor managed"}))
+}
@@ -0,0 +1,95 @@
+package terratag
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/cloudyali/terratag/internal/common"
+	"github.com/cloudyali/terratag/internal/convert"
+	"github.com/cloudyali/terratag/internal/providers"
+)
+
+// existingTagKeys returns the literal keys of the tags of a resource block. known is false when the tags are
+// computed (e.g. merge(var.tags, {...}) or tag blocks), and the number of existing tags can't be known.
+func existingTagKeys(resource *hclwrite.Block, tagId string) ([]string, bool, error) {
+	attribute := resource.Body().GetAttribute(tagId)
+	if attribute == nil {
+		for _, block := range resource.Body().Blocks() {
+			if block.Type() == tagId {
+				return nil, false, nil
+			}
+		}
+
+		return nil, true, nil
+	}
+
+	return convert.LiteralTagKeys(attribute.Expr().BuildTokens(nil))
+}
+
+// existingJSONTagKeys returns the keys of the tags of a resource of a JSON file. known is false when the tags are
+// an expression, and the number of existing tags can't be known.
+func existingJSONTagKeys(body map[string]interface{}, tagId string) ([]string, bool) {
+	switch existing := body[tagId].(type) {
+	case nil:
+		return nil, true
+	case map[string]interface{}:
+		return slices.Sorted(maps.Keys(existing)), true
+	default:
+		return nil, false
+	}
+}
+
+// checkTagConstraints checks the tags a resource would have once tagged against the limits of its cloud provider.
+// It returns why the resource isn't tagged when its tags would exceed the tag limit of the provider, and warns
+// about the added tags the provider would reject.
+func checkTagConstraints(labels []string, existing []string, known bool, tagSet *common.TagSet, traceTags map[string]string) (string, error) {
+	constraints := providers.GetTagConstraints(labels[0], "")
+	if constraints == nil {
+		return "", nil
+	}
+
+	added, err := toTagsMap(tagSet.Tags)
+	if err != nil {
+		return "", err
+	}
+
+	maps.Copy(added, traceTags)
+
+	keys := map[string]bool{}
+	for _, key := range existing {
+		keys[key] = true
+	}
+
+	for key := range added {
+		keys[key] = true
+	}
+
+	if violation := constraints.CheckCount(len(keys)); violation != nil {
+		if known {
+			log.Print("[WARN] ", violation.Message, " once tagged, skipping.", labels)
+
+			return fmt.Sprintf("tagging would give the resource %d tags, %s allows at most %d", len(keys), constraints.Provider, constraints.MaxTags), nil
+		}
+
+		log.Print("[WARN] ", violation.Message, " once tagged, without its computed tags.", labels)
+	} else if !known {
+		log.Print("[INFO] Resource has computed tags, the ", constraints.Provider, " limit of ", constraints.MaxTags, " tags can't be checked.", labels)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(added)) {
+		violations := constraints.CheckKey(key)
+		if !tagSet.TagExpressions[key] {
+			violations = append(violations, constraints.CheckValue(key, added[key])...)
+		}
+
+		for _, violation := range violations {
+			log.Print("[WARN] ", violation.Message, ".", labels)
+		}
+	}
+
+	return "", nil
+}
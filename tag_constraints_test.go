@@ -0,0 +1,90 @@
+package terratag
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudyali/terratag/internal/common"
+)
+
+func TestCheckTagConstraints(t *testing.T) {
+	added := map[string]string{}
+	for i := 0; i < 45; i++ {
+		added[fmt.Sprintf("Tag%d", i)] = "value"
+	}
+
+	tags, err := json.Marshal(added)
+	require.NoError(t, err)
+
+	tagSet := &common.TagSet{Tags: string(tags)}
+	labels := []string{"aws_instance", "web"}
+
+	existing := []string{"Name", "Tag0", "Owner", "Team", "Service"}
+
+	reason, err := checkTagConstraints(labels, existing, true, tagSet, nil)
+	require.NoError(t, err)
+	assert.Empty(t, reason, "existing keys set by the tag set are counted once")
+
+	traceTags := map[string]string{"GitCommit": "abc123"}
+
+	reason, err = checkTagConstraints(labels, existing, true, tagSet, traceTags)
+	require.NoError(t, err)
+	assert.Empty(t, reason, "resources can have as many tags as the provider allows")
+
+	reason, err = checkTagConstraints(labels, append(existing, "Extra"), true, tagSet, traceTags)
+	require.NoError(t, err)
+	assert.Equal(t, "tagging would give the resource 51 tags, aws allows at most 50", reason)
+
+	reason, err = checkTagConstraints(labels, append(existing, "Extra"), false, tagSet, traceTags)
+	require.NoError(t, err)
+	assert.Empty(t, reason, "resources with computed tags are tagged")
+
+	reason, err = checkTagConstraints([]string{"custom_resource", "x"}, append(existing, "Extra"), true, tagSet, traceTags)
+	require.NoError(t, err)
+	assert.Empty(t, reason, "resources of unknown providers have no limits")
+}
+
+func TestExistingTagKeys(t *testing.T) {
+	f, diags := hclwrite.ParseConfig([]byte(`
+resource "aws_instance" "tagged" {
+  tags = merge(local.terratag_added_main, { Name = "web" })
+}
+
+resource "aws_instance" "computed" {
+  tags = var.tags
+}
+
+resource "aws_instance" "untagged" {
+}
+`), "main.tf", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+
+	blocks := f.Body().Blocks()
+
+	keys, known, err := existingTagKeys(blocks[0], "tags")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Name"}, keys)
+	assert.True(t, known)
+
+	_, known, err = existingTagKeys(blocks[1], "tags")
+	require.NoError(t, err)
+	assert.False(t, known)
+
+	keys, known, err = existingTagKeys(blocks[2], "tags")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	assert.True(t, known)
+
+	keys, known = existingJSONTagKeys(map[string]interface{}{"tags": map[string]interface{}{"Team": "ops", "Name": "web"}}, "tags")
+	assert.Equal(t, []string{"Name", "Team"}, keys)
+	assert.True(t, known)
+
+	_, known = existingJSONTagKeys(map[string]interface{}{"tags": "${var.tags}"}, "tags")
+	assert.False(t, known)
+}
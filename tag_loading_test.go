@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/cloudyali/terratag/internal/common"
+	"github.com/cloudyali/terratag/internal/standards"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -228,6 +230,60 @@ optional_tags:
 	assert.NotContains(t, tagsJSON, `"OptionalWithoutDefault"`) // Should not be included
 }
 
+func TestLoadTaggingTags_GenerateVariables(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "terratag-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	standard := `
+version: 1
+metadata:
+  description: "Variables test standard"
+cloud_provider: "aws"
+required_tags:
+  - key: "Environment"
+    default_value: "prod"
+  - key: "CostCenter"
+    format: "^CC-[0-9]{4}$"
+`
+
+	file := filepath.Join(tmpDir, "variables.yaml")
+	err = os.WriteFile(file, []byte(standard), 0644)
+	require.NoError(t, err)
+
+	loaded, err := loadTaggingTags(file, ".", true)
+	require.NoError(t, err)
+
+	assert.Contains(t, loaded.JSON, `"CostCenter":"var.terratag_costcenter"`)
+	assert.Contains(t, loaded.JSON, `"Environment":"prod"`)
+	assert.True(t, loaded.Expressions["CostCenter"])
+	assert.False(t, loaded.Expressions["Environment"])
+	require.Len(t, loaded.Variables, 1)
+	assert.Equal(t, "CostCenter", loaded.Variables[0].Key)
+}
+
+func TestWriteTagVariables(t *testing.T) {
+	tmpDir := t.TempDir()
+	moduleDir := filepath.Join(tmpDir, ".terraform", "modules", "vpc")
+	require.NoError(t, os.MkdirAll(moduleDir, 0755))
+
+	specs := []standards.TagSpec{{Key: "CostCenter"}}
+	args := &common.TaggingArgs{
+		Dir:             tmpDir,
+		ModuleAddresses: map[string][]string{moduleDir: {"module.vpc"}},
+	}
+
+	_, err := writeTagVariables(args, specs, []string{filepath.Join(tmpDir, "main.tf")})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(tmpDir, standards.VariablesFileName))
+	assert.FileExists(t, filepath.Join(tmpDir, standards.VariablesExampleFileName))
+
+	_, err = writeTagVariables(args, specs, []string{filepath.Join(moduleDir, "main.tf")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), moduleDir)
+	assert.NoFileExists(t, filepath.Join(moduleDir, standards.VariablesFileName))
+}
+
 func TestLoadTagsFromFile_JSONValidation(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "terratag-test")
 	require.NoError(t, err)
@@ -299,4 +355,47 @@ required_tags:
 	var tagLoadingErr *TagLoadingError
 	assert.True(t, errors.As(err, &tagLoadingErr))
 	assert.Contains(t, tagLoadingErr.Cause, "file access error")
-}
\ No newline at end of file
+}
+func TestLoadTaggingTags_Templates(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("TERRATAG_TEST_PROJECT", "billing")
+
+	standard := `
+version: 1
+metadata:
+  description: "Templates test standard"
+  version: "3.0"
+cloud_provider: "aws"
+required_tags:
+  - key: "Project"
+    default_value: "{{env:TERRATAG_TEST_PROJECT}}"
+  - key: "Stack"
+    default_value: "{{dir.name}}"
+optional_tags:
+  - key: "Standard"
+    default_value: "{{standard.version}}"
+`
+
+	file := filepath.Join(tmpDir, "templates.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(standard), 0644))
+
+	stackDir := filepath.Join(tmpDir, "network")
+	require.NoError(t, os.Mkdir(stackDir, 0755))
+
+	loaded, err := loadTaggingTags(file, stackDir, false)
+	require.NoError(t, err)
+
+	assert.Contains(t, loaded.JSON, `"Project":"billing"`)
+	assert.Contains(t, loaded.JSON, `"Stack":"network"`)
+	assert.Contains(t, loaded.JSON, `"Standard":"3.0"`)
+
+	t.Setenv("TERRATAG_TEST_PROJECT", "")
+	os.Unsetenv("TERRATAG_TEST_PROJECT")
+
+	_, err = loadTaggingTags(file, stackDir, false)
+
+	var loadingErr *TagLoadingError
+	require.True(t, errors.As(err, &loadingErr))
+	assert.Equal(t, "invalid tag template", loadingErr.Cause)
+	assert.Contains(t, err.Error(), "TERRATAG_TEST_PROJECT")
+}
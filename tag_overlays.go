@@ -0,0 +1,194 @@
+package terratag
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/bmatcuk/doublestar"
+
+	"github.com/cloudyali/terratag/internal/common"
+)
+
+// fileTaggingArgs returns the tagging arguments of a file, with the {{dir.*}} placeholders of the standard resolved
+// for the directory of the file and the tags of the directory overlays matching the file applied on top of the
+// standard and resource rule tags. Files no overlay matches are tagged with args.
+func fileTaggingArgs(path string, args *common.TaggingArgs) (*common.TaggingArgs, error) {
+	args, err := dirTaggingArgs(filepath.Dir(path), args)
+	if err != nil {
+		return nil, err
+	}
+
+	overlay, err := fileOverlayTags(path, args)
+	if err != nil || len(overlay) == 0 {
+		return args, err
+	}
+
+	fileArgs := *args
+
+	fileArgs.Tags, fileArgs.TagExpressions, err = overlayTags(args.Tags, args.TagExpressions, overlay, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.TagSets != nil {
+		tagSets := &overlaidTagSets{resolver: args.TagSets, tags: overlay}
+		fileArgs.TagSets = tagSets
+
+		// The standard tags of a multi-cloud standard are the tags of its default provider, under its keys
+		standardTags, err := tagSets.ResolveProviderTagSet("")
+		if err != nil {
+			return nil, err
+		}
+
+		if standardTags != nil {
+			fileArgs.Tags, fileArgs.TagExpressions = standardTags.Tags, standardTags.TagExpressions
+		}
+	}
+
+	return &fileArgs, nil
+}
+
+// dirTaggingArgs returns the tagging arguments of the files of a directory, args if the tags don't depend on it
+func dirTaggingArgs(dir string, args *common.TaggingArgs) (*common.TaggingArgs, error) {
+	resolver, ok := args.TagSets.(common.DirTagSetResolver)
+	if !ok {
+		return args, nil
+	}
+
+	standardTags, tagSets, err := resolver.ResolveDirTagSets(dir)
+	if err != nil || tagSets == args.TagSets {
+		return args, err
+	}
+
+	dirArgs := *args
+
+	dirArgs.Tags, dirArgs.TagExpressions, dirArgs.TagSets = standardTags.Tags, standardTags.TagExpressions, tagSets
+
+	return &dirArgs, nil
+}
+
+// fileOverlayTags returns the tags of the overlays matching the directory or the path of a file relative to
+// args.Dir. Overlays are applied in order, the last matching overlay wins.
+func fileOverlayTags(filePath string, args *common.TaggingArgs) (map[string]string, error) {
+	if len(args.Overlays) == 0 {
+		return nil, nil
+	}
+
+	relative, err := relativeFilePath(args.Dir, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	relativeDir := path.Dir(relative)
+
+	tags := map[string]string{}
+
+	for _, overlay := range args.Overlays {
+		matched, err := doublestar.Match(overlay.Pattern, relativeDir)
+		if err == nil && !matched {
+			matched, err = doublestar.Match(overlay.Pattern, relative)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid directory overlay path '%s': %w", overlay.Pattern, err)
+		}
+
+		if matched {
+			log.Print("[INFO] Applying the tags of the directory overlay ", overlay.Pattern, " to ", filePath)
+
+			maps.Copy(tags, overlay.Tags)
+		}
+	}
+
+	return tags, nil
+}
+
+// overlayTags returns the JSON tags and expression keys with the overlay tags set, except the excluded keys.
+// Overlay values are literal, the keys they override are no longer expressions.
+func overlayTags(tags string, expressions map[string]bool, overlay map[string]string, excluded []string) (string, map[string]bool, error) {
+	tagsMap, err := toTagsMap(tags)
+	if err != nil {
+		return "", nil, err
+	}
+
+	overlaidExpressions := maps.Clone(expressions)
+	if overlaidExpressions == nil {
+		overlaidExpressions = map[string]bool{}
+	}
+
+	for key, value := range overlay {
+		if slices.Contains(excluded, key) {
+			continue
+		}
+
+		tagsMap[key] = value
+		delete(overlaidExpressions, key)
+	}
+
+	tagsJSON, err := json.Marshal(tagsMap)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal overlaid tags to JSON: %w", err)
+	}
+
+	return string(tagsJSON), overlaidExpressions, nil
+}
+
+// overlaidTagSets applies the tags of the directory overlays of a file to the tag sets of every resource type
+type overlaidTagSets struct {
+	resolver common.TagSetResolver
+	tags     map[string]string
+}
+
+func (o *overlaidTagSets) ResolveTagSet(resourceType string) (*common.TagSet, error) {
+	tagSet, err := o.resolver.ResolveTagSet(resourceType)
+	if err != nil || tagSet == nil {
+		return tagSet, err
+	}
+
+	return o.overlay(tagSet)
+}
+
+func (o *overlaidTagSets) ResolveProviderTagSet(provider string) (*common.TagSet, error) {
+	resolver, ok := o.resolver.(common.ProviderTagSetResolver)
+	if !ok {
+		return nil, nil
+	}
+
+	tagSet, err := resolver.ResolveProviderTagSet(provider)
+	if err != nil || tagSet == nil {
+		return tagSet, err
+	}
+
+	return o.overlay(tagSet)
+}
+
+// overlay returns a copy of a tag set with the overlay tags set, under the keys of its provider
+func (o *overlaidTagSets) overlay(tagSet *common.TagSet) (*common.TagSet, error) {
+	tags := o.tags
+	if len(tagSet.KeyAliases) > 0 {
+		tags = make(map[string]string, len(o.tags))
+		for key, value := range o.tags {
+			if alias, ok := tagSet.KeyAliases[key]; ok {
+				key = alias
+			}
+
+			tags[key] = value
+		}
+	}
+
+	overlaid := *tagSet
+
+	var err error
+
+	overlaid.Tags, overlaid.TagExpressions, err = overlayTags(tagSet.Tags, tagSet.TagExpressions, tags, tagSet.ExcludedTags)
+	if err != nil {
+		return nil, err
+	}
+
+	return &overlaid, nil
+}
@@ -0,0 +1,92 @@
+package terratag
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudyali/terratag/internal/common"
+)
+
+type staticTagSets map[string]*common.TagSet
+
+func (s staticTagSets) ResolveTagSet(resourceType string) (*common.TagSet, error) {
+	return s[resourceType], nil
+}
+
+func TestFileTaggingArgs(t *testing.T) {
+	dir := t.TempDir()
+
+	args := &common.TaggingArgs{
+		Dir:            dir,
+		Tags:           `{"Environment":"var.environment","Team":"platform"}`,
+		TagExpressions: map[string]bool{"Environment": true},
+		TagSets: staticTagSets{
+			"aws_instance": {Tags: `{"Environment":"var.environment","Team":"platform"}`, TagExpressions: map[string]bool{"Environment": true}},
+			"aws_iam_role": {Name: "aws_iam_role", Tags: `{"Team":"platform"}`, ExcludedTags: []string{"Environment"}},
+		},
+		Overlays: []common.TagOverlay{
+			{Pattern: "envs/*", Tags: map[string]string{"Environment": "shared", "Tier": "standard"}},
+			{Pattern: "envs/prod/**", Tags: map[string]string{"Environment": "prod"}},
+			{Pattern: "envs/dev", Tags: map[string]string{"Environment": "dev"}},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedTags string
+	}{
+		{
+			name:         "no matching overlay",
+			path:         filepath.Join(dir, "main.tf"),
+			expectedTags: args.Tags,
+		},
+		{
+			name:         "later overlays override earlier ones",
+			path:         filepath.Join(dir, "envs", "prod", "main.tf"),
+			expectedTags: `{"Environment":"prod","Team":"platform","Tier":"standard"}`,
+		},
+		{
+			name:         "nested directories match a ** glob",
+			path:         filepath.Join(dir, "envs", "prod", "network", "main.tf"),
+			expectedTags: `{"Environment":"prod","Team":"platform"}`,
+		},
+		{
+			name:         "directory glob without wildcard",
+			path:         filepath.Join(dir, "envs", "dev", "main.tf.json"),
+			expectedTags: `{"Environment":"dev","Team":"platform","Tier":"standard"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileArgs, err := fileTaggingArgs(tt.path, args)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, tt.expectedTags, fileArgs.Tags)
+
+			if fileArgs == args {
+				return
+			}
+
+			assert.False(t, fileArgs.TagExpressions["Environment"], "overlay values are literal")
+			assert.True(t, args.TagExpressions["Environment"], "the tags of other files are left untouched")
+
+			instance, err := fileArgs.TagSets.ResolveTagSet("aws_instance")
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedTags, instance.Tags)
+
+			role, err := fileArgs.TagSets.ResolveTagSet("aws_iam_role")
+			require.NoError(t, err)
+			assert.NotContains(t, role.Tags, "Environment", "excluded tags are not overlaid")
+			assert.Equal(t, "aws_iam_role", role.Name)
+
+			excluded, err := fileArgs.TagSets.ResolveTagSet("aws_s3_bucket")
+			require.NoError(t, err)
+			assert.Nil(t, excluded)
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package terratag
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/cloudyali/terratag/internal/common"
+	"github.com/cloudyali/terratag/internal/standards"
+)
+
+// taggingTags are the tags of a tag standardization file prepared for tagging mode
+type taggingTags struct {
+	JSON        string              // JSON string of the standard tags
+	Expressions map[string]bool     // Keys whose values are raw HCL expressions
+	Variables   []standards.TagSpec // Required tags without a value, set through generated Terraform variables
+	Overlays    []common.TagOverlay // Tag values of the files of matching directories
+
+	standard          *standards.TagStandard
+	filePath          string // Tag standardization file of the standard
+	dir               string // Directory the {{dir.*}} placeholders are resolved for
+	dirTemplates      bool   // Whether the standard has {{dir.*}} placeholders
+	generateVariables bool
+	variants          map[string]*taggingVariant // Variants of a multi-cloud standard by provider
+	mu                sync.Mutex
+	tagSets           map[string]*common.TagSet // Resolved tag sets by resource type
+	dirTags           map[string]*taggingTags   // Tags of the other directories by directory
+}
+
+// taggingVariant is the variant of a multi-cloud standard for one of its providers
+type taggingVariant struct {
+	standard *standards.TagStandard
+	tagSet   *common.TagSet // Tags of the resource types of the provider the resource rules don't change
+}
+
+// addVariants prepares the variant of every provider of a multi-cloud standard. The variant of the default
+// provider becomes the standard tags, the variants whose tags differ get a tag set named after their provider.
+func (t *taggingTags) addVariants() error {
+	t.variants = map[string]*taggingVariant{}
+
+	for _, provider := range t.standard.Providers() {
+		standard := t.standard.ForProvider(provider)
+		tags, expressions, _ := t.tagValues(standard.RequiredTags, standard.OptionalTags)
+
+		tagsJSON, err := json.Marshal(tags)
+		if err != nil {
+			return fmt.Errorf("failed to marshal tags of %s to JSON: %w", provider, err)
+		}
+
+		if provider == t.standard.DefaultProvider() {
+			t.JSON, t.Expressions = string(tagsJSON), expressions
+		}
+
+		tagSet := &common.TagSet{
+			Tags:           string(tagsJSON),
+			TagExpressions: expressions,
+			KeyAliases:     t.standard.ProviderOverrides[provider].KeyAliases,
+		}
+
+		if tagSet.Tags != t.JSON || !maps.Equal(tagSet.TagExpressions, t.Expressions) {
+			tagSet.Name = provider
+		}
+
+		t.variants[provider] = &taggingVariant{standard: standard, tagSet: tagSet}
+	}
+
+	return nil
+}
+
+// variant returns the variant of a multi-cloud standard applying to a resource type, resource types of the
+// providers the standard doesn't target get the variant of the default provider. It is nil for single provider standards.
+func (t *taggingTags) variant(resourceType string) *taggingVariant {
+	if len(t.variants) == 0 {
+		return nil
+	}
+
+	provider, ok := t.standard.ResourceProvider(resourceType)
+	if !ok {
+		provider = t.standard.DefaultProvider()
+	}
+
+	return t.variants[provider]
+}
+
+// tagValues returns the values of the required tags and of the optional tags with a default value.
+// Priority is default_value > examples > allowed_values > generated variable or placeholder,
+// the keys of required tags that got a placeholder are returned as well.
+func (t *taggingTags) tagValues(required []standards.TagSpec, optional []standards.TagSpec) (map[string]string, map[string]bool, []string) {
+	tags := make(map[string]string)
+	expressions := make(map[string]bool)
+	var missingValues []string
+
+	// Add required tags with their default values
+	for _, tagSpec := range required {
+		if tagSpec.DefaultValue != "" {
+			tags[tagSpec.Key] = tagSpec.DefaultValue
+			expressions[tagSpec.Key] = tagSpec.DefaultIsExpression
+		} else if len(tagSpec.Examples) > 0 {
+			tags[tagSpec.Key] = tagSpec.Examples[0]
+			log.Printf("[INFO] Using example value '%s' for required tag '%s'", tagSpec.Examples[0], tagSpec.Key)
+		} else if len(tagSpec.AllowedValues) > 0 {
+			tags[tagSpec.Key] = tagSpec.AllowedValues[0]
+			log.Printf("[INFO] Using first allowed value '%s' for required tag '%s'", tagSpec.AllowedValues[0], tagSpec.Key)
+		} else if t.generateVariables {
+			tags[tagSpec.Key] = "var." + standards.TagVariableName(tagSpec.Key)
+			expressions[tagSpec.Key] = true
+			t.addVariable(tagSpec)
+		} else {
+			// Track tags that need manual configuration
+			missingValues = append(missingValues, tagSpec.Key)
+			// Use a descriptive placeholder that indicates action needed
+			tags[tagSpec.Key] = fmt.Sprintf("CONFIGURE_%s_VALUE", strings.ToUpper(tagSpec.Key))
+		}
+	}
+
+	// Add optional tags with their default values if specified
+	for _, tagSpec := range optional {
+		if tagSpec.DefaultValue != "" {
+			tags[tagSpec.Key] = tagSpec.DefaultValue
+			expressions[tagSpec.Key] = tagSpec.DefaultIsExpression
+		}
+	}
+
+	return tags, expressions, missingValues
+}
+
+// addVariable records a tag whose value is set through a generated variable
+func (t *taggingTags) addVariable(tagSpec standards.TagSpec) {
+	for _, variable := range t.Variables {
+		if standards.TagVariableName(variable.Key) == standards.TagVariableName(tagSpec.Key) {
+			return
+		}
+	}
+
+	log.Printf("[INFO] Using generated variable '%s' for required tag '%s'", standards.TagVariableName(tagSpec.Key), tagSpec.Key)
+
+	t.Variables = append(t.Variables, tagSpec)
+}
+
+// ResolveTagSet returns the tags of a resource type, after applying the resource rules and global excludes of the
+// standard the same way the validator does. Resource types the rules don't change get the standard tags, or the
+// tags of their provider with a multi-cloud standard.
+func (t *taggingTags) ResolveTagSet(resourceType string) (*common.TagSet, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if tagSet, ok := t.tagSets[resourceType]; ok {
+		return tagSet, nil
+	}
+
+	standard := t.standard
+	base := &common.TagSet{Tags: t.JSON, TagExpressions: t.Expressions}
+
+	if variant := t.variant(resourceType); variant != nil {
+		standard, base = variant.standard, variant.tagSet
+	}
+
+	var tagSet *common.TagSet
+
+	if !standard.IsGloballyExcluded(resourceType) {
+		required, optional, excluded := standard.EffectiveTagRequirements(resourceType)
+		tags, expressions, _ := t.tagValues(required, optional)
+
+		for _, key := range excluded {
+			delete(tags, key)
+			delete(expressions, key)
+		}
+
+		tagsJSON, err := json.Marshal(tags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal tags of %s to JSON: %w", resourceType, err)
+		}
+
+		if string(tagsJSON) == base.Tags && maps.Equal(expressions, base.TagExpressions) {
+			tagSet = base
+		} else {
+			tagSet = &common.TagSet{
+				Name:           resourceType,
+				Tags:           string(tagsJSON),
+				TagExpressions: expressions,
+				ExcludedTags:   excluded,
+				KeyAliases:     base.KeyAliases,
+			}
+		}
+	}
+
+	t.tagSets[resourceType] = tagSet
+
+	return tagSet, nil
+}
+
+// ResolveProviderTagSet returns the tags of the provider blocks of a cloud provider with a multi-cloud standard, the
+// tags of the default provider for the providers the standard doesn't target. It is nil with a single provider standard.
+func (t *taggingTags) ResolveProviderTagSet(provider string) (*common.TagSet, error) {
+	if len(t.variants) == 0 {
+		return nil, nil
+	}
+
+	if variant, ok := t.variants[provider]; ok {
+		return variant.tagSet, nil
+	}
+
+	return t.variants[t.standard.DefaultProvider()].tagSet, nil
+}
+
+// ResolveDirTagSets returns the standard tags and the tag sets of the files of a directory. The standard is loaded
+// again for every directory its {{dir.*}} placeholders resolve to other values in.
+func (t *taggingTags) ResolveDirTagSets(dir string) (*common.TagSet, common.TagSetResolver, error) {
+	if !t.dirTemplates || sameDir(dir, t.dir) {
+		return &common.TagSet{Tags: t.JSON, TagExpressions: t.Expressions}, t, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	dirTags, ok := t.dirTags[dir]
+	if !ok {
+		log.Print("[INFO] Resolving the directory placeholders of the tag standard for ", dir)
+
+		standard, err := standards.LoadStandard(t.filePath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if dirTags, err = newTaggingTags(t.filePath, standard, dir, t.generateVariables); err != nil {
+			return nil, nil, err
+		}
+
+		t.dirTags[dir] = dirTags
+	}
+
+	return &common.TagSet{Tags: dirTags.JSON, TagExpressions: dirTags.Expressions}, dirTags, nil
+}
+
+func sameDir(dir string, other string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+
+	otherAbs, err := filepath.Abs(other)
+
+	return err == nil && abs == otherAbs
+}
@@ -0,0 +1,193 @@
+package terratag
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudyali/terratag/cli"
+	"github.com/cloudyali/terratag/internal/common"
+)
+
+func TestTaggingTags_ResolveTagSet(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "terratag-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	standard := `
+version: 1
+metadata:
+  description: "Resource rules test standard"
+cloud_provider: "aws"
+required_tags:
+  - key: "Environment"
+    default_value: "prod"
+  - key: "Owner"
+    default_value: "platform"
+optional_tags:
+  - key: "Backup"
+    default_value: "daily"
+global_excludes:
+  - "aws_iam_role"
+resource_rules:
+  - resource_types: ["aws_db_*"]
+    required_tags: ["Backup"]
+    excluded_tags: ["Owner"]
+    override_tags:
+      - key: "Environment"
+        default_value: "production-db"
+`
+
+	file := filepath.Join(tmpDir, "rules.yaml")
+	err = os.WriteFile(file, []byte(standard), 0644)
+	require.NoError(t, err)
+
+	loaded, err := loadTaggingTags(file, ".", false)
+	require.NoError(t, err)
+
+	tagSet, err := loaded.ResolveTagSet("aws_instance")
+	require.NoError(t, err)
+	require.NotNil(t, tagSet)
+	assert.Empty(t, tagSet.Name)
+	assert.Equal(t, loaded.JSON, tagSet.Tags)
+
+	tagSet, err = loaded.ResolveTagSet("aws_db_instance")
+	require.NoError(t, err)
+	require.NotNil(t, tagSet)
+	assert.Equal(t, "aws_db_instance", tagSet.Name)
+	assert.JSONEq(t, `{"Backup":"daily","Environment":"production-db"}`, tagSet.Tags)
+	assert.Equal(t, []string{"Owner"}, tagSet.ExcludedTags)
+
+	tagSet, err = loaded.ResolveTagSet("aws_iam_role")
+	require.NoError(t, err)
+	assert.Nil(t, tagSet)
+}
+
+func TestTaggingTags_MultiCloud(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	standard := `
+version: 1
+metadata:
+  description: "Multi-cloud test standard"
+cloud_provider: "aws"
+cloud_providers: ["gcp"]
+required_tags:
+  - key: "CostCenter"
+    default_value: "CC-1234"
+  - key: "Owner"
+    default_value: "platform"
+directory_overlays:
+  - path: "teams/data"
+    tags:
+      CostCenter: "CC-5678"
+resource_rules:
+  - resource_types: ["google_sql_*"]
+    excluded_tags: ["Owner"]
+provider_overrides:
+  gcp:
+    key_aliases:
+      CostCenter: "cost-center"
+      Owner: "owner"
+    override_tags:
+      - key: "CostCenter"
+        default_value: "cc-1234"
+`
+
+	file := filepath.Join(tmpDir, "multi-cloud.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(standard), 0644))
+
+	loaded, err := loadTaggingTags(file, ".", false)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"CostCenter":"CC-1234","Owner":"platform"}`, loaded.JSON)
+
+	tagSet, err := loaded.ResolveTagSet("aws_instance")
+	require.NoError(t, err)
+	require.NotNil(t, tagSet)
+	assert.Empty(t, tagSet.Name)
+
+	tagSet, err = loaded.ResolveTagSet("google_compute_instance")
+	require.NoError(t, err)
+	require.NotNil(t, tagSet)
+	assert.Equal(t, "gcp", tagSet.Name)
+	assert.JSONEq(t, `{"cost-center":"cc-1234","owner":"platform"}`, tagSet.Tags)
+
+	tagSet, err = loaded.ResolveTagSet("google_sql_database_instance")
+	require.NoError(t, err)
+	require.NotNil(t, tagSet)
+	assert.Equal(t, "google_sql_database_instance", tagSet.Name)
+	assert.JSONEq(t, `{"cost-center":"cc-1234"}`, tagSet.Tags)
+	assert.Equal(t, []string{"owner"}, tagSet.ExcludedTags)
+
+	tagSet, err = loaded.ResolveProviderTagSet("gcp")
+	require.NoError(t, err)
+	assert.Equal(t, "gcp", tagSet.Name)
+
+	// Overlay tags follow the key aliases of the provider of the resource type
+	args := &common.TaggingArgs{
+		Dir:            tmpDir,
+		Tags:           loaded.JSON,
+		TagExpressions: loaded.Expressions,
+		TagSets:        loaded,
+		Overlays:       loaded.Overlays,
+	}
+
+	fileArgs, err := fileTaggingArgs(filepath.Join(tmpDir, "teams", "data", "main.tf"), args)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"CostCenter":"CC-5678","Owner":"platform"}`, fileArgs.Tags)
+
+	tagSet, err = fileArgs.TagSets.ResolveTagSet("google_compute_instance")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"cost-center":"CC-5678","owner":"platform"}`, tagSet.Tags)
+
+	tagSet, err = fileArgs.TagSets.ResolveTagSet("google_sql_database_instance")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"cost-center":"CC-5678"}`, tagSet.Tags)
+}
+
+func TestTerratag_DirTemplatesPerStack(t *testing.T) {
+	useFakeSchemaCommand(t, "terragrunt")
+
+	dir := t.TempDir()
+
+	standard := `
+version: 1
+metadata:
+  description: "Stack templates test standard"
+cloud_provider: "aws"
+required_tags:
+  - key: "Stack"
+    default_value: "{{dir.name}}"
+`
+	tagsFile := filepath.Join(dir, "standard.yaml")
+	require.NoError(t, os.WriteFile(tagsFile, []byte(standard), 0644))
+
+	for _, stack := range []string{"network", "storage"} {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, stack), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, stack, "main.tf"), []byte(`resource "aws_s3_bucket" "this" {
+  bucket = "`+stack+`"
+}
+`), 0644))
+	}
+
+	_, err := Terratag(context.Background(), cli.Args{
+		TagsFile:        tagsFile,
+		Dir:             dir,
+		Type:            string(common.Terragrunt),
+		Rename:          true,
+		Strategy:        string(common.StrategyResource),
+		NoProviderCache: true,
+	})
+	require.NoError(t, err)
+
+	for _, stack := range []string{"network", "storage"} {
+		tagged, err := os.ReadFile(filepath.Join(dir, stack, "main.terratag.tf"))
+		require.NoError(t, err)
+
+		assert.Contains(t, string(tagged), `{"Stack"="`+stack+`"}`, "the stack %s is tagged with its own directory name", stack)
+	}
+}
@@ -0,0 +1,160 @@
+package terratag
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cloudyali/terratag/cli"
+	"github.com/cloudyali/terratag/internal/tagging"
+)
+
+// ResourceStatus is the outcome of tagging a single block
+type ResourceStatus string
+
+const (
+	ResourceTagged      ResourceStatus = "tagged"       // Tags were merged into the block
+	ResourceSkipped     ResourceStatus = "skipped"      // Taggable, but tagged some other way, excluded by the tag standard or ignored by an annotation
+	ResourceNotTaggable ResourceStatus = "not_taggable" // The resource type doesn't support tags
+	ResourceFiltered    ResourceStatus = "filtered"     // Excluded by -filter or -skip
+	ResourceError       ResourceStatus = "error"        // Tagging the block failed
+)
+
+const (
+	reasonExcludedByStandard  = "resource type is excluded by the tag standard"
+	reasonProviderDefaultTags = "tagged through provider default tags"
+	reasonIgnoreAnnotation    = "ignored by a terratag:ignore annotation"
+)
+
+// FileStatus is the outcome of tagging a single file
+type FileStatus string
+
+const (
+	FileTagged    FileStatus = "tagged"    // The tagged file was written (or diffed in dry-run mode)
+	FileUnchanged FileStatus = "unchanged" // No taggable resources found
+	FileSkipped   FileStatus = "skipped"   // Output of a previous run skipped with -skipTerratagFiles, or not processed before a cancellation
+	FileError     FileStatus = "error"     // Processing the file failed
+)
+
+// ResourceResult is the outcome of tagging a resource block, or a provider block with the provider strategy
+type ResourceResult struct {
+	Block        string         `json:"block"` // resource or provider
+	Type         string         `json:"type"`
+	Name         string         `json:"name,omitempty"`
+	Status       ResourceStatus `json:"status"`
+	Reason       string         `json:"reason,omitempty"`
+	TagAttribute string         `json:"tag_attribute,omitempty"` // Attribute the tags were merged into, e.g. tags or labels
+	Line         int            `json:"line,omitempty"`          // Line the block starts at in the original file
+}
+
+// FileResult is the outcome of tagging a file
+type FileResult struct {
+	Path      string           `json:"path"`
+	Status    FileStatus       `json:"status"`
+	Error     string           `json:"error,omitempty"`
+	Resources []ResourceResult `json:"resources"`
+}
+
+// TaggingSummary counts the processed and tagged resources and files of a run
+type TaggingSummary struct {
+	TotalResources  int `json:"total_resources"`
+	TaggedResources int `json:"tagged_resources"`
+	TotalFiles      int `json:"total_files"`
+	TaggedFiles     int `json:"tagged_files"`
+}
+
+// TaggingReport is the structured result of a tagging run, files are in processing order
+type TaggingReport struct {
+	DryRun  bool           `json:"dry_run"`
+	Files   []FileResult   `json:"files"`
+	Summary TaggingSummary `json:"summary"`
+}
+
+// newTaggingReport builds the report of a run from the counters of the processed files
+func newTaggingReport(args cli.Args, c counters) *TaggingReport {
+	report := &TaggingReport{
+		DryRun: args.DryRun,
+		Files:  c.files,
+		Summary: TaggingSummary{
+			TotalResources:  int(c.totalResources),
+			TaggedResources: int(c.taggedResources),
+			TotalFiles:      int(c.totalFiles),
+			TaggedFiles:     int(c.taggedFiles),
+		},
+	}
+
+	if report.Files == nil {
+		report.Files = []FileResult{}
+	}
+
+	return report
+}
+
+// WriteJSON writes the report as JSON to the output path, or to stdout for an empty path or "-"
+func (r *TaggingReport) WriteJSON(output string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tagging report to JSON: %w", err)
+	}
+
+	if output == "" || output == "-" {
+		fmt.Println(string(data))
+
+		return nil
+	}
+
+	if err := os.WriteFile(output, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write tagging report: %w", err)
+	}
+
+	return nil
+}
+
+// resourceResult builds the result of a resource block
+func resourceResult(labels []string, lines map[string]int, status ResourceStatus, reason string) ResourceResult {
+	result := ResourceResult{
+		Block:  "resource",
+		Status: status,
+		Reason: reason,
+	}
+
+	key := "resource"
+
+	if len(labels) > 0 {
+		result.Type = labels[0]
+		key += "." + labels[0]
+	}
+
+	if len(labels) > 1 {
+		result.Name = labels[1]
+		key += "." + labels[1]
+	}
+
+	result.Line = lines[key]
+
+	return result
+}
+
+// providerResult builds the result of a provider block tagged with provider default tags
+func providerResult(providerName string, lines map[string]int, status ResourceStatus) ResourceResult {
+	return ResourceResult{
+		Block:        "provider",
+		Type:         providerName,
+		Status:       status,
+		TagAttribute: tagging.GetProviderDefaultTagsAttribute(providerName),
+		Line:         lines["provider."+providerName],
+	}
+}
+
+// newFileResult builds the result of a file, a file without resource results reports an empty list
+func newFileResult(path string, status FileStatus, resources []ResourceResult) FileResult {
+	if resources == nil {
+		resources = []ResourceResult{}
+	}
+
+	return FileResult{
+		Path:      path,
+		Status:    status,
+		Resources: resources,
+	}
+}
@@ -1,13 +1,16 @@
 package terratag
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
-	"sync"
 	"sync/atomic"
 
 	"github.com/cloudyali/terratag/cli"
@@ -16,14 +19,17 @@ import (
 	"github.com/cloudyali/terratag/internal/convert"
 	"github.com/cloudyali/terratag/internal/file"
 	"github.com/cloudyali/terratag/internal/providers"
+	"github.com/cloudyali/terratag/internal/selector"
 	"github.com/cloudyali/terratag/internal/standards"
 	"github.com/cloudyali/terratag/internal/tag_keys"
 	"github.com/cloudyali/terratag/internal/tagging"
 	"github.com/cloudyali/terratag/internal/terraform"
+	"github.com/cloudyali/terratag/internal/trace"
 	"github.com/cloudyali/terratag/internal/tfschema"
 	"github.com/cloudyali/terratag/internal/utils"
 	"github.com/cloudyali/terratag/internal/validation"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type counters struct {
@@ -31,11 +37,17 @@ type counters struct {
 	taggedResources uint32
 	totalFiles      uint32
 	taggedFiles     uint32
+	diffs           []file.FileDiff
+	taggedPaths     []string         // Paths of the tagged files
+	resources       []ResourceResult // Results of the blocks of a single file
+	files           []FileResult
 }
 
-var pairRegex = regexp.MustCompile(`^([a-zA-Z][\w-]*)=([\w-]+)$`)
+// providersFilename is the file provider blocks are generated into when using the provider strategy.
+// It ends with terratag.tf so subsequent runs skip it like any other terratag output.
+const providersFilename = "terratag_providers.terratag.tf"
 
-var matchWaitGroup sync.WaitGroup
+var pairRegex = regexp.MustCompile(`^([a-zA-Z][\w-]*)=([\w-]+)$`)
 
 func (c *counters) Add(other counters) {
 	atomic.AddUint32(&c.totalResources, other.totalResources)
@@ -44,6 +56,17 @@ func (c *counters) Add(other counters) {
 	atomic.AddUint32(&c.taggedFiles, other.taggedFiles)
 }
 
+func (c *counters) addResource(result ResourceResult) {
+	c.resources = append(c.resources, result)
+}
+
+// failResource records the error of a resource block and returns the partial counters of the file with the error
+func (c *counters) failResource(labels []string, lines map[string]int, err error) (*counters, error) {
+	c.addResource(resourceResult(labels, lines, ResourceError, err.Error()))
+
+	return c, err
+}
+
 // TagLoadingError represents errors that occur during tag loading
 type TagLoadingError struct {
 	FilePath string
@@ -61,8 +84,21 @@ func (e *TagLoadingError) Unwrap() error {
 
 // loadTagsFromFile loads tags from a tag standardization file and returns them as JSON string
 func loadTagsFromFile(filePath string) (string, error) {
+	loaded, err := loadTaggingTags(filePath, ".", false)
+	if err != nil {
+		return "", err
+	}
+
+	return loaded.JSON, nil
+}
+
+// loadTaggingTags loads tags from a tag standardization file. Required tags without a default value, example
+// or allowed value get a placeholder value, or reference a generated Terraform variable if generateVariables is set.
+// Placeholders of default values ({{dir.name}}, {{env:NAME}}...) are resolved for dir, the tags of the files of
+// other directories are resolved by ResolveDirTagSets.
+func loadTaggingTags(filePath string, dir string, generateVariables bool) (*taggingTags, error) {
 	if filePath == "" {
-		return "", &TagLoadingError{
+		return nil, &TagLoadingError{
 			FilePath: filePath,
 			Cause:    "empty file path",
 			Err:      fmt.Errorf("tag standard file path cannot be empty"),
@@ -72,13 +108,13 @@ func loadTagsFromFile(filePath string) (string, error) {
 	// Check if file exists and is readable
 	if _, err := os.Stat(filePath); err != nil {
 		if os.IsNotExist(err) {
-			return "", &TagLoadingError{
+			return nil, &TagLoadingError{
 				FilePath: filePath,
 				Cause:    "file not found",
 				Err:      fmt.Errorf("tag standard file does not exist: %s", filePath),
 			}
 		}
-		return "", &TagLoadingError{
+		return nil, &TagLoadingError{
 			FilePath: filePath,
 			Cause:    "file access error",
 			Err:      fmt.Errorf("cannot access tag standard file: %w", err),
@@ -87,7 +123,7 @@ func loadTagsFromFile(filePath string) (string, error) {
 
 	standard, err := standards.LoadStandard(filePath)
 	if err != nil {
-		return "", &TagLoadingError{
+		return nil, &TagLoadingError{
 			FilePath: filePath,
 			Cause:    "invalid tag standard format",
 			Err:      err,
@@ -96,67 +132,91 @@ func loadTagsFromFile(filePath string) (string, error) {
 
 	// Validate that we have at least some tags defined
 	if len(standard.RequiredTags) == 0 && len(standard.OptionalTags) == 0 {
-		return "", &TagLoadingError{
+		return nil, &TagLoadingError{
 			FilePath: filePath,
 			Cause:    "no tags defined",
 			Err:      fmt.Errorf("tag standard file must define at least one required or optional tag"),
 		}
 	}
 
-	// Extract tags from the standard file
-	// For tagging mode, we'll use required tags and their default values
-	tags := make(map[string]string)
-	var missingValues []string
-	
-	// Add required tags with their default values
-	for _, tagSpec := range standard.RequiredTags {
-		if tagSpec.DefaultValue != "" {
-			tags[tagSpec.Key] = tagSpec.DefaultValue
-		} else if len(tagSpec.Examples) > 0 {
-			tags[tagSpec.Key] = tagSpec.Examples[0]
-			log.Printf("[INFO] Using example value '%s' for required tag '%s'", tagSpec.Examples[0], tagSpec.Key)
-		} else if len(tagSpec.AllowedValues) > 0 {
-			tags[tagSpec.Key] = tagSpec.AllowedValues[0]
-			log.Printf("[INFO] Using first allowed value '%s' for required tag '%s'", tagSpec.AllowedValues[0], tagSpec.Key)
-		} else {
-			// Track tags that need manual configuration
-			missingValues = append(missingValues, tagSpec.Key)
-			// Use a descriptive placeholder that indicates action needed
-			tags[tagSpec.Key] = fmt.Sprintf("CONFIGURE_%s_VALUE", strings.ToUpper(tagSpec.Key))
+	return newTaggingTags(filePath, standard, dir, generateVariables)
+}
+
+// newTaggingTags prepares the tags of a standard loaded from filePath for tagging the files of dir
+func newTaggingTags(filePath string, standard *standards.TagStandard, dir string, generateVariables bool) (*taggingTags, error) {
+	dirTemplates := standard.UsesDirTemplates()
+
+	if err := standard.ResolveTemplates(standards.NewTemplateContext(dir)); err != nil {
+		return nil, &TagLoadingError{
+			FilePath: filePath,
+			Cause:    "invalid tag template",
+			Err:      err,
 		}
 	}
-	
-	// Add optional tags with their default values if specified
-	for _, tagSpec := range standard.OptionalTags {
-		if tagSpec.DefaultValue != "" {
-			tags[tagSpec.Key] = tagSpec.DefaultValue
-		}
+
+	// Extract tags from the standard file
+	// For tagging mode, we'll use required tags and their default values
+	loaded := &taggingTags{
+		standard:          standard,
+		filePath:          filePath,
+		dir:               dir,
+		dirTemplates:      dirTemplates,
+		generateVariables: generateVariables,
+		tagSets:           map[string]*common.TagSet{},
+		dirTags:           map[string]*taggingTags{},
 	}
 
+	tags, expressions, missingValues := loaded.tagValues(standard.RequiredTags, standard.OptionalTags)
+
 	// Warn about tags that need manual configuration
 	if len(missingValues) > 0 {
 		log.Printf("[WARN] The following required tags need manual configuration in your tag standard file:")
 		for _, key := range missingValues {
 			log.Printf("[WARN]   - %s: Add 'default_value', 'examples', or 'allowed_values' to the tag specification", key)
 		}
-		log.Printf("[WARN] Placeholder values have been used. Update your tag standard file before applying tags.")
+		log.Printf("[WARN] Placeholder values have been used. Update your tag standard file before applying tags, or use -generate-variables.")
 	}
 
 	// Convert to JSON string
 	tagsJSON, err := json.Marshal(tags)
 	if err != nil {
-		return "", &TagLoadingError{
+		return nil, &TagLoadingError{
 			FilePath: filePath,
 			Cause:    "JSON serialization failed",
 			Err:      fmt.Errorf("failed to marshal tags to JSON: %w", err),
 		}
 	}
 
+	loaded.JSON = string(tagsJSON)
+	loaded.Expressions = expressions
+
+	if standard.IsMultiCloud() {
+		if err := loaded.addVariants(); err != nil {
+			return nil, &TagLoadingError{
+				FilePath: filePath,
+				Cause:    "JSON serialization failed",
+				Err:      err,
+			}
+		}
+	}
+
+	for _, overlay := range standard.DirectoryOverlays {
+		loaded.Overlays = append(loaded.Overlays, common.TagOverlay{Pattern: overlay.Path, Tags: overlay.Tags})
+	}
+
 	log.Printf("[INFO] Successfully loaded %d tags from standard file: %s", len(tags), filePath)
-	return string(tagsJSON), nil
+	return loaded, nil
 }
 
-func Terratag(args cli.Args) error {
+// Terratag tags the resources of args.Dir and returns a report of the outcome of every file and resource.
+// Validation-only mode, the tag key migration and the lifecycle commands don't tag anything and return a nil report.
+// Cancelling ctx kills running terraform subprocesses and stops tagging between files: files are either tagged
+// completely or left untouched, and the partial report is returned together with the context error.
+func Terratag(ctx context.Context, args cli.Args) (*TaggingReport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Create cleanup manager for the operation
 	cleanupMgr := cleanup.NewCleanupManager(nil)
 	defer func() {
@@ -167,37 +227,77 @@ func Terratag(args cli.Args) error {
 
 	// Handle validation-only mode
 	if args.ValidateOnly {
-		return validation.ValidateStandards(args)
+		return nil, validation.ValidateStandards(ctx, args)
+	}
+
+	// Handle revert/finalize of files left behind by a previous run
+	if args.Command != "" {
+		return nil, runLifecycleCommand(args)
+	}
+
+	// Handle renaming and removing existing tag keys
+	if args.Migrate != "" {
+		return nil, migrateTagKeys(ctx, args)
 	}
 
 	// Load tags from the standardization file
-	tagsJSON, err := loadTagsFromFile(args.TagsFile)
+	loaded, err := loadTaggingTags(args.TagsFile, args.Dir, args.GenerateVariables)
 	if err != nil {
-		return fmt.Errorf("failed to load tags from file: %w", err)
+		return nil, fmt.Errorf("failed to load tags from file: %w", err)
 	}
 
-	log.Printf("[INFO] Loaded tags from %s: %s", args.TagsFile, tagsJSON)
+	log.Printf("[INFO] Loaded tags from %s: %s", args.TagsFile, loaded.JSON)
 
 	if err := terraform.ValidateInitRun(args.Dir, args.Type); err != nil {
-		return err
+		return nil, err
 	}
 
 	matches, err := terraform.GetFilePaths(args.Dir, args.Type)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	selectors, err := selector.Parse(args.Filter, args.Skip)
+	if err != nil {
+		return nil, err
 	}
 
 	taggingArgs := &common.TaggingArgs{
 		Filter:              args.Filter,
 		Skip:                args.Skip,
+		Selectors:           selectors,
 		Dir:                 args.Dir,
-		Tags:                tagsJSON, // Use the loaded tags from file
+		Tags:                loaded.JSON, // Use the loaded tags from file
+		TagExpressions:      loaded.Expressions,
 		Matches:             matches,
 		IsSkipTerratagFiles: args.IsSkipTerratagFiles,
 		Rename:              args.Rename,
 		IACType:             common.IACType(args.Type),
 		DefaultToTerraform:  args.DefaultToTerraform,
 		KeepExistingTags:    args.KeepExistingTags,
+		DryRun:              args.DryRun,
+		Strategy:            common.TaggingStrategy(args.Strategy),
+		TagSets:             loaded,
+		Parallelism:         args.Parallelism,
+		Overlays:            loaded.Overlays,
+		CloudProvider:       loaded.standard.DefaultProvider(),
+	}
+
+	if loaded.standard.SanitizeLabelValues {
+		taggingArgs.LabelSanitizer = providers.NewLabelValueSanitizer()
+	}
+
+	if taggingArgs.ModuleAddresses, err = terraform.GetModuleAddresses(args.Dir); err != nil {
+		return nil, fmt.Errorf("failed to read the modules of %s: %w", args.Dir, err)
+	}
+
+	if args.TraceTags {
+		tracer, err := trace.NewTracer(ctx, args.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the git repository of %s: %w", args.Dir, err)
+		}
+
+		taggingArgs.Tracer = tracer
 	}
 
 	// Clean up expired provider cache entries (only if cache is enabled)
@@ -211,7 +311,7 @@ func Terratag(args cli.Args) error {
 	// Ensure terraform is initialized if auto-init is enabled
 	if args.AutoInit {
 		log.Printf("[INFO] Auto-init enabled, ensuring terraform is properly initialized")
-		if err := terraform.EnsureInitialized(args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache); err != nil {
+		if err := terraform.EnsureInitialized(ctx, args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache, args.CommandTimeouts()); err != nil {
 			log.Printf("[WARN] Auto-initialization failed: %v", err)
 			log.Printf("[INFO] Continuing with manual initialization check")
 		} else {
@@ -220,17 +320,17 @@ func Terratag(args cli.Args) error {
 	}
 
 	// Initialize provider schemas before processing files
-	if err := tfschema.InitProviderSchemasWithCache(args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache); err != nil {
+	if err := tfschema.InitProviderSchemasWithCache(ctx, args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache, args.CommandTimeouts()); err != nil {
 		log.Printf("[WARN] Failed to pre-initialize provider schemas: %v", err)
 		
 		// If auto-init is enabled and schema init failed, try to diagnose and fix
 		if args.AutoInit {
 			log.Printf("[INFO] Attempting to resolve schema initialization issue with auto-init")
-			if initErr := terraform.EnsureInitialized(args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache); initErr != nil {
+			if initErr := terraform.EnsureInitialized(ctx, args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache, args.CommandTimeouts()); initErr != nil {
 				log.Printf("[WARN] Auto-init resolution failed: %v", initErr)
 			} else {
 				// Retry schema initialization after successful init
-				if retryErr := tfschema.InitProviderSchemasWithCache(args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache); retryErr != nil {
+				if retryErr := tfschema.InitProviderSchemasWithCache(ctx, args.Dir, common.IACType(args.Type), args.DefaultToTerraform, !args.NoProviderCache, args.CommandTimeouts()); retryErr != nil {
 					log.Printf("[WARN] Schema initialization still failed after auto-init: %v", retryErr)
 				} else {
 					log.Printf("[INFO] Schema initialization succeeded after auto-init")
@@ -241,62 +341,226 @@ func Terratag(args cli.Args) error {
 		// Continue even if initialization fails, as getResourceSchema will try again on-demand
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Register cleanup for backup files if they won't be renamed
-	if !args.Rename {
+	if !args.Rename && !args.DryRun {
 		cleanupMgr.AddCleanupHook(func() error {
 			return cleanupMgr.CleanupByType(cleanup.ResourceTypeBackupFile)
 		})
 	}
 
-	counters := tagDirectoryResources(taggingArgs)
+	counters := tagDirectoryResources(ctx, taggingArgs)
+
+	if err := ctx.Err(); err != nil {
+		log.Print("[WARN] Tagging cancelled, ", counters.taggedFiles, " file/s were tagged before the cancellation")
+
+		return newTaggingReport(args, counters), err
+	}
+
+	if taggingArgs.Strategy == common.StrategyProvider {
+		providersCounters, err := tagMissingProviders(taggingArgs)
+		if err != nil {
+			return nil, err
+		}
+
+		counters.Add(*providersCounters)
+		counters.diffs = append(counters.diffs, providersCounters.diffs...)
+		counters.files = append(counters.files, providersCounters.files...)
+	}
+
+	if len(loaded.Variables) > 0 {
+		variablesDiffs, err := writeTagVariables(taggingArgs, loaded.Variables, counters.taggedPaths)
+		if err != nil {
+			return nil, err
+		}
+
+		counters.diffs = append(counters.diffs, variablesDiffs...)
+	}
 
 	log.Print("[INFO] Summary:")
 	log.Print("[INFO] Tagged ", counters.taggedResources, " resource/s (out of ", counters.totalResources, " resource/s processed)")
 	log.Print("[INFO] In ", counters.taggedFiles, " file/s (out of ", counters.totalFiles, " file/s processed)")
 
+	report := newTaggingReport(args, counters)
+
+	if args.DryRun {
+		if err := printDiffs(counters.diffs, args.DiffFormat); err != nil {
+			return report, err
+		}
+
+		if args.FailOnDiff && len(counters.diffs) > 0 {
+			return report, fmt.Errorf("dry run found pending tag changes in %d file/s", len(counters.diffs))
+		}
+	}
+
+	return report, nil
+}
+
+// runLifecycleCommand restores or finalizes the .bak and .terratag.tf files under args.Dir
+func runLifecycleCommand(args cli.Args) error {
+	var result *file.LifecycleResult
+	var err error
+
+	switch args.Command {
+	case cli.CommandRevert:
+		result, err = file.Revert(args.Dir)
+	case cli.CommandFinalize:
+		result, err = file.Finalize(args.Dir)
+	default:
+		return fmt.Errorf("unsupported command %s", args.Command)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", args.Command, err)
+	}
+
+	log.Print("[INFO] Summary:")
+	log.Print("[INFO] Restored ", len(result.Restored), " file/s, renamed ", len(result.Renamed), " file/s, removed ", len(result.Removed), " file/s")
+
 	return nil
 }
 
-func tagDirectoryResources(args *common.TaggingArgs) counters {
-	var total counters
+// printDiffs writes the dry-run diffs to stdout in the requested format
+func printDiffs(diffs []file.FileDiff, format string) error {
+	if format == "json" {
+		if diffs == nil {
+			diffs = []file.FileDiff{}
+		}
+
+		data, err := json.MarshalIndent(diffs, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal diffs to JSON: %w", err)
+		}
+
+		fmt.Println(string(data))
+
+		return nil
+	}
+
+	for _, diff := range diffs {
+		fmt.Print(diff.Diff)
+	}
+
+	return nil
+}
+
+func tagDirectoryResources(ctx context.Context, args *common.TaggingArgs) counters {
+	var paths []string
+
+	skipped := map[string]bool{}
 
 	for _, path := range args.Matches {
-		if args.IsSkipTerratagFiles && strings.HasSuffix(path, "terratag.tf") {
+		if args.IsSkipTerratagFiles && file.IsTerratagFile(path) {
 			log.Print("[INFO] Skipping file ", path, " as it's already tagged")
+
+			skipped[path] = true
 		} else {
-			matchWaitGroup.Add(1)
+			paths = append(paths, path)
+		}
+	}
 
-			go func(path string) {
-				defer matchWaitGroup.Done()
+	results := utils.ParallelMap(paths, args.Parallelism, func(path string) counters {
+		// Files not started before a cancellation are left untouched
+		if ctx.Err() != nil {
+			cancelled := newFileResult(path, FileSkipped, nil)
+			cancelled.Error = ctx.Err().Error()
 
-				total.Add(counters{
-					totalFiles: 1,
-				})
+			return counters{files: []FileResult{cancelled}}
+		}
 
-				defer func() {
-					if r := recover(); r != nil {
-						log.Printf("[ERROR] failed to process %s due to an exception\n%v", path, r)
-					}
-				}()
+		return tagFile(path, args)
+	})
 
-				perFile, err := tagFileResources(path, args)
-				if err != nil {
-					log.Printf("[ERROR] failed to process %s due to an error\n%v", path, err)
+	var total counters
 
-					return
-				}
+	for _, perFile := range results {
+		total.Add(perFile)
+		total.diffs = append(total.diffs, perFile.diffs...)
+		total.taggedPaths = append(total.taggedPaths, perFile.taggedPaths...)
+	}
+
+	// Report the files in the order they were matched, skipped files included
+	next := 0
 
-				total.Add(*perFile)
-			}(path)
+	for _, path := range args.Matches {
+		if skipped[path] {
+			total.files = append(total.files, newFileResult(path, FileSkipped, nil))
+
+			continue
 		}
+
+		total.files = append(total.files, results[next].files...)
+		next++
 	}
 
-	matchWaitGroup.Wait()
+	sort.Slice(total.diffs, func(i, j int) bool {
+		return total.diffs[i].Path < total.diffs[j].Path
+	})
 
 	return total
 }
 
+// tagFile tags the resources of a single file. A file that fails to process is logged and only counted as processed,
+// its report keeps the results of the blocks processed before the failure.
+func tagFile(path string, args *common.TaggingArgs) (perFile counters) {
+	perFile = counters{totalFiles: 1}
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[ERROR] failed to process %s due to an exception\n%v", path, r)
+
+			failed := newFileResult(path, FileError, nil)
+			failed.Error = fmt.Sprint(r)
+
+			perFile = counters{totalFiles: 1, files: []FileResult{failed}}
+		}
+	}()
+
+	fileArgs, err := fileTaggingArgs(path, args)
+
+	var result *counters
+	if err == nil {
+		result, err = tagFileResources(path, fileArgs)
+	}
+
+	if err != nil {
+		log.Printf("[ERROR] failed to process %s due to an error\n%v", path, err)
+
+		var resources []ResourceResult
+		if result != nil {
+			resources = result.resources
+		}
+
+		failed := newFileResult(path, FileError, resources)
+		failed.Error = err.Error()
+
+		perFile.files = []FileResult{failed}
+
+		return perFile
+	}
+
+	perFile = *result
+	perFile.totalFiles += 1
+
+	status := FileUnchanged
+	if perFile.taggedFiles > 0 {
+		status = FileTagged
+	}
+
+	perFile.files = []FileResult{newFileResult(path, status, perFile.resources)}
+	perFile.resources = nil
+
+	return perFile
+}
+
 func tagFileResources(path string, args *common.TaggingArgs) (*counters, error) {
+	if file.IsJSONFile(path) {
+		return tagJSONFileResources(path, args)
+	}
+
 	perFileCounters := counters{}
 
 	log.Print("[INFO] Processing file ", path)
@@ -308,110 +572,156 @@ func tagFileResources(path string, args *common.TaggingArgs) (*counters, error)
 		return nil, err
 	}
 
-	filename := file.GetFilename(path)
+	fileTagging, err := newFileTagging(path, args)
+	if err != nil {
+		return nil, err
+	}
+
+	filename, lines := fileTagging.filename, fileTagging.lines
 
-	hclMap, err := toHclMap(args.Tags)
+	hclMap, err := toHclMap(args.Tags, args.TagExpressions)
 	if err != nil {
 		return nil, err
 	}
 
 	terratag := common.TerratagLocal{
-		Found: map[string]hclwrite.Tokens{},
-		Added: hclMap,
+		Found:        map[string]hclwrite.Tokens{},
+		Added:        hclMap,
+		AddedTagSets: map[string]string{},
+		Trace:        map[string]string{},
 	}
 
+	existingAdded := map[string]*hclwrite.Attribute{}
+
 	for _, resource := range hcl.Body().Blocks() {
 		switch resource.Type() {
 		case "resource":
-			log.Print("[INFO] Processing resource ", resource.Labels())
-
-			perFileCounters.totalResources += 1
-
-			matched, err := regexp.MatchString(args.Filter, resource.Labels()[0])
+			plan, err := fileTagging.resourceTagging(&perFileCounters, resource, func(tagId string) ([]string, bool, error) {
+				return existingTagKeys(resource, tagId)
+			})
 			if err != nil {
-				return nil, err
+				return &perFileCounters, err
 			}
 
-			if !matched {
-				log.Print("[INFO] Resource excluded by filter, skipping.", resource.Labels())
-
+			if plan == nil {
 				continue
 			}
 
-			if args.Skip != "" {
-				matched, err = regexp.MatchString(args.Skip, resource.Labels()[0])
+			tagSet := plan.tagSet
+
+			if tagSet.Name != "" && terratag.AddedTagSets[tagSet.Name] == "" {
+				tagSetHclMap, err := toHclMap(tagSet.Tags, tagSet.TagExpressions)
 				if err != nil {
-					return nil, err
+					return perFileCounters.failResource(resource.Labels(), lines, err)
 				}
 
-				if matched {
-					log.Print("[INFO] Resource excluded by skip, skipping.", resource.Labels())
+				terratag.AddedTagSets[tagSet.Name] = tagSetHclMap
+			}
 
-					continue
+			var traceTagsKey string
+			if plan.traceTags != nil {
+				traceJSON, err := json.Marshal(plan.traceTags)
+				if err != nil {
+					return perFileCounters.failResource(resource.Labels(), lines, err)
 				}
+
+				traceHclMap, err := toHclMap(string(traceJSON), nil)
+				if err != nil {
+					return perFileCounters.failResource(resource.Labels(), lines, err)
+				}
+
+				traceTagsKey = tag_keys.GetResourceTraceTagsKey(filename, resource)
+				terratag.Trace[traceTagsKey] = traceHclMap
 			}
 
-			isTaggable, err := tfschema.IsTaggable(args.Dir, *resource)
+			result, err := tagging.TagResource(tagging.TagBlockArgs{
+				Filename:         filename,
+				Block:            resource,
+				Tags:             tagSet.Tags,
+				TagExpressions:   tagSet.TagExpressions,
+				TagSet:           tagSet.Name,
+				Terratag:         terratag,
+				TagId:            plan.tagId,
+				KeepExistingTags: args.KeepExistingTags,
+				TraceTagsKey:     traceTagsKey,
+			})
 			if err != nil {
-				return nil, err
+				return perFileCounters.failResource(resource.Labels(), lines, err)
 			}
 
-			if isTaggable {
-				log.Print("[INFO] Resource taggable, processing...", resource.Labels())
+			perFileCounters.addResource(fileTagging.taggedResult(resource.Labels(), plan.tagId))
 
-				perFileCounters.taggedResources += 1
+			swappedTagsStrings = append(swappedTagsStrings, result.SwappedTagsStrings...)
+		case "provider":
+			if args.Strategy != common.StrategyProvider || !tagging.HasProviderDefaultTags(resource.Labels()[0]) {
+				continue
+			}
 
-				result, err := tagging.TagResource(tagging.TagBlockArgs{
-					Filename:         filename,
-					Block:            resource,
-					Tags:             args.Tags,
-					Terratag:         terratag,
-					TagId:            providers.GetTagIdByResource(terraform.GetResourceType(*resource)),
-					KeepExistingTags: args.KeepExistingTags,
-				})
-				if err != nil {
-					return nil, err
-				}
+			log.Print("[INFO] Adding default tags to provider ", resource.Labels())
 
-				swappedTagsStrings = append(swappedTagsStrings, result.SwappedTagsStrings...)
-			} else {
-				log.Print("[INFO] Resource not taggable, skipping.", resource.Labels())
+			providerName := resource.Labels()[0]
+
+			result, err := tagProvider(resource, filename, terratag, args)
+			if err != nil {
+				failed := providerResult(providerName, lines, ResourceError)
+				failed.Reason = err.Error()
+				perFileCounters.addResource(failed)
+
+				return &perFileCounters, err
 			}
+
+			perFileCounters.addResource(providerResult(providerName, lines, ResourceTagged))
+
+			swappedTagsStrings = append(swappedTagsStrings, result.SwappedTagsStrings...)
 		case "locals":
-			// Checks if terratag_added_* exists.
-			// If it exists no need to append it again to Terratag file.
-			// Instead should override it.
-			attributes := resource.Body().Attributes()
-			key := tag_keys.GetTerratagAddedKey(filename)
-
-			for attributeKey, attribute := range attributes {
-				if attributeKey == key {
-					mergedAdded, err := convert.MergeTerratagLocals(attribute, terratag.Added)
-					if err != nil {
-						return nil, err
-					}
-
-					terratag.Added = mergedAdded
-
-					break
+			// Checks if terratag_added_* locals exist.
+			// If they exist no need to append them again to Terratag file.
+			// Instead should override them.
+			for attributeKey, attribute := range resource.Body().Attributes() {
+				if strings.HasPrefix(attributeKey, tag_keys.GetTerratagAddedKey(filename)) {
+					existingAdded[attributeKey] = attribute
 				}
 			}
 		}
 	}
 
+	if attribute, ok := existingAdded[tag_keys.GetTerratagAddedKey(filename)]; ok {
+		mergedAdded, err := convert.MergeTerratagLocals(attribute, terratag.Added)
+		if err != nil {
+			return nil, err
+		}
+
+		terratag.Added = mergedAdded
+	}
+
+	for tagSetName, added := range terratag.AddedTagSets {
+		if attribute, ok := existingAdded[tag_keys.GetTerratagAddedTagSetKey(filename, tagSetName)]; ok {
+			mergedAdded, err := convert.MergeTerratagLocals(attribute, added)
+			if err != nil {
+				return nil, err
+			}
+
+			terratag.AddedTagSets[tagSetName] = mergedAdded
+		}
+	}
+
 	if len(swappedTagsStrings) > 0 {
 		convert.AppendLocalsBlock(hcl, filename, terratag)
 
 		text := string(hcl.Bytes())
 
 		swappedTagsStrings = append(swappedTagsStrings, terratag.Added)
+		for _, added := range terratag.AddedTagSets {
+			swappedTagsStrings = append(swappedTagsStrings, added)
+		}
+		for _, traceTags := range terratag.Trace {
+			swappedTagsStrings = append(swappedTagsStrings, traceTags)
+		}
 		text = convert.UnquoteTagsAttribute(swappedTagsStrings, text)
 
-		if err := file.ReplaceWithTerratagFile(path, text, args.Rename); err != nil {
+		if err := writeTaggedFile(path, text, args, &perFileCounters); err != nil {
 			return nil, err
 		}
-
-		perFileCounters.taggedFiles = 1
 	} else {
 		log.Print("[INFO] No taggable resources found in file ", path, " - skipping")
 	}
@@ -419,7 +729,513 @@ func tagFileResources(path string, args *common.TaggingArgs) (*counters, error)
 	return &perFileCounters, nil
 }
 
-func toHclMap(tags string) (string, error) {
+// tagJSONFileResources tags the resources of a JSON syntax (.tf.json) configuration file.
+// Literal tag objects are merged in place, tag expressions are merged with the terratag_added_* local.
+func tagJSONFileResources(path string, args *common.TaggingArgs) (*counters, error) {
+	perFileCounters := counters{}
+
+	log.Print("[INFO] Processing file ", path)
+
+	jsonFile, err := file.ReadJSONFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileTagging, err := newFileTagging(path, args)
+	if err != nil {
+		return nil, err
+	}
+
+	filename, lines := fileTagging.filename, fileTagging.lines
+
+	tags, err := toJSONTagsMap(args.Tags, args.TagExpressions)
+	if err != nil {
+		return nil, err
+	}
+
+	tagged := false
+	needsLocal := false
+
+	// Tags of the resource rule specific tag sets whose terratag_added_* local is referenced
+	tagSetLocals := map[string]map[string]string{}
+
+	// Trace tags of the resources whose terratag_trace_* local is referenced
+	traceLocals := map[string]map[string]string{}
+
+	for _, resource := range jsonFile.Blocks("resource") {
+		block := resource.HCLBlock("resource")
+
+		plan, err := fileTagging.resourceTagging(&perFileCounters, block, func(tagId string) ([]string, bool, error) {
+			existing, known := existingJSONTagKeys(resource.Body, tagId)
+
+			return existing, known, nil
+		})
+		if err != nil {
+			return &perFileCounters, err
+		}
+
+		if plan == nil {
+			continue
+		}
+
+		tagSet := plan.tagSet
+
+		resourceTags := tags
+		if tagSet.Name != "" {
+			if resourceTags, err = toJSONTagsMap(tagSet.Tags, tagSet.TagExpressions); err != nil {
+				return perFileCounters.failResource(resource.Labels, lines, err)
+			}
+		}
+
+		var traceTagsKey string
+		if plan.traceTags != nil {
+			traceTagsKey = tag_keys.GetResourceTraceTagsKey(filename, block)
+		}
+
+		usesLocal, err := tagging.TagJSONBlock(tagging.TagJSONBlockArgs{
+			Filename:         filename,
+			Body:             resource.Body,
+			Tags:             resourceTags,
+			TagSet:           tagSet.Name,
+			TagId:            plan.tagId,
+			KeepExistingTags: args.KeepExistingTags,
+			TraceTags:        plan.traceTags,
+			TraceTagsKey:     traceTagsKey,
+		})
+		if err != nil {
+			return perFileCounters.failResource(resource.Labels, lines, fmt.Errorf("failed to tag %s: %w", strings.Join(resource.Labels, "."), err))
+		}
+
+		perFileCounters.addResource(fileTagging.taggedResult(resource.Labels, plan.tagId))
+
+		tagged = true
+
+		if usesLocal && tagSet.Name != "" {
+			tagSetLocals[tagSet.Name] = resourceTags
+		} else {
+			needsLocal = needsLocal || usesLocal
+		}
+
+		if usesLocal && plan.traceTags != nil {
+			traceLocals[traceTagsKey] = plan.traceTags
+		}
+	}
+
+	if args.Strategy == common.StrategyProvider {
+		for _, provider := range jsonFile.Blocks("provider") {
+			if !tagging.HasProviderDefaultTags(provider.Labels[0]) {
+				continue
+			}
+
+			log.Print("[INFO] Adding default tags to provider ", provider.Labels)
+
+			providerTags := tags
+
+			tagSet, err := providerTagSet(provider.Labels[0], args)
+			if err == nil && tagSet.Name != "" {
+				providerTags, err = toJSONTagsMap(tagSet.Tags, tagSet.TagExpressions)
+			}
+
+			var usesLocal bool
+			if err == nil {
+				usesLocal, err = tagging.TagJSONProviderBlock(provider.Labels[0], tagging.TagJSONBlockArgs{
+					Filename:         filename,
+					Body:             provider.Body,
+					Tags:             providerTags,
+					TagSet:           tagSet.Name,
+					KeepExistingTags: args.KeepExistingTags,
+				})
+			}
+			if err != nil {
+				failed := providerResult(provider.Labels[0], lines, ResourceError)
+				failed.Reason = err.Error()
+				perFileCounters.addResource(failed)
+
+				return &perFileCounters, fmt.Errorf("failed to tag provider %s: %w", provider.Labels[0], err)
+			}
+
+			perFileCounters.addResource(providerResult(provider.Labels[0], lines, ResourceTagged))
+
+			tagged = true
+
+			if usesLocal && tagSet.Name != "" {
+				tagSetLocals[tagSet.Name] = providerTags
+			} else {
+				needsLocal = needsLocal || usesLocal
+			}
+		}
+	}
+
+	if !tagged {
+		log.Print("[INFO] No taggable resources found in file ", path, " - skipping")
+
+		return &perFileCounters, nil
+	}
+
+	if needsLocal {
+		local := make(map[string]interface{}, len(tags))
+		for key, value := range tags {
+			local[key] = value
+		}
+
+		jsonFile.SetLocal(tag_keys.GetTerratagAddedKey(filename), local)
+	}
+
+	for tagSetName, tagSetTags := range tagSetLocals {
+		local := make(map[string]interface{}, len(tagSetTags))
+		for key, value := range tagSetTags {
+			local[key] = value
+		}
+
+		jsonFile.SetLocal(tag_keys.GetTerratagAddedTagSetKey(filename, tagSetName), local)
+	}
+
+	for traceTagsKey, traceTags := range traceLocals {
+		local := make(map[string]interface{}, len(traceTags))
+		for key, value := range traceTags {
+			local[key] = value
+		}
+
+		jsonFile.SetLocal(traceTagsKey, local)
+	}
+
+	text, err := jsonFile.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeTaggedFile(path, string(text), args, &perFileCounters); err != nil {
+		return nil, err
+	}
+
+	return &perFileCounters, nil
+}
+
+// resolveTagSet returns the tags to apply to a resource type, or nil if it is excluded from tagging.
+// Every resource type gets the standard tags when no tag set resolver is configured.
+func resolveTagSet(args *common.TaggingArgs, resourceType string) (*common.TagSet, error) {
+	tagSet := &common.TagSet{Tags: args.Tags, TagExpressions: args.TagExpressions}
+
+	if args.TagSets != nil {
+		var err error
+		if tagSet, err = args.TagSets.ResolveTagSet(resourceType); err != nil {
+			return nil, err
+		}
+	}
+
+	return resourceLabelTagSet(tagSet, resourceType, args)
+}
+
+// resourceTraceTags returns the traceability tags of a resource, or nil if trace tags are disabled or the tags
+// of the resource type can't be merged with them. Google label values are sanitized.
+func resourceTraceTags(args *common.TaggingArgs, traces map[string]map[string]string, labels []string, tagId string) map[string]string {
+	if args.Tracer == nil {
+		return nil
+	}
+
+	if !tagging.SupportsTraceTags(labels[0]) {
+		log.Print("[INFO] Trace tags aren't supported for ", labels[0], ", skipping them.", labels)
+
+		return nil
+	}
+
+	traceTags, ok := traces[strings.Join(labels, ".")]
+	if !ok {
+		return nil
+	}
+
+	if tagId == "labels" {
+		return trace.LabelValues(traceTags)
+	}
+
+	return traceTags
+}
+
+// logProviderTagSet explains why a resource covered by provider default tags is tagged individually
+func logProviderTagSet(labels []string, tagSet *common.TagSet) {
+	log.Print("[INFO] Resource has resource rule specific tags, tagging it individually.", labels)
+
+	if len(tagSet.ExcludedTags) > 0 {
+		log.Print("[WARN] Excluded tags ", tagSet.ExcludedTags, " are still inherited from the provider default tags.", labels)
+	}
+}
+
+// writeTaggedFile writes the tagged content of a file, or records its diff in dry-run mode
+func writeTaggedFile(path string, text string, args *common.TaggingArgs, perFileCounters *counters) error {
+	if args.DryRun {
+		diff, err := file.UnifiedDiff(path, text, args.Rename)
+		if err != nil {
+			return err
+		}
+
+		if diff.Diff != "" {
+			perFileCounters.diffs = append(perFileCounters.diffs, *diff)
+		}
+	} else if err := file.ReplaceWithTerratagFile(path, text, args.Rename); err != nil {
+		return err
+	}
+
+	perFileCounters.taggedFiles = 1
+	perFileCounters.taggedPaths = []string{path}
+
+	return nil
+}
+
+// tagProvider adds the default tags of a provider block, the tags of its tag set are added to the
+// terratag_added_* locals of the file
+func tagProvider(provider *hclwrite.Block, filename string, terratag common.TerratagLocal, args *common.TaggingArgs) (*tagging.Result, error) {
+	tagSet, err := providerTagSet(provider.Labels()[0], args)
+	if err != nil {
+		return nil, err
+	}
+
+	if tagSet.Name != "" && terratag.AddedTagSets[tagSet.Name] == "" {
+		if terratag.AddedTagSets[tagSet.Name], err = toHclMap(tagSet.Tags, tagSet.TagExpressions); err != nil {
+			return nil, err
+		}
+	}
+
+	return tagging.TagProviderBlock(tagging.TagBlockArgs{
+		Filename:         filename,
+		Block:            provider,
+		Tags:             tagSet.Tags,
+		TagExpressions:   tagSet.TagExpressions,
+		TagSet:           tagSet.Name,
+		Terratag:         terratag,
+		KeepExistingTags: args.KeepExistingTags,
+	})
+}
+
+// tagMissingProviders creates a provider block with default tags for every provider that has resources
+// relying on provider default tags but no provider block in the root module.
+// Provider blocks are never created in child modules, they inherit the configuration of the root module.
+func tagMissingProviders(args *common.TaggingArgs) (*counters, error) {
+	perFileCounters := counters{}
+
+	rootDir, err := filepath.EvalSymlinks(args.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	declared := map[string]bool{}
+	used := map[string]bool{}
+
+	for _, path := range args.Matches {
+		if file.IsJSONFile(path) {
+			jsonFile, err := file.ReadJSONFile(path)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, provider := range jsonFile.Blocks("provider") {
+				if filepath.Dir(path) == rootDir {
+					declared[provider.Labels[0]] = true
+				}
+			}
+
+			for _, resource := range jsonFile.Blocks("resource") {
+				if providerName := tagging.GetDefaultTagsProviderName(resource.Labels[0]); providerName != "" {
+					used[providerName] = true
+				}
+			}
+
+			continue
+		}
+
+		hcl, err := file.ReadHCLFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, block := range hcl.Body().Blocks() {
+			switch block.Type() {
+			case "provider":
+				if filepath.Dir(path) == rootDir {
+					declared[block.Labels()[0]] = true
+				}
+			case "resource":
+				if providerName := tagging.GetDefaultTagsProviderName(block.Labels()[0]); providerName != "" {
+					used[providerName] = true
+				}
+			}
+		}
+	}
+
+	var missing []string
+
+	for providerName := range used {
+		if !declared[providerName] {
+			missing = append(missing, providerName)
+		}
+	}
+
+	if len(missing) == 0 {
+		return &perFileCounters, nil
+	}
+
+	sort.Strings(missing)
+
+	path := filepath.Join(args.Dir, providersFilename)
+	filename := file.GetFilename(path)
+
+	hclMap, err := toHclMap(args.Tags, args.TagExpressions)
+	if err != nil {
+		return nil, err
+	}
+
+	terratag := common.TerratagLocal{
+		Found:        map[string]hclwrite.Tokens{},
+		Added:        hclMap,
+		AddedTagSets: map[string]string{},
+	}
+
+	hcl := hclwrite.NewEmptyFile()
+
+	var swappedTagsStrings []string
+
+	for _, providerName := range missing {
+		log.Print("[INFO] No provider ", providerName, " block found in ", args.Dir, ", creating one in ", path)
+
+		provider := hcl.Body().AppendNewBlock("provider", []string{providerName})
+
+		result, err := tagProvider(provider, filename, terratag, args)
+		if err != nil {
+			return nil, err
+		}
+
+		perFileCounters.addResource(providerResult(providerName, nil, ResourceTagged))
+
+		swappedTagsStrings = append(swappedTagsStrings, result.SwappedTagsStrings...)
+	}
+
+	convert.AppendLocalsBlock(hcl, filename, terratag)
+
+	swappedTagsStrings = append(swappedTagsStrings, terratag.Added)
+	for _, added := range terratag.AddedTagSets {
+		swappedTagsStrings = append(swappedTagsStrings, added)
+	}
+	text := convert.UnquoteTagsAttribute(swappedTagsStrings, string(hcl.Bytes()))
+
+	if args.DryRun {
+		diff, err := file.UnifiedDiff(path, text, false)
+		if err != nil {
+			return nil, err
+		}
+
+		perFileCounters.diffs = append(perFileCounters.diffs, *diff)
+	} else if err := file.CreateFile(path, text); err != nil {
+		return nil, err
+	}
+
+	perFileCounters.totalFiles = 1
+	perFileCounters.taggedFiles = 1
+	perFileCounters.files = []FileResult{newFileResult(path, FileTagged, perFileCounters.resources)}
+
+	return &perFileCounters, nil
+}
+
+// writeTagVariables generates the Terraform variables of the required tags without a value into every directory
+// with tagged files, and an example variable definitions file into the root directory.
+// In dry-run mode the diffs of the generated files are returned instead.
+func writeTagVariables(args *common.TaggingArgs, specs []standards.TagSpec, taggedPaths []string) ([]file.FileDiff, error) {
+	variablesText, err := standards.GenerateVariablesFile(specs)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := map[string]bool{}
+	for _, path := range taggedPaths {
+		dirs[filepath.Dir(path)] = true
+	}
+
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+
+	rootDir, err := filepath.EvalSymlinks(args.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	generated := map[string]string{
+		filepath.Join(args.Dir, standards.VariablesExampleFileName): standards.GenerateVariablesExample(specs),
+	}
+
+	for dir := range dirs {
+		// The callers of a module can't set variables they don't declare, the run would prompt for them
+		if dir != rootDir && isChildModule(args, dir) {
+			return nil, fmt.Errorf("failed to generate tag variables into module %s: set a default_value, examples or allowed_values for the required tags or exclude the module with -skip, then run terratag revert", dir)
+		}
+
+		generated[filepath.Join(dir, standards.VariablesFileName)] = variablesText
+	}
+
+	paths := utils.SortObjectKeys(generated)
+
+	var diffs []file.FileDiff
+
+	for _, path := range paths {
+		if args.DryRun {
+			diff, err := file.UnifiedDiff(path, generated[path], false)
+			if err != nil {
+				return nil, err
+			}
+
+			if diff.Diff != "" {
+				diffs = append(diffs, *diff)
+			}
+		} else if err := file.CreateFile(path, generated[path]); err != nil {
+			return nil, err
+		}
+	}
+
+	return diffs, nil
+}
+
+// isChildModule returns whether a directory is only instantiated as a module of the root module. Directories
+// that aren't installed modules, e.g. the stacks of a terragrunt run, are root modules of their own.
+func isChildModule(args *common.TaggingArgs, dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+
+	addresses := args.ModuleAddresses[abs]
+
+	return len(addresses) > 0 && !slices.Contains(addresses, "")
+}
+
+// toJSONTagsMap decodes the input tags for the JSON syntax, which has no raw expressions:
+// expression values are validated and written as "${...}" templates, template sequences of literal values are escaped
+func toJSONTagsMap(tags string, expressions map[string]bool) (map[string]string, error) {
+	tagsMap, err := toTagsMap(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range tagsMap {
+		if !expressions[key] {
+			tagsMap[key] = convert.LiteralToJSONTemplate(value)
+
+			continue
+		}
+
+		if err := standards.ValidateTagExpression(value); err != nil {
+			return nil, fmt.Errorf("invalid expression for tag '%s': %w", key, err)
+		}
+
+		tagsMap[key] = convert.ExpressionToJSONTemplate(value)
+	}
+
+	return tagsMap, nil
+}
+
+// toTagsMap decodes the input tags, given either as JSON or as "key1=value1,key2=value2" pairs
+func toTagsMap(tags string) (map[string]string, error) {
 	var tagsMap map[string]string
 
 	if err := json.Unmarshal([]byte(tags), &tagsMap); err != nil {
@@ -430,20 +1246,45 @@ func toHclMap(tags string) (string, error) {
 		for _, pair := range pairs {
 			match := pairRegex.FindStringSubmatch(pair)
 			if match == nil {
-				return "", fmt.Errorf("invalid input tags! must be a valid JSON or pairs of key=value.\nInput: %s", tags)
+				return nil, fmt.Errorf("invalid input tags! must be a valid JSON or pairs of key=value.\nInput: %s", tags)
 			}
 
 			tagsMap[match[1]] = match[2]
 		}
 	}
 
+	return tagsMap, nil
+}
+
+// toHclMap renders the tags as an HCL object, values of the keys marked in expressions are emitted unquoted
+func toHclMap(tags string, expressions map[string]bool) (string, error) {
+	tagsMap, err := toTagsMap(tags)
+	if err != nil {
+		return "", err
+	}
+
 	keys := utils.SortObjectKeys(tagsMap)
 
 	mapContent := []string{}
 
 	for _, key := range keys {
-		mapContent = append(mapContent, "\""+key+"\"="+"\""+tagsMap[key]+"\"")
+		if expressions[key] {
+			if err := standards.ValidateTagExpression(tagsMap[key]); err != nil {
+				return "", fmt.Errorf("invalid expression for tag '%s': %w", key, err)
+			}
+
+			mapContent = append(mapContent, hclString(key)+"="+strings.TrimSpace(tagsMap[key]))
+
+			continue
+		}
+
+		mapContent = append(mapContent, hclString(key)+"="+hclString(tagsMap[key]))
 	}
 
 	return "{" + strings.Join(mapContent, ",") + "}", nil
 }
+
+// hclString quotes a literal value, escaping quotes, backslashes and template sequences
+func hclString(value string) string {
+	return string(hclwrite.TokensForValue(cty.StringVal(value)).Bytes())
+}
@@ -2,13 +2,16 @@ package terratag
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
@@ -16,6 +19,8 @@ import (
 	"github.com/bmatcuk/doublestar"
 	"github.com/cloudyali/terratag/cli"
 	"github.com/cloudyali/terratag/internal/common"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	. "github.com/onsi/gomega"
 	"github.com/otiai10/copy"
 	"github.com/spf13/viper"
@@ -417,7 +422,9 @@ func run_terratag(entryDir string, filter string, skip string, iacType common.IA
 		return err
 	}
 
-	return Terratag(args)
+	_, err = Terratag(context.Background(), args)
+
+	return err
 }
 
 func run(prog string, entryDir string, args ...string) error {
@@ -481,7 +488,7 @@ func TestToHclMap(t *testing.T) {
 	for input, output := range validCases {
 		input, expectedOutput := input, output
 		t.Run("valid input "+input, func(t *testing.T) {
-			output, err := toHclMap(input)
+			output, err := toHclMap(input, nil)
 			require.NoError(t, err)
 			assert.Equal(t, expectedOutput, output)
 		})
@@ -498,12 +505,50 @@ func TestToHclMap(t *testing.T) {
 	for i := range invalidCases {
 		input := invalidCases[i]
 		t.Run("invalid input "+input, func(t *testing.T) {
-			_, err := toHclMap(input)
+			_, err := toHclMap(input, nil)
 			assert.Error(t, err)
 		})
 	}
 }
 
+func TestToHclMapExpressions(t *testing.T) {
+	tags := `{"Environment":"var.environment","Owner":"\"${local.team}@corp.com\"","Team":"platform"}`
+	expressions := map[string]bool{"Environment": true, "Owner": true}
+
+	output, err := toHclMap(tags, expressions)
+	require.NoError(t, err)
+	assert.Equal(t, `{"Environment"=var.environment,"Owner"="${local.team}@corp.com","Team"="platform"}`, output)
+
+	_, err = toHclMap(`{"Environment":"var."}`, map[string]bool{"Environment": true})
+	assert.Error(t, err)
+}
+
+func TestToHclMapEscapesLiterals(t *testing.T) {
+	tags := `{"Owner":"team \"core\"","Path":"C:\\infra","Stack":"${var.stack}-%{if true}x%{endif}"}`
+
+	output, err := toHclMap(tags, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"Owner"="team \"core\"","Path"="C:\\infra","Stack"="$${var.stack}-%%{if true}x%%{endif}"}`, output)
+
+	// The generated locals must parse and evaluate to the literal values
+	expr, diags := hclsyntax.ParseExpression([]byte(output), "", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+
+	value, diags := expr.Value(nil)
+	require.False(t, diags.HasErrors(), diags.Error())
+	assert.Equal(t, `team "core"`, value.GetAttr("Owner").AsString())
+	assert.Equal(t, `C:\infra`, value.GetAttr("Path").AsString())
+	assert.Equal(t, "${var.stack}-%{if true}x%{endif}", value.GetAttr("Stack").AsString())
+}
+
+func TestToJSONTagsMapEscapesLiterals(t *testing.T) {
+	tags := `{"Environment":"var.environment","Stack":"${var.stack}"}`
+
+	output, err := toJSONTagsMap(tags, map[string]bool{"Environment": true})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Environment": "${var.environment}", "Stack": "$${var.stack}"}, output)
+}
+
 func TestEnvVariables(t *testing.T) {
 	os.Setenv("TERRATAG_TAGS", "test-tags.yaml")
 	os.Setenv("TERRATAG_DIR", "./dir")
@@ -551,3 +596,105 @@ func TestEnvVariables(t *testing.T) {
 
 	assert.Equal(t, "other-tags.yaml", args.TagsFile)
 }
+
+func TestProviderStrategyValidation(t *testing.T) {
+	useFakeSchemaCommand(t, "terraform")
+
+	standard := `
+version: 1
+metadata:
+  description: "Provider strategy test standard"
+cloud_provider: "aws"
+required_tags:
+  - key: "Owner"
+    default_value: "platform"
+  - key: "Team"
+    default_value: "storage"
+optional_tags:
+  - key: "Project"
+`
+
+	tests := []struct {
+		name     string
+		config   string
+		provider string
+	}{
+		{
+			name:     "generated provider",
+			config:   "resource \"aws_s3_bucket\" \"logs\" {\n  bucket = \"logs\"\n}\n",
+			provider: "terratag_providers.terratag.tf",
+		},
+		{
+			name:     "existing provider default tags",
+			config:   "resource \"aws_s3_bucket\" \"logs\" {\n  bucket = \"logs\"\n}\n\nprovider \"aws\" {\n  default_tags {\n    tags = {\n      Project = \"logs\"\n    }\n  }\n}\n",
+			provider: "main.tf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.Mkdir(filepath.Join(dir, ".terraform"), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(tt.config), 0644))
+
+			standardFile := filepath.Join(t.TempDir(), "standard.yaml")
+			require.NoError(t, os.WriteFile(standardFile, []byte(standard), 0644))
+
+			_, err := Terratag(context.Background(), cli.Args{
+				TagsFile:            standardFile,
+				Dir:                 dir,
+				Type:                string(common.Terraform),
+				Strategy:            string(common.StrategyProvider),
+				IsSkipTerratagFiles: true,
+				NoProviderCache:     true,
+			})
+			require.NoError(t, err)
+
+			provider, err := os.ReadFile(filepath.Join(dir, tt.provider))
+			require.NoError(t, err)
+			require.Contains(t, string(provider), "local.terratag_added_", "the default tags are written as a terratag local")
+
+			reportFile := filepath.Join(t.TempDir(), "report.json")
+
+			_, err = Terratag(context.Background(), cli.Args{
+				ValidateOnly:        true,
+				StandardFile:        standardFile,
+				Dir:                 dir,
+				Type:                string(common.Terraform),
+				IsSkipTerratagFiles: true,
+				NoProviderCache:     true,
+				ReportFormat:        "json",
+				ReportOutput:        reportFile,
+			})
+			require.NoError(t, err)
+
+			data, err := os.ReadFile(reportFile)
+			require.NoError(t, err)
+
+			var report struct {
+				TotalResources     int `json:"total_resources"`
+				CompliantResources int `json:"compliant_resources"`
+			}
+			require.NoError(t, json.Unmarshal(data, &report))
+
+			assert.Equal(t, 1, report.TotalResources)
+			assert.Equal(t, 1, report.CompliantResources, "the bucket gets the tags of the provider default tags:\n%s", data)
+		})
+	}
+}
+
+// useFakeSchemaCommand puts a fake terraform or terragrunt command printing the provider schema of aws_s3_bucket
+// first in the PATH of a test
+func useFakeSchemaCommand(t *testing.T, name string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake " + name + " is a shell script")
+	}
+
+	schema := `{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/aws":{"resource_schemas":{"aws_s3_bucket":{"block":{"attributes":{"tags":{"type":["map","string"],"optional":true}}}}}}}}`
+
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, name), []byte("#!/bin/sh\necho '"+schema+"'\n"), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}